@@ -1,16 +1,36 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/automate-podcast/internal/cli"
 )
 
+// exitCodeInterrupted is the conventional exit code of a process stopped by SIGINT
+const exitCodeInterrupted = 130
+
 func main() {
+	// SIGINT/SIGTERM でコンテキストをキャンセルし、実行中の API 呼び出しを中断する
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		// 2 回目のシグナルでは即座に終了する（入力待ちのプロンプトなど）
+		stop()
+		fmt.Fprintln(os.Stderr, "\nInterrupted, cancelling... (press Ctrl-C again to quit immediately)")
+	}()
+
 	// ルートコマンドの作成と実行
 	rootCmd := cli.NewRootCmd()
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+			fmt.Println("cancelled")
+			os.Exit(exitCodeInterrupted)
+		}
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -3,51 +3,170 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
-// Config holds all configuration values
+// Config holds all configuration values.
+// The env and desc tags document the environment variable of each field, e.g. for the init command.
 type Config struct {
-	OpenAIAPIKey        string
-	Art19Username       string
-	Art19Password       string
-	TwitterAPIKey       string
-	TwitterAPISecret    string
-	TwitterAccessToken  string
-	TwitterAccessSecret string
-	VercelDeployHook    string
-	UploadDir           string
-	Port                string
+	OpenAIAPIKey        string `env:"OPENAI_API_KEY" desc:"OpenAI API key for content generation and transcription"`
+	OpenAIBaseURL       string `env:"OPENAI_BASE_URL" desc:"Optional OpenAI-compatible endpoint or Azure OpenAI resource URL (default: the public OpenAI API)"`
+	AzureDeployment     string `env:"AZURE_OPENAI_DEPLOYMENT" desc:"Azure OpenAI chat deployment used with an Azure OPENAI_BASE_URL (default: named after the model)"`
+	WhisperDeployment   string `env:"AZURE_OPENAI_WHISPER_DEPLOYMENT" desc:"Azure OpenAI Whisper deployment used by transcribe with an Azure OPENAI_BASE_URL (default: whisper-1)"`
+	Art19Username       string `env:"ART19_USERNAME" desc:"Art19 login used by the Playwright upload scripts"`
+	Art19Password       string `env:"ART19_PASSWORD" desc:"Art19 password used by the Playwright upload scripts"`
+	TwitterAPIKey       string `env:"TWITTER_API_KEY" desc:"Twitter/X API key for step4 --post"`
+	TwitterAPISecret    string `env:"TWITTER_API_SECRET" desc:"Twitter/X API secret for step4 --post"`
+	TwitterAccessToken  string `env:"TWITTER_ACCESS_TOKEN" desc:"Twitter/X access token for step4 --post"`
+	TwitterAccessSecret string `env:"TWITTER_ACCESS_SECRET" desc:"Twitter/X access token secret for step4 --post"`
+	MastodonInstanceURL string `env:"MASTODON_INSTANCE_URL" desc:"Mastodon instance URL for step4 --mastodon, e.g. https://mastodon.social"`
+	MastodonAccessToken string `env:"MASTODON_ACCESS_TOKEN" desc:"Mastodon access token for step4 --mastodon"`
+	BlueskyIdentifier   string `env:"BLUESKY_IDENTIFIER" desc:"Bluesky handle for step4 --bluesky, e.g. you.bsky.social"`
+	BlueskyAppPassword  string `env:"BLUESKY_APP_PASSWORD" desc:"Bluesky app password for step4 --bluesky"`
+	BlueskyPDS          string `env:"BLUESKY_PDS_URL" desc:"Bluesky PDS URL (default: https://bsky.social)"`
+	LinkedInAccessToken string `env:"LINKEDIN_ACCESS_TOKEN" desc:"LinkedIn access token with the w_member_social scope for step4 --linkedin"`
+	LinkedInAuthorURN   string `env:"LINKEDIN_AUTHOR_URN" desc:"LinkedIn author of the posts for step4 --linkedin, e.g. urn:li:person:abc123"`
+	SpotifyClientID     string `env:"SPOTIFY_CLIENT_ID" desc:"Optional Spotify Web API client ID, used instead of scraping the show page"`
+	SpotifyClientSecret string `env:"SPOTIFY_CLIENT_SECRET" desc:"Optional Spotify Web API client secret"`
+	SpotifyMarket       string `env:"SPOTIFY_MARKET" desc:"Spotify market for the Web API (default: JP)"`
+	SNSHeader           string `env:"SNS_HEADER" desc:"Header line of the SNS post"`
+	SNSHashtags         string `env:"SNS_HASHTAGS" desc:"Hashtags appended to the SNS post"`
+	SNSHostHandle       string `env:"SNS_HOST_HANDLE" desc:"Host handle mentioned in the SNS post"`
+	VercelDeployHook    string `env:"VERCEL_DEPLOY_HOOK" desc:"Vercel deploy hook URL for step3; separate several hooks with commas"`
+	VercelToken         string `env:"VERCEL_TOKEN" desc:"Optional Vercel API token, required by step3 --wait"`
+	VercelProjectID     string `env:"VERCEL_PROJECT_ID" desc:"Optional Vercel project ID, required by step3 --wait"`
+	NetlifyBuildHook    string `env:"NETLIFY_BUILD_HOOK" desc:"Netlify build hook URL, an alternative to Vercel for step3"`
+	DiscordWebhookURL   string `env:"DISCORD_WEBHOOK_URL" desc:"Optional Discord webhook notified when step2 creates a draft and step3 redeploys"`
+	SlackWebhookURL     string `env:"SLACK_WEBHOOK_URL" desc:"Optional Slack incoming webhook notified when process run or process all completes"`
+	RSSFeedURL          string `env:"RSS_FEED_URL" desc:"Podcast RSS feed URL, used for episode numbers and step4"`
+	SpotifyShowURL      string `env:"SPOTIFY_SHOW_URL" desc:"Spotify show URL for step4"`
+	ApplePodcastURL     string `env:"APPLE_PODCAST_URL" desc:"Apple Podcasts show URL for step4"`
+	HTTPTimeout         string `env:"HTTP_TIMEOUT" desc:"Timeout of outbound HTTP requests as a duration, e.g. 45s (default: 30s; transcription and generation allow at least 10m and 5m)"`
+	UploadDir           string `env:"UPLOAD_DIR" desc:"Directory for uploaded files (default: uploads)"`
+	Port                string `env:"PORT" desc:"Port of the HTTP server (default: 8080)"`
+	ServeToken          string `env:"SERVE_TOKEN" desc:"Shared secret that serve requests must send as an Authorization: Bearer token; required to listen on other interfaces than localhost"`
+	Show                string `env:"PODCAST_SHOW" desc:"Name of the show profile to use from the shows: map of the config file, like --show"`
 }
 
-// LoadConfig loads configuration from environment variables
-func LoadConfig() (*Config, error) {
-	// Load .env file if it exists
+// Feature groups of configuration values, so each command only requires what it uses
+const (
+	FeatureOpenAI    = "openai"
+	FeatureArt19     = "art19"
+	FeatureVercel    = "vercel"
+	FeatureVercelAPI = "vercel-api" // Vercel REST API, used to follow deployments
+	FeatureNetlify   = "netlify"
+	FeatureSNS       = "sns"
+	FeatureTwitter   = "twitter"
+	FeatureMastodon  = "mastodon"
+	FeatureBluesky   = "bluesky"
+	FeatureLinkedIn  = "linkedin"
+)
+
+// defaultFeatures are validated when LoadConfig is called without feature groups
+var defaultFeatures = []string{FeatureOpenAI, FeatureArt19, FeatureTwitter, FeatureVercel}
+
+// source is the config file and show profile that Load resolves
+var source struct {
+	file string
+	show string
+}
+
+// SetSource sets the config file (default: DefaultConfigFile, if it exists) and the show profile
+// (default: PODCAST_SHOW or the file's podcast_show key) that Load resolves, e.g. from the
+// --config and --show flags
+func SetSource(file, show string) {
+	source.file = file
+	source.show = show
+}
+
+// Load resolves the configuration from .env, the environment, the config file and its active
+// show profile without validating required values. The active profile wins over .env and the
+// environment, which win over the top-level keys of the config file.
+func Load() (*Config, error) {
+	// Load .env first so that it, like the environment, takes precedence over the config file
 	if err := godotenv.Load(); err != nil {
-		logrus.Warn("No .env file found, using environment variables")
+		logrus.Debug("No .env file found, using environment variables")
+	}
+
+	var err error
+	if source.file != "" {
+		err = LoadFile(source.file, source.show)
+	} else {
+		err = LoadDefaultFile(source.show)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadEnvConfig(), nil
+}
+
+// LoadConfig resolves the configuration like Load and validates the values required by the
+// given feature groups (default: OpenAI, Art19, Twitter and Vercel)
+func LoadConfig(features ...string) (*Config, error) {
+	config, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate required configuration
+	if len(features) == 0 {
+		features = defaultFeatures
+	}
+	if err := config.ValidateFor(features...); err != nil {
+		return nil, err
 	}
 
-	config := &Config{
+	return config, nil
+}
+
+// LoadEnvConfig reads configuration from environment variables without validating required values.
+// Commands that only need some of the values use this, after the root command has run Load,
+// and check what they need themselves.
+func LoadEnvConfig() *Config {
+	return &Config{
 		OpenAIAPIKey:        getEnv("OPENAI_API_KEY", ""),
+		OpenAIBaseURL:       getEnv("OPENAI_BASE_URL", ""),
+		AzureDeployment:     getEnv("AZURE_OPENAI_DEPLOYMENT", ""),
+		WhisperDeployment:   getEnv("AZURE_OPENAI_WHISPER_DEPLOYMENT", ""),
 		Art19Username:       getEnv("ART19_USERNAME", ""),
 		Art19Password:       getEnv("ART19_PASSWORD", ""),
 		TwitterAPIKey:       getEnv("TWITTER_API_KEY", ""),
 		TwitterAPISecret:    getEnv("TWITTER_API_SECRET", ""),
 		TwitterAccessToken:  getEnv("TWITTER_ACCESS_TOKEN", ""),
 		TwitterAccessSecret: getEnv("TWITTER_ACCESS_SECRET", ""),
+		MastodonInstanceURL: getEnv("MASTODON_INSTANCE_URL", ""),
+		MastodonAccessToken: getEnv("MASTODON_ACCESS_TOKEN", ""),
+		BlueskyIdentifier:   getEnv("BLUESKY_IDENTIFIER", ""),
+		BlueskyAppPassword:  getEnv("BLUESKY_APP_PASSWORD", ""),
+		BlueskyPDS:          getEnv("BLUESKY_PDS_URL", "https://bsky.social"),
+		LinkedInAccessToken: getEnv("LINKEDIN_ACCESS_TOKEN", ""),
+		LinkedInAuthorURN:   getEnv("LINKEDIN_AUTHOR_URN", ""),
+		SpotifyClientID:     getEnv("SPOTIFY_CLIENT_ID", ""),
+		SpotifyClientSecret: getEnv("SPOTIFY_CLIENT_SECRET", ""),
+		SpotifyMarket:       getEnv("SPOTIFY_MARKET", "JP"),
+		SNSHeader:           getEnv("SNS_HEADER", ""),
+		SNSHashtags:         getEnv("SNS_HASHTAGS", ""),
+		SNSHostHandle:       getEnv("SNS_HOST_HANDLE", ""),
 		VercelDeployHook:    getEnv("VERCEL_DEPLOY_HOOK", ""),
+		VercelToken:         getEnv("VERCEL_TOKEN", ""),
+		VercelProjectID:     getEnv("VERCEL_PROJECT_ID", ""),
+		NetlifyBuildHook:    getEnv("NETLIFY_BUILD_HOOK", ""),
+		DiscordWebhookURL:   getEnv("DISCORD_WEBHOOK_URL", ""),
+		SlackWebhookURL:     getEnv("SLACK_WEBHOOK_URL", ""),
+		RSSFeedURL:          getEnv("RSS_FEED_URL", ""),
+		SpotifyShowURL:      getEnv("SPOTIFY_SHOW_URL", ""),
+		ApplePodcastURL:     getEnv("APPLE_PODCAST_URL", ""),
+		HTTPTimeout:         getEnv("HTTP_TIMEOUT", ""),
 		UploadDir:           getEnv("UPLOAD_DIR", "uploads"),
 		Port:                getEnv("PORT", "8080"),
+		ServeToken:          getEnv("SERVE_TOKEN", ""),
+		Show:                getEnv("PODCAST_SHOW", ""),
 	}
-
-	// Validate required configuration
-	if err := validateConfig(config); err != nil {
-		return nil, err
-	}
-
-	return config, nil
 }
 
 // getEnv gets an environment variable with a default value
@@ -58,23 +177,77 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-// validateConfig checks if all required configuration values are set
-func validateConfig(config *Config) error {
-	required := map[string]string{
-		"OPENAI_API_KEY":       config.OpenAIAPIKey,
-		"ART19_USERNAME":       config.Art19Username,
-		"ART19_PASSWORD":       config.Art19Password,
-		"TWITTER_API_KEY":      config.TwitterAPIKey,
-		"TWITTER_API_SECRET":   config.TwitterAPISecret,
-		"TWITTER_ACCESS_TOKEN": config.TwitterAccessToken,
-		"VERCEL_DEPLOY_HOOK":   config.VercelDeployHook,
+// required returns the environment variables required by a feature group and their values
+func (c *Config) required(feature string) (map[string]string, error) {
+	switch feature {
+	case FeatureOpenAI:
+		return map[string]string{"OPENAI_API_KEY": c.OpenAIAPIKey}, nil
+	case FeatureArt19:
+		return map[string]string{
+			"ART19_USERNAME": c.Art19Username,
+			"ART19_PASSWORD": c.Art19Password,
+		}, nil
+	case FeatureVercel:
+		return map[string]string{"VERCEL_DEPLOY_HOOK": c.VercelDeployHook}, nil
+	case FeatureVercelAPI:
+		return map[string]string{
+			"VERCEL_TOKEN":      c.VercelToken,
+			"VERCEL_PROJECT_ID": c.VercelProjectID,
+		}, nil
+	case FeatureNetlify:
+		return map[string]string{"NETLIFY_BUILD_HOOK": c.NetlifyBuildHook}, nil
+	case FeatureSNS:
+		return map[string]string{
+			"RSS_FEED_URL":      c.RSSFeedURL,
+			"SPOTIFY_SHOW_URL":  c.SpotifyShowURL,
+			"APPLE_PODCAST_URL": c.ApplePodcastURL,
+		}, nil
+	case FeatureTwitter:
+		return map[string]string{
+			"TWITTER_API_KEY":       c.TwitterAPIKey,
+			"TWITTER_API_SECRET":    c.TwitterAPISecret,
+			"TWITTER_ACCESS_TOKEN":  c.TwitterAccessToken,
+			"TWITTER_ACCESS_SECRET": c.TwitterAccessSecret,
+		}, nil
+	case FeatureMastodon:
+		return map[string]string{
+			"MASTODON_INSTANCE_URL": c.MastodonInstanceURL,
+			"MASTODON_ACCESS_TOKEN": c.MastodonAccessToken,
+		}, nil
+	case FeatureBluesky:
+		return map[string]string{
+			"BLUESKY_IDENTIFIER":   c.BlueskyIdentifier,
+			"BLUESKY_APP_PASSWORD": c.BlueskyAppPassword,
+		}, nil
+	case FeatureLinkedIn:
+		return map[string]string{
+			"LINKEDIN_ACCESS_TOKEN": c.LinkedInAccessToken,
+			"LINKEDIN_AUTHOR_URN":   c.LinkedInAuthorURN,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown configuration feature %q", feature)
 	}
+}
 
-	for key, value := range required {
-		if value == "" {
-			return fmt.Errorf("required environment variable %s is not set", key)
+// ValidateFor checks that all values required by the given feature groups are set
+func (c *Config) ValidateFor(features ...string) error {
+	var missing []string
+	for _, feature := range features {
+		required, err := c.required(feature)
+		if err != nil {
+			return err
+		}
+		for key, value := range required {
+			if value == "" {
+				missing = append(missing, key)
+			}
 		}
 	}
 
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("required environment variables are not set: %s", strings.Join(missing, ", "))
+	}
+
 	return nil
 }
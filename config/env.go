@@ -0,0 +1,33 @@
+package config
+
+import "reflect"
+
+// EnvVar describes an environment variable read by the CLI
+type EnvVar struct {
+	Name        string
+	Description string
+}
+
+// commandEnvVars are environment variables read directly by commands rather than through Config
+var commandEnvVars = []EnvVar{
+	{"ANTHROPIC_API_KEY", "Anthropic API key for step1 --provider anthropic"},
+	{"PROMPT_TEMPLATE", "Prompt template file for step1 (text/template with {{.Transcript}}, {{.EpisodeNumber}}, {{.Hosts}}, {{.Language}})"},
+	{"ART19_EPISODE_NEW_URL", "Art19 URL of the new episode form used by step2"},
+	{"SNS_TEMPLATE", "text/template file for the step4 post text"},
+	{"YOUTUBE_CHANNEL_URL", "Optional YouTube channel URL for step4 (https://www.youtube.com/channel/UC...)"},
+}
+
+// EnvVars returns every environment variable of Config in field order, from the env and
+// desc struct tags, followed by the ones that commands read directly
+func EnvVars() []EnvVar {
+	configType := reflect.TypeOf(Config{})
+
+	vars := make([]EnvVar, 0, configType.NumField()+len(commandEnvVars))
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		if name := field.Tag.Get("env"); name != "" {
+			vars = append(vars, EnvVar{Name: name, Description: field.Tag.Get("desc")})
+		}
+	}
+	return append(vars, commandEnvVars...)
+}
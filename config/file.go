@@ -0,0 +1,231 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFileName is the config file looked up in the home directory when no path is given
+const DefaultConfigFileName = ".aipodflow.yaml"
+
+// fileKeys maps config file keys to the environment variables they set
+var fileKeys = map[string]string{
+	"openai_api_key":                  "OPENAI_API_KEY",
+	"openai_base_url":                 "OPENAI_BASE_URL",
+	"azure_openai_deployment":         "AZURE_OPENAI_DEPLOYMENT",
+	"azure_openai_whisper_deployment": "AZURE_OPENAI_WHISPER_DEPLOYMENT",
+	"anthropic_api_key":               "ANTHROPIC_API_KEY",
+	"prompt_template":                 "PROMPT_TEMPLATE",
+	"art19_username":                  "ART19_USERNAME",
+	"art19_password":                  "ART19_PASSWORD",
+	"art19_episode_new_url":           "ART19_EPISODE_NEW_URL",
+	"twitter_api_key":                 "TWITTER_API_KEY",
+	"twitter_api_secret":              "TWITTER_API_SECRET",
+	"twitter_access_token":            "TWITTER_ACCESS_TOKEN",
+	"twitter_access_secret":           "TWITTER_ACCESS_SECRET",
+	"mastodon_instance_url":           "MASTODON_INSTANCE_URL",
+	"mastodon_access_token":           "MASTODON_ACCESS_TOKEN",
+	"bluesky_identifier":              "BLUESKY_IDENTIFIER",
+	"bluesky_app_password":            "BLUESKY_APP_PASSWORD",
+	"bluesky_pds_url":                 "BLUESKY_PDS_URL",
+	"linkedin_access_token":           "LINKEDIN_ACCESS_TOKEN",
+	"linkedin_author_urn":             "LINKEDIN_AUTHOR_URN",
+	"spotify_client_id":               "SPOTIFY_CLIENT_ID",
+	"spotify_client_secret":           "SPOTIFY_CLIENT_SECRET",
+	"spotify_market":                  "SPOTIFY_MARKET",
+	"sns_template":                    "SNS_TEMPLATE",
+	"sns_header":                      "SNS_HEADER",
+	"sns_hashtags":                    "SNS_HASHTAGS",
+	"sns_host_handle":                 "SNS_HOST_HANDLE",
+	"vercel_deploy_hook":              "VERCEL_DEPLOY_HOOK",
+	"vercel_token":                    "VERCEL_TOKEN",
+	"vercel_project_id":               "VERCEL_PROJECT_ID",
+	"netlify_build_hook":              "NETLIFY_BUILD_HOOK",
+	"discord_webhook_url":             "DISCORD_WEBHOOK_URL",
+	"slack_webhook_url":               "SLACK_WEBHOOK_URL",
+	"rss_feed_url":                    "RSS_FEED_URL",
+	"spotify_show_url":                "SPOTIFY_SHOW_URL",
+	"apple_podcast_url":               "APPLE_PODCAST_URL",
+	"youtube_channel_url":             "YOUTUBE_CHANNEL_URL",
+	"http_timeout":                    "HTTP_TIMEOUT",
+	"upload_dir":                      "UPLOAD_DIR",
+	"port":                            "PORT",
+	"serve_token":                     "SERVE_TOKEN",
+	"podcast_show":                    "PODCAST_SHOW",
+}
+
+// DefaultConfigFile returns the path of the config file in the home directory
+func DefaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DefaultConfigFileName)
+}
+
+// showsKey is the config file key holding the named show profiles
+const showsKey = "shows"
+
+// LoadFile reads a YAML config file and sets the environment variables for its keys.
+// Variables that are already set are left alone, so the environment wins over the top-level keys.
+//
+// The file may define several shows under "shows:", each a map of the same keys for one podcast.
+// The show named by show (or else PODCAST_SHOW or the file's podcast_show key) is the active
+// profile: its keys take precedence over the environment and the top-level keys, so that a
+// selected show never runs with another show's feed or credentials left in .env.
+func LoadFile(path, show string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	shows, err := parseShows(values[showsKey])
+	if err != nil {
+		return fmt.Errorf("invalid %q in config file %s: %w", showsKey, path, err)
+	}
+	delete(values, showsKey)
+
+	// Check all keys before applying any so a typo doesn't leave a half-applied config
+	if err := checkFileKeys(values); err != nil {
+		return fmt.Errorf("unknown keys in config file %s: %w", path, err)
+	}
+	for name, show := range shows {
+		if err := checkFileKeys(show); err != nil {
+			return fmt.Errorf("unknown keys in show %q of config file %s: %w", name, path, err)
+		}
+		if _, ok := show["podcast_show"]; ok {
+			return fmt.Errorf("show %q of config file %s can't set podcast_show", name, path)
+		}
+	}
+
+	// Resolve the active show; --show and then the environment win over the file's podcast_show
+	active := show
+	if active == "" {
+		active = os.Getenv("PODCAST_SHOW")
+	}
+	if active == "" && values["podcast_show"] != nil {
+		active = fmt.Sprint(values["podcast_show"])
+	}
+	if active != "" {
+		profile, ok := shows[active]
+		if !ok {
+			return fmt.Errorf("show %q is not defined in config file %s; defined shows: %v", active, path, showNames(shows))
+		}
+		if err := applyShowValues(active, profile); err != nil {
+			return err
+		}
+		if err := os.Setenv("PODCAST_SHOW", active); err != nil {
+			return fmt.Errorf("failed to select show %q: %w", active, err)
+		}
+	}
+
+	return applyFileValues(values)
+}
+
+// parseShows converts the "shows:" value of a config file into a map of show names to their keys
+func parseShows(value interface{}) (map[string]map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map of show names to their settings")
+	}
+
+	shows := make(map[string]map[string]interface{}, len(entries))
+	for name, entry := range entries {
+		show, ok := entry.(map[string]interface{})
+		if !ok && entry != nil {
+			return nil, fmt.Errorf("show %q: expected a map of settings", name)
+		}
+		shows[name] = show
+	}
+	return shows, nil
+}
+
+// checkFileKeys returns an error listing the keys that are not config file keys
+func checkFileKeys(values map[string]interface{}) error {
+	var unknown []string
+	for key := range values {
+		if _, ok := fileKeys[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%v", unknown)
+	}
+	return nil
+}
+
+// applyFileValues sets the environment variables of config file keys that are not set yet
+func applyFileValues(values map[string]interface{}) error {
+	for key, value := range values {
+		envKey := fileKeys[key]
+		if value == nil || os.Getenv(envKey) != "" {
+			continue
+		}
+		if err := os.Setenv(envKey, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("failed to set %s from config file: %w", envKey, err)
+		}
+	}
+	return nil
+}
+
+// applyShowValues sets the environment variables of a show profile's keys, overriding the
+// values already set by the environment or .env
+func applyShowValues(show string, values map[string]interface{}) error {
+	for key, value := range values {
+		if value == nil {
+			continue
+		}
+		envKey := fileKeys[key]
+		profileValue := fmt.Sprint(value)
+		if current := os.Getenv(envKey); current != "" && current != profileValue {
+			logrus.Warnf("Show %q overrides %s from the environment", show, envKey)
+		}
+		if err := os.Setenv(envKey, profileValue); err != nil {
+			return fmt.Errorf("failed to set %s from show %q: %w", envKey, show, err)
+		}
+	}
+	return nil
+}
+
+// showNames returns the sorted names of the shows in a config file
+func showNames(shows map[string]map[string]interface{}) []string {
+	names := make([]string, 0, len(shows))
+	for name := range shows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadDefaultFile loads the config file in the home directory if it exists, like LoadFile
+func LoadDefaultFile(show string) error {
+	path := DefaultConfigFile()
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if show == "" {
+			show = os.Getenv("PODCAST_SHOW")
+		}
+		if show != "" {
+			return fmt.Errorf("show %q is selected but there is no config file %s defining it; pass --config", show, path)
+		}
+		return nil
+	}
+	return LoadFile(path, show)
+}
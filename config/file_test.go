@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfigFile = `openai_api_key: shared-key
+rss_feed_url: https://example.com/top/feed.xml
+podcast_show: momitfm
+
+shows:
+  momitfm:
+    rss_feed_url: https://example.com/momitfm/feed.xml
+    art19_username: momitfm-user
+  another-show:
+    rss_feed_url: https://example.com/another/feed.xml
+`
+
+// writeConfigFile writes a config file into a temporary directory
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "aipodflow.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clearEnv sets the given variables for the test, restoring them afterwards
+func clearEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{"OPENAI_API_KEY", "RSS_FEED_URL", "ART19_USERNAME", "PODCAST_SHOW"} {
+		t.Setenv(key, env[key])
+	}
+}
+
+func TestLoadFileShowPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		show     string
+		env      map[string]string
+		wantShow string
+		wantRSS  string
+		wantUser string
+		wantKey  string
+	}{
+		{
+			name:     "file default show",
+			wantShow: "momitfm",
+			wantRSS:  "https://example.com/momitfm/feed.xml",
+			wantUser: "momitfm-user",
+			wantKey:  "shared-key",
+		},
+		{
+			name:     "selected show wins over the file default",
+			show:     "another-show",
+			wantShow: "another-show",
+			wantRSS:  "https://example.com/another/feed.xml",
+			wantKey:  "shared-key",
+		},
+		{
+			name:     "selected show wins over PODCAST_SHOW",
+			show:     "another-show",
+			env:      map[string]string{"PODCAST_SHOW": "momitfm"},
+			wantShow: "another-show",
+			wantRSS:  "https://example.com/another/feed.xml",
+			wantKey:  "shared-key",
+		},
+		{
+			name:     "PODCAST_SHOW wins over the file default",
+			env:      map[string]string{"PODCAST_SHOW": "another-show"},
+			wantShow: "another-show",
+			wantRSS:  "https://example.com/another/feed.xml",
+			wantKey:  "shared-key",
+		},
+		{
+			name:     "profile keys win over the environment",
+			show:     "another-show",
+			env:      map[string]string{"RSS_FEED_URL": "https://example.com/dotenv/feed.xml", "ART19_USERNAME": "env-user"},
+			wantShow: "another-show",
+			wantRSS:  "https://example.com/another/feed.xml",
+			wantUser: "env-user",
+			wantKey:  "shared-key",
+		},
+		{
+			name:     "environment wins over top-level keys",
+			show:     "momitfm",
+			env:      map[string]string{"OPENAI_API_KEY": "env-key"},
+			wantShow: "momitfm",
+			wantRSS:  "https://example.com/momitfm/feed.xml",
+			wantUser: "momitfm-user",
+			wantKey:  "env-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t, tt.env)
+			if err := LoadFile(writeConfigFile(t, testConfigFile), tt.show); err != nil {
+				t.Fatalf("LoadFile() error = %v", err)
+			}
+
+			cfg := LoadEnvConfig()
+			if cfg.Show != tt.wantShow {
+				t.Errorf("Show = %q, want %q", cfg.Show, tt.wantShow)
+			}
+			if cfg.RSSFeedURL != tt.wantRSS {
+				t.Errorf("RSSFeedURL = %q, want %q", cfg.RSSFeedURL, tt.wantRSS)
+			}
+			if cfg.Art19Username != tt.wantUser {
+				t.Errorf("Art19Username = %q, want %q", cfg.Art19Username, tt.wantUser)
+			}
+			if cfg.OpenAIAPIKey != tt.wantKey {
+				t.Errorf("OpenAIAPIKey = %q, want %q", cfg.OpenAIAPIKey, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestLoadFileWithoutShows(t *testing.T) {
+	clearEnv(t, map[string]string{"RSS_FEED_URL": "https://example.com/env/feed.xml"})
+	path := writeConfigFile(t, "openai_api_key: file-key\nrss_feed_url: https://example.com/file/feed.xml\n")
+	if err := LoadFile(path, ""); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	cfg := LoadEnvConfig()
+	if cfg.RSSFeedURL != "https://example.com/env/feed.xml" || cfg.OpenAIAPIKey != "file-key" {
+		t.Errorf("LoadEnvConfig() = %+v, want the environment's RSS feed and the file's API key", cfg)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		show    string
+		wantErr string
+	}{
+		{name: "unknown show", content: testConfigFile, show: "missing", wantErr: "defined shows: [another-show momitfm]"},
+		{name: "unknown top-level key", content: "rss_feed: x\n", wantErr: "unknown keys"},
+		{name: "unknown show key", content: "shows:\n  a:\n    rss: x\n", show: "a", wantErr: `unknown keys in show "a"`},
+		{name: "show sets podcast_show", content: "shows:\n  a:\n    podcast_show: b\n", show: "a", wantErr: "can't set podcast_show"},
+		{name: "shows is not a map", content: "shows: [a, b]\n", wantErr: `invalid "shows"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t, nil)
+			err := LoadFile(writeConfigFile(t, tt.content), tt.show)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFile() error = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadResolvesSelectedShow(t *testing.T) {
+	clearEnv(t, map[string]string{"RSS_FEED_URL": "https://example.com/dotenv/feed.xml"})
+	SetSource(writeConfigFile(t, testConfigFile), "another-show")
+	t.Cleanup(func() { SetSource("", "") })
+
+	cfg, err := LoadConfig(FeatureOpenAI)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Show != "another-show" || cfg.RSSFeedURL != "https://example.com/another/feed.xml" {
+		t.Errorf("LoadConfig() show = %q, RSS feed = %q, want the another-show profile", cfg.Show, cfg.RSSFeedURL)
+	}
+}
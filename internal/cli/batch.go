@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/automate-podcast/internal/model"
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/internal/ui"
+	"github.com/automate-podcast/services"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// DefaultBatchRequestsPerMinute is the default cap on OpenAI calls per minute across a batch
+const DefaultBatchRequestsPerMinute = 20
+
+// batchTranscriptExtensions are the transcript file extensions picked up from the input directory
+var batchTranscriptExtensions = map[string]bool{".txt": true, ".srt": true, ".vtt": true}
+
+// episodeNumberPrefixPattern matches the episode number at the start of a transcript file name, e.g. "042-ai-parenting.txt"
+var episodeNumberPrefixPattern = regexp.MustCompile(`^(\d+)`)
+
+// batchResult is the outcome of generating content for one transcript
+type batchResult struct {
+	Name  string
+	Title string
+	Err   error
+}
+
+// NewBatchCmd creates a command that runs step1 content generation for a directory of transcripts
+func NewBatchCmd() *cobra.Command {
+	var inputDir string
+	var outputDir string
+	var openAIKey string
+	var openAIBaseURL string
+	var concurrency int
+	var requestsPerMinute int
+	var skipExisting bool
+	var numTitles int
+	var language string
+	var hosts []string
+	var promptTemplateFile string
+	var maxTranscriptTokens int
+	var maxRetries int
+	var noCache bool
+	var cacheDir string
+
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Generate titles and show notes for a directory of transcripts",
+		Long: `Run step1 content generation for every transcript (.txt, .srt, .vtt) in a directory,
+e.g. a back-catalog of episodes. Each episode's candidates and selected content (the first
+candidates, as with --non-interactive) are saved to a subfolder of the output directory named
+after the transcript. Episodes are processed concurrently within a shared requests-per-minute
+cap, and a failed episode doesn't stop the others.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			// Get OpenAI API key from flag or environment
+			if openAIKey == "" {
+				openAIKey = os.Getenv("OPENAI_API_KEY")
+				if openAIKey == "" {
+					return fmt.Errorf("OpenAI API key is required. Set it with --openai-key flag or OPENAI_API_KEY environment variable")
+				}
+			}
+			if openAIBaseURL == "" {
+				openAIBaseURL = os.Getenv("OPENAI_BASE_URL")
+			}
+
+			if concurrency <= 0 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+			if requestsPerMinute < 0 {
+				return fmt.Errorf("--requests-per-minute must be 0 or greater")
+			}
+			if _, err := services.LanguageName(language); err != nil {
+				return fmt.Errorf("invalid --language: %w", err)
+			}
+
+			// Load a custom prompt template from flag or environment
+			if promptTemplateFile == "" {
+				promptTemplateFile = os.Getenv("PROMPT_TEMPLATE")
+			}
+			var promptTemplate string
+			if promptTemplateFile != "" {
+				var err error
+				promptTemplate, err = processor.LoadPromptTemplate(promptTemplateFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			transcripts, err := findBatchTranscripts(inputDir)
+			if err != nil {
+				return err
+			}
+			if len(transcripts) == 0 {
+				return fmt.Errorf("no transcripts (.txt, .srt, .vtt) found in %s", inputDir)
+			}
+			logger.Infof("Found %d transcripts in %s", len(transcripts), inputDir)
+
+			// Every episode shares one rate limit
+			limiter := services.NewRequestLimiter(requestsPerMinute)
+			generate := func(ctx context.Context, transcriptPath, episodeDir string, logger *logrus.Logger) (string, error) {
+				aiService := services.NewAIService(openAIKey, logger)
+				aiService.SetBaseURL(openAIBaseURL, os.Getenv("AZURE_OPENAI_DEPLOYMENT"))
+				aiService.MaxTranscriptTokens = maxTranscriptTokens
+				aiService.MaxRetries = maxRetries
+				aiService.CacheDir = cacheDir
+				aiService.Limiter = limiter
+
+				var usage services.Usage
+				opts := services.GenerateOptions{
+					NumTitles:      numTitles,
+					PromptTemplate: promptTemplate,
+					EpisodeNumber:  episodeNumberFromFileName(transcriptPath),
+					Hosts:          hosts,
+					OnUsage:        usage.Add,
+					NoCache:        noCache,
+					Language:       language,
+				}
+				return generateBatchEpisode(ctx, aiService, transcriptPath, episodeDir, opts, &usage, logger)
+			}
+
+			// Process the transcripts with at most --concurrency running at a time
+			results := make([]batchResult, len(transcripts))
+			sem := make(chan struct{}, concurrency)
+			var wg sync.WaitGroup
+			for i, transcriptPath := range transcripts {
+				name := strings.TrimSuffix(filepath.Base(transcriptPath), filepath.Ext(transcriptPath))
+				episodeDir := filepath.Join(outputDir, name)
+				results[i].Name = name
+
+				if skipExisting {
+					if _, err := os.Stat(filepath.Join(episodeDir, processor.SelectedContentFileName)); err == nil {
+						logger.Infof("Skipping %s: %s already exists (--skip-existing)", name, processor.SelectedContentFileName)
+						results[i].Title = "(skipped)"
+						continue
+					}
+				}
+
+				select {
+				case sem <- struct{}{}:
+				case <-cmd.Context().Done():
+					results[i].Err = cmd.Context().Err()
+					continue
+				}
+				wg.Add(1)
+				go func(i int, transcriptPath, episodeDir string) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					episodeLogger := taggedLogger(logger, "episode", results[i].Name)
+					results[i].Title, results[i].Err = generate(cmd.Context(), transcriptPath, episodeDir, episodeLogger)
+					if results[i].Err != nil {
+						episodeLogger.Errorf("Failed: %v", results[i].Err)
+					}
+				}(i, transcriptPath, episodeDir)
+			}
+			wg.Wait()
+
+			// Report a summary of all episodes
+			failed := 0
+			fmt.Println()
+			for _, result := range results {
+				if result.Err != nil {
+					failed++
+					fmt.Printf("FAIL %s: %v\n", result.Name, result.Err)
+				} else {
+					fmt.Printf("OK   %s: %s\n", result.Name, result.Title)
+				}
+			}
+			fmt.Printf("\n%d succeeded, %d failed\n", len(results)-failed, failed)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d episodes failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVarP(&inputDir, "input-dir", "i", "", "Directory of transcripts: plain text, SRT or VTT (required)")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Directory for the per-episode output subfolders (required)")
+	cmd.Flags().StringVar(&openAIKey, "openai-key", "", "OpenAI API key (can also be set via OPENAI_API_KEY environment variable)")
+	cmd.Flags().StringVar(&openAIBaseURL, "openai-base-url", "", "OpenAI-compatible endpoint or Azure OpenAI resource URL (can also be set via OPENAI_BASE_URL environment variable)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 2, "Number of episodes processed at the same time")
+	cmd.Flags().IntVar(&requestsPerMinute, "requests-per-minute", DefaultBatchRequestsPerMinute, "Maximum number of OpenAI calls per minute across all episodes (0 for no limit)")
+	cmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "Skip episodes whose output subfolder already has selected content, e.g. when re-running after failures")
+	cmd.Flags().IntVar(&numTitles, "num-titles", services.DefaultNumTitles, "Number of title candidates to generate")
+	cmd.Flags().StringVar(&language, "language", services.DefaultLanguage, "Language of the generated titles and show notes as an ISO-639-1 code (e.g. ja, en)")
+	cmd.Flags().StringSliceVar(&hosts, "hosts", nil, "Comma-separated host handles for the prompt's credits")
+	cmd.Flags().StringVar(&promptTemplateFile, "prompt-template", "", "Prompt template file (can also be set via PROMPT_TEMPLATE environment variable)")
+	cmd.Flags().IntVar(&maxTranscriptTokens, "max-transcript-tokens", services.DefaultMaxTranscriptTokens, "Transcripts longer than this many estimated tokens are chunked and summarized first")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", services.DefaultMaxRetries, "Number of retries for OpenAI rate-limit and server errors")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Call OpenAI even if a cached response exists for the same transcript and prompt")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", services.DefaultCacheDir(), "Directory for cached OpenAI responses (empty disables caching)")
+
+	// Set required flags
+	if err := cmd.MarkFlagRequired("input-dir"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+	if err := cmd.MarkFlagRequired("output-dir"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return cmd
+}
+
+// findBatchTranscripts returns the transcript files in a directory, sorted by name
+func findBatchTranscripts(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !batchTranscriptExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// episodeNumberFromFileName returns the episode number a transcript file name starts with, or 0
+func episodeNumberFromFileName(path string) int {
+	m := episodeNumberPrefixPattern.FindStringSubmatch(filepath.Base(path))
+	if m == nil {
+		return 0
+	}
+	number, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// generateBatchEpisode generates candidates for one transcript, selects the first ones and saves
+// them to episodeDir. It returns the selected title.
+func generateBatchEpisode(ctx context.Context, generator services.ContentGenerator, transcriptPath, episodeDir string, opts services.GenerateOptions, usage *services.Usage, logger *logrus.Logger) (string, error) {
+	// Load the transcript, with speaker names for labeled transcripts as in step1
+	loadedTranscript, err := processor.LoadTranscript(transcriptPath, logger)
+	if err != nil {
+		return "", fmt.Errorf("failed to load transcript: %w", err)
+	}
+	transcript := loadedTranscript.Text
+	if len(loadedTranscript.Turns) > 0 {
+		transcript = services.FormatTurns(loadedTranscript.Turns)
+		opts.Speakers = services.TurnSpeakers(loadedTranscript.Turns)
+	}
+	if strings.TrimSpace(transcript) == "" {
+		return "", fmt.Errorf("transcript %s is empty", transcriptPath)
+	}
+
+	// Generate the candidates
+	contentProcessor := processor.NewContentProcessor(generator, logger)
+	candidates, err := contentProcessor.GenerateCandidates(ctx, transcript, true, opts)
+	if err != nil {
+		return "", fmt.Errorf("content generation failed: %w", err)
+	}
+
+	// Select the first candidates
+	selector := ui.NewInteractiveUI(logger)
+	selector.NonInteractive = true
+	selected, err := selector.SelectContent(candidates)
+	if err != nil {
+		return "", err
+	}
+
+	if err := saveBatchEpisode(episodeDir, candidates, selected, usage); err != nil {
+		return "", err
+	}
+	logger.Infof("Saved to %s", episodeDir)
+	return selected.Title, nil
+}
+
+// saveBatchEpisode writes the candidates, selected content and token usage of an episode
+// using the same file names as step1
+func saveBatchEpisode(episodeDir string, candidates *model.ContentCandidates, selected *model.SelectedContent, usage *services.Usage) error {
+	if err := os.MkdirAll(episodeDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := processor.SaveCandidates(filepath.Join(episodeDir, processor.CandidatesFileName), candidates); err != nil {
+		return fmt.Errorf("failed to save candidates: %w", err)
+	}
+	if err := processor.SaveSelectedContent(filepath.Join(episodeDir, processor.SelectedContentFileName), selected); err != nil {
+		return fmt.Errorf("failed to save selected content: %w", err)
+	}
+	legacyPath := filepath.Join(episodeDir, processor.LegacySelectedContentFileName)
+	if err := os.WriteFile(legacyPath, []byte(processor.FormatSelectedContent(selected)), 0644); err != nil {
+		return fmt.Errorf("failed to save selected content: %w", err)
+	}
+	if usage.Calls > 0 {
+		data, err := json.MarshalIndent(usage, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode usage: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(episodeDir, "usage.json"), append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to save usage: %w", err)
+		}
+	}
+	return nil
+}
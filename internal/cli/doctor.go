@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/automate-podcast/config"
+	"github.com/automate-podcast/services"
+	"github.com/spf13/cobra"
+)
+
+// doctorCheck is a credential check run by the doctor command
+type doctorCheck struct {
+	name     string
+	features []string // Configuration the check needs; it is skipped when any of it is missing
+	run      func(ctx context.Context) (string, error)
+}
+
+// NewDoctorCmd creates a command that verifies the configured credentials against each service
+func NewDoctorCmd() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the configured credentials work",
+		Long:  `Verify each configured credential with a cheap request to its service and print a pass/fail line per service. Nothing is posted or deployed.`,
+		// Failed checks are already reported line by line
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			cfg := config.LoadEnvConfig()
+
+			checks := []doctorCheck{
+				{
+					name:     "OpenAI",
+					features: []string{config.FeatureOpenAI},
+					run: func(ctx context.Context) (string, error) {
+						aiService := services.NewAIService(cfg.OpenAIAPIKey, logger)
+						aiService.SetBaseURL(cfg.OpenAIBaseURL, cfg.AzureDeployment)
+						return "API key accepted", aiService.VerifyCredentials(ctx)
+					},
+				},
+				{
+					name:     "Art19 (Playwright MCP server)",
+					features: []string{config.FeatureArt19},
+					run: func(ctx context.Context) (string, error) {
+						return "MCP server reachable", services.NewArt19Service(cfg.Art19Username, cfg.Art19Password, logger).Ping(ctx)
+					},
+				},
+				{
+					name:     "Vercel deploy hook",
+					features: []string{config.FeatureVercel},
+					run: func(ctx context.Context) (string, error) {
+						return "deploy hooks found", services.NewVercelServiceFromEnv(logger).CheckDeployHooks(ctx)
+					},
+				},
+				{
+					name:     "Vercel API",
+					features: []string{config.FeatureVercelAPI},
+					run: func(ctx context.Context) (string, error) {
+						return "token can read the project's deployments", services.NewVercelServiceFromEnv(logger).CheckAPIAccess(ctx)
+					},
+				},
+				{
+					name:     "Twitter/X",
+					features: []string{config.FeatureTwitter},
+					run: func(ctx context.Context) (string, error) {
+						twitterService := services.NewTwitterService(cfg.TwitterAPIKey, cfg.TwitterAPISecret, cfg.TwitterAccessToken, cfg.TwitterAccessSecret, logger)
+						username, err := twitterService.VerifyCredentials(ctx)
+						return "authenticated as @" + username, err
+					},
+				},
+			}
+
+			failed := 0
+			for _, check := range checks {
+				if err := cfg.ValidateFor(check.features...); err != nil {
+					fmt.Printf("-    %s: skipped (%v)\n", check.name, err)
+					continue
+				}
+
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				detail, err := check.run(ctx)
+				cancel()
+				if err != nil {
+					failed++
+					fmt.Printf("FAIL %s: %v\n", check.name, err)
+					continue
+				}
+				fmt.Printf("OK   %s: %s\n", check.name, detail)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d credential checks failed", failed)
+			}
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().DurationVar(&timeout, "timeout", 15*time.Second, "Timeout for each check")
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/automate-podcast/config"
+	"github.com/automate-podcast/services"
+	"github.com/spf13/cobra"
+)
+
+// Files written by the init command
+const (
+	initEnvFile            = ".env.example"
+	initPromptTemplateFile = "prompt-template.txt"
+)
+
+// NewInitCmd creates a command that scaffolds a commented .env.example and a starter prompt template
+func NewInitCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a .env.example and prompt template",
+		Long:  `Write a commented .env.example listing every environment variable and a starter prompt-template.txt to the current directory.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			files := []struct {
+				path    string
+				content string
+			}{
+				{initEnvFile, envExample()},
+				{initPromptTemplateFile, services.DefaultPromptTemplate + "\n"},
+			}
+
+			// Check all files first so nothing is written when one of them already exists
+			if !force {
+				for _, file := range files {
+					if _, err := os.Stat(file.path); err == nil {
+						return fmt.Errorf("%s already exists (use --force to overwrite)", file.path)
+					} else if !errors.Is(err, os.ErrNotExist) {
+						return fmt.Errorf("failed to check %s: %w", file.path, err)
+					}
+				}
+			}
+
+			for _, file := range files {
+				if err := os.WriteFile(file.path, []byte(file.content), 0644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", file.path, err)
+				}
+				logger.Infof("Created %s", file.path)
+			}
+
+			logger.Infof("Next: copy %s to .env, fill in the values you need, and set PROMPT_TEMPLATE=%s to customize the prompt", initEnvFile, initPromptTemplateFile)
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+
+	return cmd
+}
+
+// envExample renders every environment variable with its description as a commented .env file
+func envExample() string {
+	var sb strings.Builder
+	sb.WriteString("# podcast-cli configuration\n")
+	sb.WriteString("# Copy this file to .env and fill in the values for the steps you use.\n")
+	for _, envVar := range config.EnvVars() {
+		fmt.Fprintf(&sb, "\n# %s\n%s=\n", envVar.Description, envVar.Name)
+	}
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Log formats accepted by --log-format
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// logFormat is the value of the persistent --log-format flag, shared by every command
+// including the steps that run executes
+var logFormat = LogFormatText
+
+// verboseLogging is the value of the persistent --verbose flag
+var verboseLogging bool
+
+// loggerKey is the context key of the logger initialized by the root command
+type loggerKey struct{}
+
+// withLogger returns a copy of ctx carrying the logger
+func withLogger(ctx context.Context, logger *logrus.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// loggerFromContext returns the logger initialized by the root command, or a new one
+// from the --verbose and --log-format flags when the command runs without the root
+func loggerFromContext(ctx context.Context) *logrus.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*logrus.Logger); ok {
+			return logger
+		}
+	}
+	return newLogger(verboseLogging, logFormat)
+}
+
+// validateLogFormat checks the --log-format value
+func validateLogFormat(format string) error {
+	switch format {
+	case LogFormatText, LogFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --log-format %q: must be %s or %s", format, LogFormatText, LogFormatJSON)
+	}
+}
+
+// newLogger creates the logger used by a command, at debug level when verbose is set
+func newLogger(verbose bool, format string) *logrus.Logger {
+	logger := logrus.New()
+	if verbose {
+		logger.SetLevel(logrus.DebugLevel)
+	} else {
+		logger.SetLevel(logrus.InfoLevel)
+	}
+
+	if format == LogFormatJSON {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+	}
+	return logger
+}
+
+// taggedLogger creates a logger with the output, level and format of base that adds
+// a field to every entry, e.g. the request ID in serve or the episode in batch
+func taggedLogger(base *logrus.Logger, key, value string) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(base.Out)
+	logger.SetLevel(base.GetLevel())
+	logger.SetFormatter(&fieldFormatter{Formatter: base.Formatter, key: key, value: value})
+	return logger
+}
+
+// fieldFormatter adds a fixed field to every entry
+type fieldFormatter struct {
+	logrus.Formatter
+	key   string
+	value string
+}
+
+// Format adds the field and formats the entry with the wrapped formatter
+func (f *fieldFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+1)
+	for key, value := range entry.Data {
+		data[key] = value
+	}
+	data[f.key] = f.value
+	entry.Data = data
+	return f.Formatter.Format(entry)
+}
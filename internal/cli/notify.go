@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/automate-podcast/config"
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/services"
+	"github.com/sirupsen/logrus"
+)
+
+// notify sends a message to the configured team chat webhooks. Notifications are optional,
+// so a failure is only logged and never fails the step.
+func notify(ctx context.Context, cfg *config.Config, message string, logger *logrus.Logger) {
+	if cfg.DiscordWebhookURL == "" {
+		return
+	}
+
+	notifyService := services.NewNotifyService(logger)
+	if err := notifyService.SendDiscord(ctx, cfg.DiscordWebhookURL, message); err != nil {
+		logger.Warnf("Failed to send Discord notification: %v", err)
+		return
+	}
+	logger.Info("Discord notification sent")
+}
+
+// episodeMessage formats a notification about an episode with its title and Art19 URL, if known
+func episodeMessage(headline, title, art19URL string) string {
+	lines := []string{headline}
+	if title != "" {
+		lines = append(lines, fmt.Sprintf("**%s**", title))
+	}
+	if art19URL != "" {
+		lines = append(lines, art19URL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notifyPipelineCompleted posts a summary of the finished pipeline to Slack, with the episode
+// title and links found in the output directory, whose step1 files have the given --output-prefix.
+// Like notify, it never fails the pipeline.
+func notifyPipelineCompleted(ctx context.Context, outputDir, outputPrefix string, logger *logrus.Logger) {
+	cfg := config.LoadEnvConfig()
+	if cfg.SlackWebhookURL == "" {
+		return
+	}
+
+	// Collect the episode title and links saved by the steps; the SNS post has the title when step1's file doesn't
+	var title string
+	var links []string
+	if outputDir != "" {
+		if content, err := processor.LoadSelectedContent(processor.OutputPath(outputDir, outputPrefix, processor.SelectedContentFileName), logger); err == nil {
+			title = content.Title
+		}
+		if art19URL := processor.LoadArt19EpisodeURL(outputDir); art19URL != "" {
+			links = append(links, slackLink("Art19 draft", art19URL))
+		}
+		if post, err := processor.LoadSNSPost(filepath.Join(outputDir, processor.SNSPostJSONFileName)); err == nil {
+			if title == "" {
+				title = post.Title
+			}
+			links = append(links, slackLink("Spotify", post.SpotifyURL), slackLink("Apple Podcasts", post.AppleURL))
+			if post.YouTubeURL != "" {
+				links = append(links, slackLink("YouTube", post.YouTubeURL))
+			}
+		}
+	}
+
+	lines := []string{":white_check_mark: Podcast pipeline completed"}
+	if title != "" {
+		lines = append(lines, fmt.Sprintf("*%s*", slackEscape(title)))
+	}
+	lines = append(lines, links...)
+
+	notifyService := services.NewNotifyService(logger)
+	if err := notifyService.SendSlack(ctx, cfg.SlackWebhookURL, strings.Join(lines, "\n")); err != nil {
+		logger.Warnf("Failed to send Slack notification: %v", err)
+		return
+	}
+	logger.Info("Slack notification sent")
+}
+
+// slackLink formats a labeled link as a Slack mrkdwn list item
+func slackLink(label, url string) string {
+	return fmt.Sprintf("• <%s|%s>", url, label)
+}
+
+// slackEscape escapes the characters that Slack mrkdwn uses for links and mentions
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
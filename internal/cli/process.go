@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/automate-podcast/internal/processor"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +22,7 @@ func NewProcessCmd() *cobra.Command {
 	processCmd.AddCommand(Step2Cmd())
 	processCmd.AddCommand(Step3Cmd())
 	processCmd.AddCommand(Step4Cmd())
+	processCmd.AddCommand(NewRunCmd())
 	
 	// Add the legacy command for backward compatibility
 	processCmd.AddCommand(NewLegacyProcessCmd())
@@ -33,11 +35,12 @@ func NewLegacyProcessCmd() *cobra.Command {
 	var inputTranscript string
 	var inputAudio string
 	var outputDir string
-	var verbose bool
 	var titlesOnly bool
 	var generateShowNotes bool
 	var skipUpload bool
 	var apiOnly bool
+	var metadataOut string
+	var nonInteractive bool
 
 	processCmd := &cobra.Command{
 		Use:   "all",
@@ -50,18 +53,21 @@ func NewLegacyProcessCmd() *cobra.Command {
 				"--input-transcript", inputTranscript,
 				"--output-dir", outputDir,
 			}
-			if verbose {
-				step1Args = append(step1Args, "--verbose")
-			}
 			if titlesOnly {
 				step1Args = append(step1Args, "--titles-only")
 			}
 			if !generateShowNotes {
 				step1Args = append(step1Args, "--gen-shownotes=false")
 			}
+			if metadataOut != "" {
+				step1Args = append(step1Args, "--metadata-out", metadataOut)
+			}
+			if nonInteractive {
+				step1Args = append(step1Args, "--non-interactive")
+			}
 			
 			step1Cmd.SetArgs(step1Args)
-			if err := step1Cmd.Execute(); err != nil {
+			if err := step1Cmd.ExecuteContext(cmd.Context()); err != nil {
 				return err
 			}
 			
@@ -73,22 +79,21 @@ func NewLegacyProcessCmd() *cobra.Command {
 			// Skip Art19 upload if requested
 			if !skipUpload && inputAudio != "" {
 				// Run step 2
-				selectedPath := filepath.Join(outputDir, "selected_content.txt")
+				selectedPath := filepath.Join(outputDir, processor.SelectedContentFileName)
 				step2Cmd := Step2Cmd()
 				step2Args := []string{
 					"--input-audio", inputAudio,
 					"--content-file", selectedPath,
-				}
-				if verbose {
-					step2Args = append(step2Args, "--verbose")
+					"--output-dir", outputDir,
 				}
 				
 				step2Cmd.SetArgs(step2Args)
-				if err := step2Cmd.Execute(); err != nil {
+				if err := step2Cmd.ExecuteContext(cmd.Context()); err != nil {
 					return err
 				}
 			}
-			
+
+			notifyPipelineCompleted(cmd.Context(), outputDir, "", loggerFromContext(cmd.Context()))
 			return nil
 		},
 	}
@@ -97,11 +102,12 @@ func NewLegacyProcessCmd() *cobra.Command {
 	processCmd.Flags().StringVarP(&inputTranscript, "input-transcript", "t", "", "Path to transcript file (required)")
 	processCmd.Flags().StringVarP(&inputAudio, "input-audio", "a", "", "Path to audio file (required)")
 	processCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Output directory for generated files")
-	processCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
 	processCmd.Flags().BoolVar(&titlesOnly, "titles-only", false, "Generate only titles, skip show notes")
 	processCmd.Flags().BoolVar(&generateShowNotes, "gen-shownotes", true, "Generate show notes (default: true)")
 	processCmd.Flags().BoolVar(&skipUpload, "skip-upload", false, "Skip uploading to Art19")
 	processCmd.Flags().BoolVar(&apiOnly, "api-only", false, "Stop after API call and display response")
+	processCmd.Flags().StringVar(&metadataOut, "metadata-out", "", "Episode metadata JSON file to create or update (optional)")
+	processCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Select the first candidates without prompting (for CI)")
 
 	// Set required flags
 	if err := processCmd.MarkFlagRequired("input-transcript"); err != nil {
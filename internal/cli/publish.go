@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/automate-podcast/config"
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/internal/ui"
+	"github.com/automate-podcast/services"
+	"github.com/spf13/cobra"
+)
+
+// NewPublishCmd creates a command that publishes an Art19 draft episode after confirmation
+func NewPublishCmd() *cobra.Command {
+	var episodeID string
+	var outputDir string
+	var outputPrefix string
+	var yes bool
+	var dryRun bool
+	var mcpTimeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Publish an Art19 draft episode",
+		Long: `Publish an Art19 draft episode created by step2, making it live.
+The episode must still be a draft. You are asked to confirm before it is published,
+unless --yes is given, so a draft is never published by accident.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			if err := validateOutputPrefix(outputPrefix, outputDir); err != nil {
+				return err
+			}
+
+			// Load configuration; only the Art19 credentials are required here
+			cfg, err := config.LoadConfig(config.FeatureArt19)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			// Use the episode saved by step2 unless one is given
+			if episodeID == "" && outputDir != "" {
+				episodeID = processor.LoadArt19EpisodeID(outputDir)
+			}
+			if episodeID == "" {
+				return fmt.Errorf("no Art19 episode to publish: set --episode-id or an --output-dir where step2 saved %s", processor.Art19EpisodeFileName)
+			}
+
+			art19Service := services.NewArt19Service(cfg.Art19Username, cfg.Art19Password, logger)
+			art19Service.SetTimeout(mcpTimeout)
+			art19Service.DryRun = dryRun
+
+			// Only drafts can be published
+			if !dryRun {
+				spinner := ui.StartSpinner(logger, "Checking the Art19 episode...")
+				status, err := art19Service.EpisodeStatus(cmd.Context(), episodeID)
+				spinner.Stop()
+				if err != nil {
+					logMCPHint(logger, err)
+					return err
+				}
+				switch status {
+				case services.Art19StatusDraft:
+				case services.Art19StatusPublished:
+					return fmt.Errorf("Art19 episode %s is already published", episodeID)
+				default:
+					return fmt.Errorf("Art19 episode %s is not a draft (status %q); check the episode ID", episodeID, status)
+				}
+			}
+
+			// Ask for confirmation, showing what is about to go live
+			if !yes && !dryRun {
+				fmt.Printf("Art19 episode: %s\n", episodeID)
+				if outputDir != "" {
+					if url := processor.LoadArt19EpisodeURL(outputDir); url != "" {
+						fmt.Printf("Draft URL:     %s\n", url)
+					}
+					if content, err := processor.LoadSelectedContent(processor.OutputPath(outputDir, outputPrefix, processor.SelectedContentFileName), logger); err == nil {
+						fmt.Printf("Title:         %s\n", content.Title)
+					}
+				}
+				confirmed, err := ui.NewInteractiveUI(logger).Confirm("Publish this episode? It will go live immediately.")
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					return fmt.Errorf("publishing was not confirmed, the episode is still a draft")
+				}
+			}
+
+			spinner := ui.StartSpinner(logger, "Publishing on Art19...")
+			episode, err := art19Service.PublishDraft(cmd.Context(), episodeID)
+			spinner.Stop()
+			if err != nil {
+				logMCPHint(logger, err)
+				return err
+			}
+			if dryRun {
+				logger.Info("Dry run: nothing was published")
+				return nil
+			}
+
+			if episode.URL != "" {
+				logger.Infof(">>> Published Art19 episode: %s", episode.URL)
+			} else {
+				logger.Infof(">>> Published Art19 episode %s", episode.ID)
+			}
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVar(&episodeID, "episode-id", "", "Art19 episode ID to publish (default: the episode step2 saved in --output-dir)")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Output directory where step2 saved the Art19 episode reference")
+	cmd.Flags().StringVar(&outputPrefix, "output-prefix", "", "Prefix of step1's file names in --output-dir, as given to step1's --output-prefix")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Publish without asking for confirmation")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the MCP payload without checking or publishing the episode")
+	cmd.Flags().DurationVar(&mcpTimeout, "mcp-timeout", services.DefaultMCPTimeout, "Timeout for each Playwright MCP browser automation call")
+
+	// Set required flags
+	cmd.MarkFlagsOneRequired("episode-id", "output-dir")
+
+	return cmd
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/automate-podcast/config"
+	"github.com/automate-podcast/internal/model"
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/services"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// queuePost adds a post to the local queue to be published by flush-queue when it is due
+func queuePost(queueFile, platform, text string, image *services.ShareImage, article *model.PostLink, scheduledAt time.Time, logger *logrus.Logger) error {
+	post := model.ScheduledPost{
+		Platform:    platform,
+		Text:        text,
+		Article:     article,
+		ScheduledAt: scheduledAt,
+	}
+	if image != nil {
+		// flush-queue may run from another directory
+		imagePath, err := filepath.Abs(image.Path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve share image path: %w", err)
+		}
+		post.ImagePath = imagePath
+		post.ImageAlt = image.AltText
+	}
+
+	queued, err := processor.EnqueuePost(queueFile, post)
+	if err != nil {
+		return fmt.Errorf("failed to queue %s post: %w", platform, err)
+	}
+	logger.Infof("Queued %s post %s for %s in %s", platform, queued.ID, scheduledAt.Format(time.RFC3339), queueFile)
+	return nil
+}
+
+// publishQueuedPost publishes a queued post to its platform and returns its URL, if known
+func publishQueuedPost(ctx context.Context, cfg *config.Config, post *model.ScheduledPost, logger *logrus.Logger) (string, error) {
+	var image *services.ShareImage
+	if post.ImagePath != "" {
+		image = &services.ShareImage{Path: post.ImagePath, AltText: post.ImageAlt}
+	}
+
+	switch post.Platform {
+	case model.PlatformTwitter:
+		if err := cfg.ValidateFor(config.FeatureTwitter); err != nil {
+			return "", err
+		}
+		twitterService := services.NewTwitterService(cfg.TwitterAPIKey, cfg.TwitterAPISecret, cfg.TwitterAccessToken, cfg.TwitterAccessSecret, logger)
+		tweetID, err := twitterService.PostTweet(ctx, post.Text, image)
+		if err != nil {
+			return "", err
+		}
+		return services.TweetURL(tweetID), nil
+	case model.PlatformMastodon:
+		if err := cfg.ValidateFor(config.FeatureMastodon); err != nil {
+			return "", err
+		}
+		return "", services.NewMastodonService(logger).PostStatus(ctx, cfg.MastodonInstanceURL, cfg.MastodonAccessToken, post.Text, image)
+	case model.PlatformBluesky:
+		if err := cfg.ValidateFor(config.FeatureBluesky); err != nil {
+			return "", err
+		}
+		return services.NewBlueskyService(cfg.BlueskyPDS, logger).PostText(ctx, cfg.BlueskyIdentifier, cfg.BlueskyAppPassword, post.Text, image)
+	case model.PlatformLinkedIn:
+		if err := cfg.ValidateFor(config.FeatureLinkedIn); err != nil {
+			return "", err
+		}
+		var article *services.LinkedInArticle
+		if post.Article != nil {
+			article = &services.LinkedInArticle{URL: post.Article.URL, Title: post.Article.Title, Description: post.Article.Description}
+		}
+		return services.NewLinkedInService(logger).PostShare(ctx, cfg.LinkedInAccessToken, cfg.LinkedInAuthorURN, post.Text, article)
+	default:
+		return "", fmt.Errorf("unknown platform %q", post.Platform)
+	}
+}
+
+// NewFlushQueueCmd creates a command for publishing the scheduled posts that are due
+func NewFlushQueueCmd() *cobra.Command {
+	var queueFile string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "flush-queue",
+		Short: "Publish scheduled posts that are due",
+		Long: `Publish the posts queued by step4 --schedule-at whose time has come, and remove them from the queue.
+Posts that fail stay in the queue and are retried on the next run. Run it periodically, e.g. from cron.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			queue, err := processor.LoadPostQueue(queueFile)
+			if err != nil {
+				return err
+			}
+			if len(queue.Posts) == 0 {
+				logger.Infof("No scheduled posts in %s", queueFile)
+				return nil
+			}
+
+			// Read SNS credentials; each platform checks the values it needs
+			cfg := config.LoadEnvConfig()
+
+			now := time.Now()
+			var remaining []model.ScheduledPost
+			published, failed := 0, 0
+			for i, post := range queue.Posts {
+				if post.ScheduledAt.After(now) {
+					logger.Debugf("Post %s is scheduled for %s", post.ID, post.ScheduledAt.Format(time.RFC3339))
+					remaining = append(remaining, post)
+					continue
+				}
+
+				if dryRun {
+					logger.Infof("Dry run: would publish %s post %s scheduled for %s:\n%s", post.Platform, post.ID, post.ScheduledAt.Format(time.RFC3339), post.Text)
+					remaining = append(remaining, post)
+					continue
+				}
+
+				logger.Infof("Publishing %s post %s scheduled for %s...", post.Platform, post.ID, post.ScheduledAt.Format(time.RFC3339))
+				postURL, err := publishQueuedPost(cmd.Context(), cfg, &post, logger)
+				if err != nil {
+					logger.Errorf("Failed to publish %s post %s: %v", post.Platform, post.ID, err)
+					post.Attempts++
+					post.LastError = err.Error()
+					remaining = append(remaining, post)
+					failed++
+				} else {
+					if postURL != "" {
+						logger.Infof("Posted to %s: %s", post.Platform, postURL)
+					}
+					published++
+				}
+
+				// Save after every post so a crash can't publish the same post twice
+				current := &model.PostQueue{Posts: append(append([]model.ScheduledPost{}, remaining...), queue.Posts[i+1:]...)}
+				if err := processor.SavePostQueue(queueFile, current); err != nil {
+					return err
+				}
+			}
+
+			if dryRun {
+				logger.Info("Dry run: nothing was published")
+				return nil
+			}
+
+			logger.Infof("Published %d posts, %d failed, %d left in %s", published, failed, len(remaining), queueFile)
+			if failed > 0 {
+				return fmt.Errorf("%d scheduled posts failed to publish", failed)
+			}
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVar(&queueFile, "queue-file", processor.DefaultPostQueueFile, "Queue of scheduled posts written by step4 --schedule-at")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the posts that are due without publishing them")
+
+	return cmd
+}
@@ -1,19 +1,86 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/automate-podcast/config"
+	"github.com/automate-podcast/services"
 	"github.com/spf13/cobra"
 )
 
 // NewRootCmd はルートコマンドを作成する
 func NewRootCmd() *cobra.Command {
+	var configFile string
+	var show string
+	var httpTimeout time.Duration
+
 	rootCmd := &cobra.Command{
 		Use:   "podcast-cli",
 		Short: "Podcast automation tool",
 		Long:  `A CLI tool for automating podcast production workflow with interactive content selection.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateLogFormat(logFormat); err != nil {
+				return err
+			}
+
+			// Initialize the logger once and share it with the subcommand through its context
+			cmd.SetContext(withLogger(cmd.Context(), newLogger(verboseLogging, logFormat)))
+
+			// 設定ファイルを読み込む (.env, the config file and the --show profile)
+			config.SetSource(configFile, show)
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if cfg.Show != "" {
+				loggerFromContext(cmd.Context()).Infof("Using show %q from the config file", cfg.Show)
+			}
+
+			// The flag takes precedence over HTTP_TIMEOUT from the environment or config file
+			if !cmd.Flags().Changed("http-timeout") {
+				timeout, err := httpTimeoutFromEnv()
+				if err != nil {
+					return err
+				}
+				httpTimeout = timeout
+			}
+			services.SetHTTPTimeout(httpTimeout)
+			return nil
+		},
 	}
 
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "YAML config file (default: ~/"+config.DefaultConfigFileName+"); environment variables take precedence over its top-level keys")
+	rootCmd.PersistentFlags().StringVar(&show, "show", "", "Show profile to use from the shows: map of the config file, overriding the environment (default: PODCAST_SHOW)")
+	rootCmd.PersistentFlags().BoolVarP(&verboseLogging, "verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", LogFormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().DurationVar(&httpTimeout, "http-timeout", 0, "Timeout of outbound HTTP requests, e.g. 45s (default: HTTP_TIMEOUT or 30s)")
+
 	// サブコマンドを追加
 	rootCmd.AddCommand(NewProcessCmd())
+	rootCmd.AddCommand(NewTranscribeCmd())
+	rootCmd.AddCommand(NewSummarizeCmd())
+	rootCmd.AddCommand(NewValidateTranscriptCmd())
+	rootCmd.AddCommand(NewBatchCmd())
+	rootCmd.AddCommand(NewPublishCmd())
+	rootCmd.AddCommand(NewFlushQueueCmd())
+	rootCmd.AddCommand(NewInitCmd())
+	rootCmd.AddCommand(NewDoctorCmd())
+	rootCmd.AddCommand(NewServeCmd())
 	
 	return rootCmd
 }
+
+// httpTimeoutFromEnv parses HTTP_TIMEOUT, returning 0 for the default timeout if it is not set
+func httpTimeoutFromEnv() (time.Duration, error) {
+	value := os.Getenv("HTTP_TIMEOUT")
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid HTTP_TIMEOUT %q: use a duration such as 45s or 2m", value)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/automate-podcast/internal/processor"
+	"github.com/spf13/cobra"
+)
+
+// NewRunCmd creates a command that runs steps 1 to 4 end to end
+func NewRunCmd() *cobra.Command {
+	var inputTranscript string
+	var inputAudio string
+	var outputDir string
+	var outputPrefix string
+	var nonInteractive bool
+	var metadataOut string
+	var skipArt19 bool
+	var skipVercel bool
+	var skipSNS bool
+	var deployWait time.Duration
+	var wait bool
+	var post bool
+	var mastodon bool
+	var bluesky bool
+	var linkedin bool
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run all steps end to end",
+		Long:  `Generate content (step1), upload it to Art19 (step2), redeploy the website on Vercel (step3) and create the SNS post (step4) in sequence.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			// Check the step inputs up front so the pipeline doesn't fail halfway
+			if !skipArt19 && inputAudio == "" {
+				return fmt.Errorf("--input-audio is required for the Art19 upload (or use --skip-art19)")
+			}
+			if err := validateOutputPrefix(outputPrefix, outputDir); err != nil {
+				return err
+			}
+
+			// runStep executes a step command, stopping the pipeline on its first error
+			runStep := func(name string, stepCmd *cobra.Command, stepArgs []string) error {
+				logger.Infof("=== Running %s ===", name)
+				stepCmd.SetArgs(stepArgs)
+				stepCmd.SilenceUsage = true
+				stepCmd.SilenceErrors = true
+				if err := stepCmd.ExecuteContext(cmd.Context()); err != nil {
+					logger.Errorf("%s failed: %v", name, err)
+					return fmt.Errorf("%s failed: %w", name, err)
+				}
+				return nil
+			}
+
+			// Step 1: generate and select content
+			step1Args := []string{
+				"--input-transcript", inputTranscript,
+				"--output-dir", outputDir,
+			}
+			if outputPrefix != "" {
+				step1Args = append(step1Args, "--output-prefix", outputPrefix)
+			}
+			if nonInteractive {
+				step1Args = append(step1Args, "--non-interactive")
+			}
+			if metadataOut != "" {
+				step1Args = append(step1Args, "--metadata-out", metadataOut)
+			}
+			if err := runStep("step1", Step1Cmd(), step1Args); err != nil {
+				return err
+			}
+
+			// Step 2: upload the selected content to Art19
+			if skipArt19 {
+				logger.Info("Skipping step2 (Art19 upload)")
+			} else {
+				step2Args := []string{
+					"--input-audio", inputAudio,
+					"--content-file", processor.OutputPath(outputDir, outputPrefix, processor.SelectedContentFileName),
+					"--output-dir", outputDir,
+				}
+				if metadataOut != "" {
+					step2Args = append(step2Args, "--metadata-out", metadataOut)
+				}
+				if err := runStep("step2", Step2Cmd(), step2Args); err != nil {
+					return err
+				}
+			}
+
+			// Step 3: redeploy the website and give the deployment time to finish
+			if skipVercel {
+				logger.Info("Skipping step3 (Vercel redeploy)")
+			} else {
+				step3Args := []string{"--output-dir", outputDir, "--output-prefix", outputPrefix}
+				if wait {
+					step3Args = append(step3Args, "--wait")
+				}
+				if err := runStep("step3", Step3Cmd(), step3Args); err != nil {
+					return err
+				}
+				if !skipSNS && !wait && deployWait > 0 {
+					logger.Infof("Waiting %s for the deployment to finish before creating the SNS post...", deployWait)
+					select {
+					case <-time.After(deployWait):
+					case <-cmd.Context().Done():
+						return cmd.Context().Err()
+					}
+				}
+			}
+
+			// Step 4: create and optionally publish the SNS post
+			if skipSNS {
+				logger.Info("Skipping step4 (SNS post)")
+			} else {
+				step4Args := []string{
+					"--output", filepath.Join(outputDir, processor.SNSPostFileName),
+					"--output-json", filepath.Join(outputDir, processor.SNSPostJSONFileName),
+				}
+				if metadataOut != "" {
+					step4Args = append(step4Args, "--metadata-out", metadataOut)
+				}
+				if post {
+					step4Args = append(step4Args, "--post")
+				}
+				if mastodon {
+					step4Args = append(step4Args, "--mastodon")
+				}
+				if bluesky {
+					step4Args = append(step4Args, "--bluesky")
+				}
+				if linkedin {
+					step4Args = append(step4Args, "--linkedin")
+				}
+				if err := runStep("step4", Step4Cmd(), step4Args); err != nil {
+					return err
+				}
+			}
+
+			notifyPipelineCompleted(cmd.Context(), outputDir, outputPrefix, logger)
+
+			logger.Info("All steps completed successfully!")
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVarP(&inputTranscript, "input-transcript", "t", "", "Path to transcript file (required)")
+	cmd.Flags().StringVarP(&inputAudio, "input-audio", "a", "", "Path to audio file (required unless --skip-art19 is set)")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "output", "Output directory for generated files")
+	cmd.Flags().StringVar(&outputPrefix, "output-prefix", "", "Prefix for the names of step1's files in --output-dir, e.g. ep42_ for ep42_selected_content.json")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Select the first candidates without prompting (for CI)")
+	cmd.Flags().StringVar(&metadataOut, "metadata-out", "", "Episode metadata JSON file to create or update (optional)")
+	cmd.Flags().BoolVar(&skipArt19, "skip-art19", false, "Skip the Art19 upload (step2)")
+	cmd.Flags().BoolVar(&skipVercel, "skip-vercel", false, "Skip the Vercel redeploy (step3)")
+	cmd.Flags().BoolVar(&skipSNS, "skip-sns", false, "Skip the SNS post (step4)")
+	cmd.Flags().DurationVar(&deployWait, "deploy-wait", time.Minute, "Time to wait after the Vercel redeploy before creating the SNS post")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait for the Vercel deployment to finish instead of a fixed --deploy-wait (requires VERCEL_TOKEN and VERCEL_PROJECT_ID)")
+	cmd.Flags().BoolVar(&post, "post", false, "Publish the SNS post to Twitter/X")
+	cmd.Flags().BoolVar(&mastodon, "mastodon", false, "Publish the SNS post to Mastodon")
+	cmd.Flags().BoolVar(&bluesky, "bluesky", false, "Publish the SNS post to Bluesky")
+	cmd.Flags().BoolVar(&linkedin, "linkedin", false, "Publish the SNS post to LinkedIn")
+
+	// Set required flags
+	if err := cmd.MarkFlagRequired("input-transcript"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return cmd
+}
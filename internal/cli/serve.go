@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/automate-podcast/config"
+	"github.com/automate-podcast/internal/model"
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/internal/ui"
+	"github.com/automate-podcast/services"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// DefaultMaxUploadSize is the default limit on the size of a serve request, audio included
+const DefaultMaxUploadSize = 500 << 20
+
+// DefaultServeHost is the address the serve command listens on by default, reachable only from this machine
+const DefaultServeHost = "127.0.0.1"
+
+// MaxServeNumTitles is the largest num_titles a serve request may ask for, so that one request
+// can't spend an unbounded amount of tokens and time while it holds the pipeline
+const MaxServeNumTitles = 20
+
+// episodeResponse is the JSON response of the serve command's episode endpoint
+type episodeResponse struct {
+	RequestID  string                   `json:"request_id"`
+	Candidates *model.ContentCandidates `json:"candidates"`
+	Selected   *model.SelectedContent   `json:"selected"`
+	EpisodeID  string                   `json:"art19_episode_id,omitempty"`
+	EpisodeURL string                   `json:"art19_episode_url,omitempty"`
+}
+
+// episodeServer runs the step1 to step2 pipeline for HTTP requests
+type episodeServer struct {
+	cfg           *config.Config
+	uploadDir     string
+	maxUploadSize int64
+	logger        *logrus.Logger
+
+	// token is the shared secret requests must send as a bearer token; empty disables the check
+	token string
+	// allowUpload enables Art19 uploads; without it requests only generate content
+	allowUpload bool
+
+	// art19Mu serializes Art19 uploads, which each drive a browser through the MCP server
+	art19Mu sync.Mutex
+}
+
+// NewServeCmd creates a command that serves the content pipeline over HTTP
+func NewServeCmd() *cobra.Command {
+	var host string
+	var port string
+	var uploadDir string
+	var maxUploadSize int64
+	var allowUpload bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the content pipeline over HTTP",
+		Long: `Start an HTTP server that accepts a transcript (and optionally audio) as a multipart POST to /episodes,
+generates the title and show note (step1), uploads them to Art19 (step2) and returns the result as JSON.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			// Content generation always needs OpenAI; Art19 only when uploads are allowed
+			cfg, err := config.LoadConfig(config.FeatureOpenAI)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if allowUpload {
+				if err := cfg.ValidateFor(config.FeatureArt19); err != nil {
+					return fmt.Errorf("cannot allow Art19 uploads: %w", err)
+				}
+			} else {
+				logger.Info("Art19 uploads are disabled; requests only generate content (use --allow-upload to enable them)")
+			}
+
+			// Requests spend OpenAI credits and may publish drafts, so other machines need the shared secret
+			if cfg.ServeToken == "" && !isLoopbackHost(host) {
+				return fmt.Errorf("set SERVE_TOKEN to listen on %s: without it, anyone who can reach the port can use the server", host)
+			}
+			if cfg.ServeToken == "" {
+				logger.Warn("SERVE_TOKEN is not set; requests are not authenticated")
+			}
+			if port == "" {
+				port = cfg.Port
+			}
+			if uploadDir == "" {
+				uploadDir = cfg.UploadDir
+			}
+			if err := os.MkdirAll(uploadDir, 0755); err != nil {
+				return fmt.Errorf("failed to create upload directory: %w", err)
+			}
+
+			server := &episodeServer{
+				cfg:           cfg,
+				uploadDir:     uploadDir,
+				maxUploadSize: maxUploadSize,
+				logger:        logger,
+				token:         cfg.ServeToken,
+				allowUpload:   allowUpload,
+			}
+			mux := http.NewServeMux()
+			mux.HandleFunc("/episodes", server.handleEpisode)
+			mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			httpServer := &http.Server{
+				Addr:              net.JoinHostPort(host, port),
+				Handler:           mux,
+				ReadHeaderTimeout: 30 * time.Second,
+			}
+
+			// Shut down gracefully when the command is cancelled
+			go func() {
+				<-cmd.Context().Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				_ = httpServer.Shutdown(shutdownCtx)
+			}()
+
+			logger.Infof("Listening on %s (POST /episodes)", httpServer.Addr)
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("server failed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVar(&host, "host", DefaultServeHost, "Address to listen on; use 0.0.0.0 for all interfaces, which requires SERVE_TOKEN")
+	cmd.Flags().StringVar(&port, "port", "", "Port to listen on (default: PORT environment variable or 8080)")
+	cmd.Flags().StringVar(&uploadDir, "upload-dir", "", "Directory for uploaded files while a request is processed (default: UPLOAD_DIR environment variable or uploads)")
+	cmd.Flags().Int64Var(&maxUploadSize, "max-upload-size", DefaultMaxUploadSize, "Maximum size of a request in bytes, audio included")
+	cmd.Flags().BoolVar(&allowUpload, "allow-upload", false, "Upload the generated content to Art19 as a draft unless a request sets skip_upload=true (default: only generate content)")
+
+	return cmd
+}
+
+// handleEpisode runs the pipeline for a multipart request with a transcript file, an optional
+// audio file and optional num_titles, language, title_index, shownote_index and skip_upload fields
+func (s *episodeServer) handleEpisode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+
+	if !s.authorized(r) {
+		s.logger.Warnf("Rejected unauthenticated episode request from %s", r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSONError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+		return
+	}
+
+	requestID := newRequestID()
+	logger := s.requestLogger(requestID)
+	logger.Infof("Received episode request from %s", r.RemoteAddr)
+
+	// Save the uploaded files for the duration of the request
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request is larger than %d bytes", s.maxUploadSize))
+			return
+		}
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid multipart form: %v", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	requestDir := filepath.Join(s.uploadDir, requestID)
+	if err := os.MkdirAll(requestDir, 0755); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create upload directory: %v", err))
+		return
+	}
+	defer os.RemoveAll(requestDir)
+
+	transcriptPath, err := saveFormFile(r, "transcript", requestDir)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if transcriptPath == "" {
+		writeJSONError(w, http.StatusBadRequest, "the transcript file is required")
+		return
+	}
+	audioPath, err := saveFormFile(r, "audio", requestDir)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts, titleIndex, showNoteIndex, skipUpload, err := parseEpisodeForm(r, !s.allowUpload)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !skipUpload && !s.allowUpload {
+		writeJSONError(w, http.StatusForbidden, "Art19 uploads are disabled on this server; start it with --allow-upload or set skip_upload=true")
+		return
+	}
+	if !skipUpload {
+		if err := s.cfg.ValidateFor(config.FeatureArt19); err != nil {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("Art19 upload is not configured (%v); set skip_upload=true to only generate content", err))
+			return
+		}
+	}
+
+	// Step 1: generate and select content
+	transcript, err := processor.LoadTranscript(transcriptPath, logger)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to load transcript: %v", err))
+		return
+	}
+	if strings.TrimSpace(transcript.Text) == "" {
+		writeJSONError(w, http.StatusBadRequest, "the transcript is empty")
+		return
+	}
+
+	aiService := services.NewAIService(s.cfg.OpenAIAPIKey, logger)
+	aiService.SetBaseURL(s.cfg.OpenAIBaseURL, s.cfg.AzureDeployment)
+	contentProcessor := processor.NewContentProcessor(aiService, logger)
+	candidates, err := contentProcessor.GenerateCandidates(r.Context(), transcript.Text, true, opts)
+	if err != nil {
+		logger.Errorf("Content generation failed: %v", err)
+		writeJSONError(w, http.StatusBadGateway, fmt.Sprintf("content generation failed: %v", err))
+		return
+	}
+
+	selector := ui.NewInteractiveUI(logger)
+	selector.NonInteractive = true
+	selector.TitleIndex = titleIndex
+	selector.ShowNoteIndex = showNoteIndex
+	selected, err := selector.SelectContent(candidates)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := &episodeResponse{
+		RequestID:  requestID,
+		Candidates: candidates,
+		Selected:   selected,
+	}
+
+	// Step 2: upload to Art19, one request at a time
+	if !skipUpload {
+		s.art19Mu.Lock()
+		art19Processor := processor.NewArt19Processor(services.NewArt19Service(s.cfg.Art19Username, s.cfg.Art19Password, logger), logger)
+		art19Processor.RSSFeedURL = s.cfg.RSSFeedURL
+		episode, err := art19Processor.UploadDraft(r.Context(), audioPath, selected)
+		s.art19Mu.Unlock()
+		if errors.Is(err, processor.ErrDuplicateEpisode) {
+			writeJSONError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			logger.Errorf("Art19 upload failed: %v", err)
+			writeJSONError(w, http.StatusBadGateway, fmt.Sprintf("Art19 upload failed: %v", err))
+			return
+		}
+		response.EpisodeID = episode.ID
+		response.EpisodeURL = episode.URL
+	}
+
+	logger.Info("Episode request completed")
+	writeJSON(w, http.StatusOK, response)
+}
+
+// authorized reports whether a request carries the server's bearer token, or no token is configured
+func (s *episodeServer) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.token)) == 1
+}
+
+// isLoopbackHost reports whether a listen address is only reachable from this machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// requestLogger creates a logger for one request that tags every entry with the request ID
+func (s *episodeServer) requestLogger(requestID string) *logrus.Logger {
+	return taggedLogger(s.logger, "request_id", requestID)
+}
+
+// parseEpisodeForm reads the optional generation fields of an episode request.
+// skipUpload is the value of skip_upload when the request doesn't set it.
+func parseEpisodeForm(r *http.Request, skipUpload bool) (services.GenerateOptions, int, int, bool, error) {
+	opts := services.GenerateOptions{
+		Language: r.FormValue("language"),
+	}
+	if opts.Language != "" {
+		if _, err := services.LanguageName(opts.Language); err != nil {
+			return opts, 0, 0, false, err
+		}
+	}
+
+	var titleIndex, showNoteIndex int
+	fields := map[string]*int{
+		"num_titles":     &opts.NumTitles,
+		"episode_number": &opts.EpisodeNumber,
+		"title_index":    &titleIndex,
+		"shownote_index": &showNoteIndex,
+	}
+	for name, target := range fields {
+		value := r.FormValue(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return opts, 0, 0, false, fmt.Errorf("%s must be a non-negative integer, got %q", name, value)
+		}
+		*target = n
+	}
+	if value := r.FormValue("num_titles"); value != "" && (opts.NumTitles < 1 || opts.NumTitles > MaxServeNumTitles) {
+		return opts, 0, 0, false, fmt.Errorf("num_titles must be between 1 and %d, got %q", MaxServeNumTitles, value)
+	}
+
+	if value := r.FormValue("skip_upload"); value != "" {
+		var err error
+		skipUpload, err = strconv.ParseBool(value)
+		if err != nil {
+			return opts, 0, 0, false, fmt.Errorf("skip_upload must be true or false, got %q", value)
+		}
+	}
+
+	return opts, titleIndex, showNoteIndex, skipUpload, nil
+}
+
+// saveFormFile saves an uploaded file into dir and returns its path, or "" if the field is absent
+func saveFormFile(r *http.Request, field, dir string) (string, error) {
+	file, header, err := r.FormFile(field)
+	if errors.Is(err, http.ErrMissingFile) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s file: %w", field, err)
+	}
+	defer file.Close()
+
+	path := filepath.Join(dir, field+"-"+filepath.Base(header.Filename))
+	if err := writeUploadedFile(path, file); err != nil {
+		return "", fmt.Errorf("failed to save %s file: %w", field, err)
+	}
+	return path, nil
+}
+
+// writeUploadedFile copies an uploaded file to path
+func writeUploadedFile(path string, file multipart.File) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, file); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// newRequestID returns a random ID for a request
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+}
+
+// writeJSONError writes a JSON error response
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
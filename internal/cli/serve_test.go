@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseEpisodeForm(t *testing.T) {
+	tests := []struct {
+		name          string
+		form          url.Values
+		wantNumTitles int
+		wantSkip      bool
+		wantErr       bool
+	}{
+		{name: "defaults", wantSkip: true},
+		{name: "num_titles in range", form: url.Values{"num_titles": {"5"}}, wantNumTitles: 5, wantSkip: true},
+		{name: "num_titles at the limit", form: url.Values{"num_titles": {"20"}}, wantNumTitles: 20, wantSkip: true},
+		{name: "num_titles above the limit", form: url.Values{"num_titles": {"5000"}}, wantErr: true},
+		{name: "num_titles zero", form: url.Values{"num_titles": {"0"}}, wantErr: true},
+		{name: "num_titles negative", form: url.Values{"num_titles": {"-1"}}, wantErr: true},
+		{name: "num_titles not a number", form: url.Values{"num_titles": {"ten"}}, wantErr: true},
+		{name: "skip_upload false", form: url.Values{"skip_upload": {"false"}}},
+		{name: "invalid skip_upload", form: url.Values{"skip_upload": {"maybe"}}, wantErr: true},
+		{name: "unknown language", form: url.Values{"language": {"xx"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/episodes", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			opts, _, _, skipUpload, err := parseEpisodeForm(r, true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEpisodeForm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if opts.NumTitles != tt.wantNumTitles {
+				t.Errorf("NumTitles = %d, want %d", opts.NumTitles, tt.wantNumTitles)
+			}
+			if skipUpload != tt.wantSkip {
+				t.Errorf("skipUpload = %v, want %v", skipUpload, tt.wantSkip)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/automate-podcast/config"
 	"github.com/automate-podcast/internal/model"
@@ -20,33 +24,108 @@ import (
 func Step1Cmd() *cobra.Command {
 	var inputTranscript string
 	var outputDir string
-	var verbose bool
 	var titlesOnly bool
 	var generateShowNotes bool
 	var openAIKey string
+	var openAIBaseURL string
+	var numTitles int
+	var examplesFile string
+	var metadataOut string
+	var maxTranscriptTokens int
+	var maxRetries int
+	var provider string
+	var anthropicKey string
+	var promptTemplateFile string
+	var hosts []string
+	var lint bool
+	var lintStrict bool
+	var lintFix bool
+	var lintRulesFile string
+	var episodeNumber int
+	var episodeBase int
+	var nonInteractive bool
+	var maxRegenerations int
+	var fromCandidates string
+	var dryRun bool
+	var printPrompt bool
+	var promptOut string
+	var outputPrefix string
+	var noClobber bool
+	var appendCandidates bool
+	var adTimecodes bool
+	var genChapters bool
+	var timestampedNotes bool
+	var cleanTranscript bool
+	var fillerWords []string
+	var removeSpeakerLabels bool
+	var speakerTurns bool
+	var maxCandidates int
+	var titleSimilarity float64
+	var strict bool
+	var jsonMode bool
+	var toolCalling bool
+	var stream bool
+	var noCache bool
+	var cacheDir string
+	var titleIndex int
+	var showNoteIndex int
+	var language string
+	var temperature float64
+	var maxTokens int
+	var rateLimitDelay time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "step1",
 		Short: "Process transcript and call OpenAI API",
 		Long:  `Import the transcript file, call OpenAI API, and show the output in console.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Initialize logger
-			logger := logrus.New()
-			if verbose {
-				logger.SetLevel(logrus.DebugLevel)
-			} else {
-				logger.SetLevel(logrus.InfoLevel)
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			if titleIndex < 0 || showNoteIndex < 0 {
+				return fmt.Errorf("--title-index and --shownote-index must be 1 or greater")
+			}
+			if _, err := services.LanguageName(language); err != nil {
+				return fmt.Errorf("invalid --language: %w", err)
+			}
+			if maxCandidates < 0 {
+				return fmt.Errorf("--max-candidates must be 0 or greater")
+			}
+			if titleSimilarity <= 0 || titleSimilarity > 1 {
+				return fmt.Errorf("--title-similarity must be greater than 0.0 and at most 1.0, got %g", titleSimilarity)
+			}
+			if (removeSpeakerLabels || cmd.Flags().Changed("filler-words")) && !cleanTranscript {
+				return fmt.Errorf("--filler-words and --remove-speaker-labels need --clean-transcript")
+			}
+			if promptOut != "" {
+				printPrompt = true
+			}
+			if err := validateOutputPrefix(outputPrefix, outputDir); err != nil {
+				return err
+			}
+			if appendCandidates && outputDir == "" {
+				return fmt.Errorf("--append needs --output-dir")
 			}
-			logger.SetFormatter(&logrus.TextFormatter{
-				FullTimestamp: true,
-			})
 
-			// Get OpenAI API key from flag or environment
-			if openAIKey == "" {
-				openAIKey = os.Getenv("OPENAI_API_KEY")
-				if openAIKey == "" {
-					return fmt.Errorf("OpenAI API key is required. Set it with --openai-key flag or OPENAI_API_KEY environment variable")
+			// Get the API key for the selected provider from flag or environment
+			// (not needed when reusing saved candidates)
+			switch provider {
+			case "openai":
+				if openAIKey == "" && fromCandidates == "" && !dryRun && !printPrompt {
+					openAIKey = os.Getenv("OPENAI_API_KEY")
+					if openAIKey == "" {
+						return fmt.Errorf("OpenAI API key is required. Set it with --openai-key flag or OPENAI_API_KEY environment variable")
+					}
+				}
+			case "anthropic":
+				if anthropicKey == "" && fromCandidates == "" && !dryRun && !printPrompt {
+					anthropicKey = os.Getenv("ANTHROPIC_API_KEY")
+					if anthropicKey == "" {
+						return fmt.Errorf("Anthropic API key is required. Set it with --anthropic-key flag or ANTHROPIC_API_KEY environment variable")
+					}
 				}
+			default:
+				return fmt.Errorf("unknown provider %q, expected openai or anthropic", provider)
 			}
 
 			// Create output directory if specified
@@ -55,50 +134,444 @@ func Step1Cmd() *cobra.Command {
 					return fmt.Errorf("failed to create output directory: %w", err)
 				}
 			}
+			// outputPath returns the path of an output file, with the --output-prefix
+			outputPath := func(name string) string {
+				return processor.OutputPath(outputDir, outputPrefix, name)
+			}
 
-			// 1. Load transcript
-			logger.Infof("Loading transcript from %s", inputTranscript)
-			transcript, err := processor.LoadTranscript(inputTranscript)
-			if err != nil {
-				return fmt.Errorf("failed to load transcript: %w", err)
+			// Refuse to overwrite the output of a previous run before spending any tokens
+			if noClobber && outputDir != "" {
+				names := []string{processor.CandidatesFileName, processor.SelectedContentFileName, processor.LegacySelectedContentFileName}
+				if !appendCandidates {
+					names = append(names, "all_candidates.txt")
+				}
+				if fromCandidates == "" {
+					names = append(names, "usage.json")
+				}
+				if genChapters {
+					names = append(names, processor.ChaptersXMLFileName, processor.ChaptersTextFileName)
+				}
+				var paths []string
+				for _, name := range names {
+					// Re-selecting from saved candidates rewrites the same file
+					if path := outputPath(name); filepath.Clean(path) != filepath.Clean(fromCandidates) {
+						paths = append(paths, path)
+					}
+				}
+				if err := checkNoClobber(paths); err != nil {
+					return err
+				}
 			}
-			logger.Info("Transcript loaded successfully")
 
-			// 2. Initialize AI service
-			aiService := services.NewAIService(openAIKey, logger)
+			// Model used for generation, recorded with appended candidates
+			generationModel := services.OpenAIContentModel
+			if provider == "anthropic" {
+				generationModel = services.DefaultClaudeModel
+			}
 
-			// 3. Initialize processor
-			contentProcessor := processor.NewContentProcessor(aiService, logger)
+			var err error
+			var usage services.Usage
+			var candidates *model.ContentCandidates
+			var regenerate func() (*model.ContentCandidates, error)
+			if fromCandidates != "" {
+				// Reuse previously generated candidates instead of calling the API
+				logger.Infof("Loading candidates from %s", fromCandidates)
+				candidates, err = processor.LoadCandidates(fromCandidates)
+				if err != nil {
+					return err
+				}
+				logger.Infof("Loaded %d title and %d show note candidates", len(candidates.Titles), len(candidates.ShowNotes))
+			} else {
+				// 1. Load transcript
+				logger.Infof("Loading transcript from %s", inputTranscript)
+				loadedTranscript, err := processor.LoadTranscript(inputTranscript, logger)
+				if err != nil {
+					return fmt.Errorf("failed to load transcript: %w", err)
+				}
+				// Remove filler words before the transcript is sent to the AI
+				if cleanTranscript {
+					if !cmd.Flags().Changed("filler-words") {
+						fillerWords = processor.FillerWordsFor(language)
+					}
+					opts := processor.CleanOptions{FillerWords: fillerWords, RemoveSpeakerLabels: removeSpeakerLabels}
+					before := utf8.RuneCountInString(loadedTranscript.Text)
+					loadedTranscript.Text = processor.CleanTranscript(loadedTranscript.Text, opts)
+					for i := range loadedTranscript.Segments {
+						loadedTranscript.Segments[i].Text = processor.CleanTranscript(loadedTranscript.Segments[i].Text, opts)
+					}
+					if removeSpeakerLabels {
+						loadedTranscript.Turns = nil
+					}
+					var turns []services.Turn
+					for _, turn := range loadedTranscript.Turns {
+						if turn.Text = processor.CleanTranscript(turn.Text, opts); turn.Text != "" {
+							turns = append(turns, turn)
+						}
+					}
+					loadedTranscript.Turns = turns
+					logger.Infof("Cleaned transcript: %d -> %d characters", before, utf8.RuneCountInString(loadedTranscript.Text))
+				}
+				transcript := loadedTranscript.Text
+				// Tell the model who is speaking in speaker-labeled transcripts
+				var speakers []string
+				if speakerTurns && len(loadedTranscript.Turns) > 0 {
+					transcript = services.FormatTurns(loadedTranscript.Turns)
+					speakers = services.TurnSpeakers(loadedTranscript.Turns)
+				}
+				if strings.TrimSpace(transcript) == "" {
+					return fmt.Errorf("transcript %s is empty", inputTranscript)
+				}
+				if adTimecodes && len(loadedTranscript.Segments) == 0 {
+					return fmt.Errorf("--ad-timecodes needs a timestamped transcript (SRT or VTT)")
+				}
+				if genChapters && len(loadedTranscript.Segments) == 0 {
+					return fmt.Errorf("--gen-chapters needs a timestamped transcript (SRT or VTT)")
+				}
+				// Send the start time of each line so the show note bullets can link to their topics
+				if timestampedNotes {
+					if len(loadedTranscript.Segments) == 0 {
+						return fmt.Errorf("--timestamped-notes needs a timestamped transcript (SRT or VTT)")
+					}
+					transcript = services.FormatTimestampedTranscript(loadedTranscript.Segments)
+					speakers = nil
+				}
+				logger.Info("Transcript loaded successfully")
 
-			// 4. AI generation process
-			logger.Info("Starting content generation...")
-			// Determine what to generate based on flags
-			genShownotes := generateShowNotes
+				// Load few-shot style examples if specified
+				var examples []services.StyleExample
+				if examplesFile != "" {
+					logger.Infof("Loading style examples from %s", examplesFile)
+					examples, err = processor.LoadExamples(examplesFile)
+					if err != nil {
+						return fmt.Errorf("failed to load style examples: %w", err)
+					}
+					logger.Infof("Loaded %d style examples", len(examples))
+				}
 
-			// If titles-only is set, override other flags
-			if titlesOnly {
-				genShownotes = false
-			}
+				// Load a custom prompt template from flag or environment
+				if promptTemplateFile == "" {
+					promptTemplateFile = os.Getenv("PROMPT_TEMPLATE")
+				}
+				var promptTemplate string
+				if promptTemplateFile != "" {
+					logger.Infof("Loading prompt template from %s", promptTemplateFile)
+					promptTemplate, err = processor.LoadPromptTemplate(promptTemplateFile)
+					if err != nil {
+						return err
+					}
+				}
 
-			// Generate content
-			candidates, err := contentProcessor.GenerateCandidates(transcript, genShownotes)
-			if err != nil {
-				return fmt.Errorf("content generation failed: %w", err)
+				// Derive the episode number from the RSS feed unless given explicitly
+				if episodeNumber <= 0 && !dryRun {
+					if rssURL := os.Getenv("RSS_FEED_URL"); rssURL != "" {
+						snsService := services.NewSNSService(logger)
+						episodeNumber, err = snsService.GetNextEpisodeNumber(cmd.Context(), rssURL, episodeBase)
+						if err != nil {
+							logger.Warnf("Failed to derive episode number from RSS feed: %v", err)
+							episodeNumber = 0
+						}
+					} else {
+						logger.Debug("RSS_FEED_URL not set, episode number left to the model")
+					}
+				}
+				if episodeNumber > 0 {
+					logger.Infof("Episode number: %d", episodeNumber)
+				}
+
+				if err := services.ValidateTemperature(temperature); err != nil {
+					return err
+				}
+				if provider == "anthropic" && temperature > 1 {
+					return fmt.Errorf("temperature must be between 0.0 and 1.0 with the anthropic provider, got %g", temperature)
+				}
+				if maxTokens <= 0 {
+					return fmt.Errorf("--max-tokens must be positive, got %d", maxTokens)
+				}
+				temperature32 := float32(temperature)
+
+				// In dry-run mode, print the prompt instead of calling the API
+				if dryRun {
+					prompt, err := services.BuildContentPrompt(transcript, services.GenerateOptions{
+						NumTitles:        numTitles,
+						Examples:         examples,
+						PromptTemplate:   promptTemplate,
+						EpisodeNumber:    episodeNumber,
+						Hosts:            hosts,
+						MaxTokens:        maxTokens,
+						Language:         language,
+						Speakers:         speakers,
+						TimestampedNotes: timestampedNotes,
+					}, logger)
+					if err != nil {
+						return err
+					}
+					fmt.Println(prompt)
+
+					tokens := services.EstimateTokens(transcript)
+					logger.Infof("Transcript: about %d tokens", tokens)
+					if provider == "openai" && tokens > maxTranscriptTokens {
+						logger.Infof("The transcript exceeds %d tokens and would be chunked and summarized before this prompt is sent", maxTranscriptTokens)
+					}
+					logger.Info("Dry run: skipping content generation")
+					return nil
+				}
+
+				// Print the exact messages that would be sent, including chunking, then exit
+				if printPrompt {
+					chunkTokens := maxTranscriptTokens
+					if provider == "anthropic" {
+						chunkTokens = 0
+					}
+					preview, err := services.PreviewContentRequest(transcript, chunkTokens, services.GenerateOptions{
+						NumTitles:        numTitles,
+						Examples:         examples,
+						PromptTemplate:   promptTemplate,
+						EpisodeNumber:    episodeNumber,
+						Hosts:            hosts,
+						JSONMode:         jsonMode && provider == "openai",
+						ToolCalling:      toolCalling && provider == "openai",
+						Temperature:      &temperature32,
+						MaxTokens:        maxTokens,
+						Language:         language,
+						Speakers:         speakers,
+						TimestampedNotes: timestampedNotes,
+					}, logger)
+					if err != nil {
+						return err
+					}
+					if promptOut == "" {
+						fmt.Print(preview)
+						return nil
+					}
+					if err := os.WriteFile(promptOut, []byte(preview), 0644); err != nil {
+						return fmt.Errorf("failed to write prompt preview: %w", err)
+					}
+					logger.Infof("Prompt written to %s", promptOut)
+					return nil
+				}
+
+				// 2. Initialize AI service for the selected provider
+				var generator services.ContentGenerator
+				if provider == "anthropic" {
+					claudeService := services.NewClaudeService(anthropicKey, logger)
+					claudeService.MaxRetries = maxRetries
+					generator = claudeService
+				} else {
+					aiService := services.NewAIService(openAIKey, logger)
+					// Route requests through a proxy or Azure OpenAI if configured
+					if openAIBaseURL == "" {
+						openAIBaseURL = os.Getenv("OPENAI_BASE_URL")
+					}
+					aiService.SetBaseURL(openAIBaseURL, os.Getenv("AZURE_OPENAI_DEPLOYMENT"))
+					aiService.MaxTranscriptTokens = maxTranscriptTokens
+					aiService.MaxRetries = maxRetries
+					aiService.CacheDir = cacheDir
+					aiService.RateLimitDelay = rateLimitDelay
+					generator = aiService
+				}
+				logger.Infof("Using %s for content generation", provider)
+				if toolCalling && provider == "anthropic" {
+					logger.Warn("--tool-calling is only supported with OpenAI, using text markers")
+				}
+				if jsonMode && provider == "anthropic" {
+					logger.Warn("--json-mode is only supported with OpenAI, using text markers")
+				}
+				if stream && provider == "anthropic" {
+					logger.Warn("--stream is only supported with OpenAI, waiting for the full response")
+				}
+
+				// 3. Initialize processor
+				contentProcessor := processor.NewContentProcessor(generator, logger)
+				contentProcessor.MaxCandidates = maxCandidates
+				contentProcessor.SimilarityThreshold = titleSimilarity
+
+				// 4. AI generation process
+				logger.Info("Starting content generation...")
+				// Determine what to generate based on flags
+				genShownotes := generateShowNotes
+
+				// If titles-only is set, override other flags
+				if titlesOnly {
+					genShownotes = false
+				}
+
+				// Generate content, accumulating token usage across API calls
+				generateOpts := services.GenerateOptions{
+					NumTitles:        numTitles,
+					Examples:         examples,
+					PromptTemplate:   promptTemplate,
+					EpisodeNumber:    episodeNumber,
+					Hosts:            hosts,
+					OnUsage:          usage.Add,
+					JSONMode:         jsonMode,
+					ToolCalling:      toolCalling,
+					NoCache:          noCache,
+					Temperature:      &temperature32,
+					MaxTokens:        maxTokens,
+					Language:         language,
+					Speakers:         speakers,
+					TimestampedNotes: timestampedNotes,
+				}
+				if stream {
+					generateOpts.StreamTo = os.Stdout
+				}
+				// Show a spinner while waiting, unless the response is streamed to the terminal
+				var spinner *ui.Spinner
+				if !stream {
+					spinner = ui.StartSpinner(logger, "Generating content...")
+				}
+				candidates, err = contentProcessor.GenerateCandidates(cmd.Context(), transcript, genShownotes, generateOpts)
+				spinner.Stop()
+				if err != nil {
+					return fmt.Errorf("content generation failed: %w", err)
+				}
+				logger.Info("Content generation completed")
+
+				// Check the topic timecodes of the show notes against the episode length
+				if timestampedNotes {
+					contentProcessor.CheckShowNoteTimecodes(candidates, loadedTranscript.Segments)
+				}
+
+				// Suggest ad breaks from the transcript timing
+				if adTimecodes {
+					spinner := ui.StartSpinner(logger, "Suggesting ad breaks...")
+					err := contentProcessor.GenerateAdTimecodes(cmd.Context(), candidates, loadedTranscript.Segments, generateOpts)
+					spinner.Stop()
+					if err != nil {
+						return err
+					}
+				}
+
+				// Suggest chapter markers from the transcript timing
+				if genChapters {
+					spinner := ui.StartSpinner(logger, "Generating chapters...")
+					err := contentProcessor.GenerateChapters(cmd.Context(), candidates, loadedTranscript.Segments, generateOpts)
+					spinner.Stop()
+					if err != nil {
+						return err
+					}
+				}
+
+				regenerate = func() (*model.ContentCandidates, error) {
+					// A regenerated response must differ from the cached one
+					regenerateOpts := generateOpts
+					regenerateOpts.NoCache = true
+					var spinner *ui.Spinner
+					if !stream {
+						spinner = ui.StartSpinner(logger, "Regenerating content...")
+					}
+					regenerated, err := contentProcessor.GenerateCandidates(cmd.Context(), transcript, genShownotes, regenerateOpts)
+					spinner.Stop()
+					if err != nil {
+						return nil, err
+					}
+					// Ad breaks and chapters don't depend on the titles and show notes, so keep them
+					regenerated.AdTimecodes = candidates.AdTimecodes
+					regenerated.Chapters = candidates.Chapters
+					return regenerated, nil
+				}
+
+				// Check the show notes against the format required by the default prompt
+				if genShownotes && promptTemplate == "" {
+					if logShowNoteViolations(candidates, logger) > 0 && strict {
+						logger.Info("Show note does not follow the required format, regenerating once (--strict)")
+						candidates, err = regenerate()
+						if err != nil {
+							return fmt.Errorf("content regeneration failed: %w", err)
+						}
+						logShowNoteViolations(candidates, logger)
+					}
+				}
 			}
-			logger.Info("Content generation completed")
 
 			// 5. Display the generated content
 			interactiveUI := ui.NewInteractiveUI(logger)
+			interactiveUI.NonInteractive = nonInteractive
+			interactiveUI.TitleIndex = titleIndex
+			interactiveUI.ShowNoteIndex = showNoteIndex
+			interactiveUI.MaxRegenerations = maxRegenerations
+			interactiveUI.Regenerate = regenerate
 			logger.Info("Displaying content...")
 			selectedContent, err := interactiveUI.SelectContent(candidates)
 			if err != nil {
 				return fmt.Errorf("content display failed: %w", err)
 			}
 
+			// Report token usage and estimated cost, including any regenerations
+			if usage.Calls > 0 {
+				logger.Infof("Token usage: %d prompt + %d completion = %d total (%d API calls)",
+					usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, usage.Calls)
+				if cost, ok := services.EstimateCost(usage); ok {
+					logger.Infof("Estimated cost: $%.4f (%s)", cost, usage.Model)
+				} else {
+					logger.Warnf("No price information for model %s, cost not estimated", usage.Model)
+				}
+				if outputDir != "" {
+					usagePath := outputPath("usage.json")
+					data, err := json.MarshalIndent(usage, "", "  ")
+					if err == nil {
+						err = os.WriteFile(usagePath, append(data, '\n'), 0644)
+					}
+					if err != nil {
+						logger.Warnf("Failed to save usage to file: %v", err)
+					} else {
+						logger.Infof("Token usage saved to %s", usagePath)
+					}
+				}
+			}
+
+			// Check the selected content against the house style rules
+			if lint || lintStrict || lintFix {
+				rules := processor.DefaultLintRules()
+				if lintRulesFile != "" {
+					rules, err = processor.LoadLintRules(lintRulesFile)
+					if err != nil {
+						return err
+					}
+				}
+				linter, err := processor.NewLinter(rules)
+				if err != nil {
+					return err
+				}
+
+				if lintFix {
+					if fixes := linter.Fix(selectedContent); fixes > 0 {
+						logger.Infof("Applied %d house style fixes", fixes)
+					}
+				}
+
+				violations := linter.Lint(selectedContent)
+				for _, violation := range violations {
+					logger.Warnf("House style violation: %s", violation)
+				}
+				if len(violations) == 0 {
+					logger.Info("No house style violations found")
+				} else if lintStrict {
+					return fmt.Errorf("%d house style violations found", len(violations))
+				}
+			}
+
 			// Save all candidates to file if output directory is specified
 			if outputDir != "" {
-				allCandidatesPath := filepath.Join(outputDir, "all_candidates.txt")
-				content := "=== Title Candidates ===\n"
+				// Save the candidates in machine-readable form so selection can be re-run with --from-candidates
+				candidatesPath := outputPath(processor.CandidatesFileName)
+				if err := processor.SaveCandidates(candidatesPath, candidates); err != nil {
+					logger.Warnf("Failed to save candidates to file: %v", err)
+				} else {
+					logger.Infof("Candidates saved to %s", candidatesPath)
+				}
+
+				allCandidatesPath := outputPath("all_candidates.txt")
+				content := ""
+				if appendCandidates {
+					// Label each run so the appended candidates can be compared
+					source := fmt.Sprintf("model: %s, temperature: %g", generationModel, temperature)
+					if fromCandidates != "" {
+						source = "from " + fromCandidates
+					}
+					content = fmt.Sprintf("##### Run %s (%s) #####\n\n", time.Now().Format(time.RFC3339), source)
+				}
+				content += "=== Title Candidates ===\n"
 				for i, title := range candidates.Titles {
 					content += fmt.Sprintf("%d: %s\n", i+1, title)
 				}
@@ -108,22 +581,65 @@ func Step1Cmd() *cobra.Command {
 					content += fmt.Sprintf("%d:\n%s\n\n", i+1, note)
 				}
 
-				if err := os.WriteFile(allCandidatesPath, []byte(content), 0644); err != nil {
+				if appendCandidates {
+					if err := appendToFile(allCandidatesPath, content); err != nil {
+						logger.Warnf("Failed to append candidates to file: %v", err)
+					} else {
+						logger.Infof("Candidates appended to %s", allCandidatesPath)
+					}
+				} else if err := os.WriteFile(allCandidatesPath, []byte(content), 0644); err != nil {
 					logger.Warnf("Failed to save all candidates to file: %v", err)
 				} else {
 					logger.Infof("All candidates saved to %s", allCandidatesPath)
 				}
 
-				// Also save the selected content
-				selectedPath := filepath.Join(outputDir, "selected_content.txt")
-				selectedContent := fmt.Sprintf("=== Selected Content ===\nTitle: %s\n\nShow Notes:\n%s",
-					selectedContent.Title, selectedContent.ShowNote)
-
-				if err := os.WriteFile(selectedPath, []byte(selectedContent), 0644); err != nil {
+				// Also save the selected content, as JSON for step2 and as text for reading
+				selectedPath := outputPath(processor.SelectedContentFileName)
+				if err := processor.SaveSelectedContent(selectedPath, selectedContent); err != nil {
 					logger.Warnf("Failed to save selected content to file: %v", err)
 				} else {
 					logger.Infof("Selected content saved to %s", selectedPath)
 				}
+
+				legacyPath := outputPath(processor.LegacySelectedContentFileName)
+				if err := os.WriteFile(legacyPath, []byte(processor.FormatSelectedContent(selectedContent)), 0644); err != nil {
+					logger.Warnf("Failed to save selected content to file: %v", err)
+				}
+			}
+
+			// Save the chapters as Podlove Simple Chapters and as a plain list
+			if len(candidates.Chapters) > 0 {
+				if outputDir != "" {
+					psc, err := processor.ChaptersToPSC(candidates.Chapters)
+					if err != nil {
+						return err
+					}
+					chaptersXMLPath := outputPath(processor.ChaptersXMLFileName)
+					if err := os.WriteFile(chaptersXMLPath, []byte(psc), 0644); err != nil {
+						return fmt.Errorf("failed to save chapters: %w", err)
+					}
+					chaptersTextPath := outputPath(processor.ChaptersTextFileName)
+					if err := os.WriteFile(chaptersTextPath, []byte(processor.ChaptersToText(candidates.Chapters)), 0644); err != nil {
+						return fmt.Errorf("failed to save chapters: %w", err)
+					}
+					logger.Infof("Chapters saved to %s and %s", chaptersXMLPath, chaptersTextPath)
+				} else {
+					fmt.Print("\n=== CHAPTERS ===\n" + processor.ChaptersToText(candidates.Chapters))
+				}
+			}
+
+			// Record the selected content in the episode metadata document
+			if metadataOut != "" {
+				err := processor.UpdateMetadata(metadataOut, func(meta *model.EpisodeMetadata) {
+					processor.ApplySelectedContent(meta, selectedContent)
+					if len(candidates.Chapters) > 0 {
+						meta.Chapters = candidates.Chapters
+					}
+				})
+				if err != nil {
+					return fmt.Errorf("failed to write episode metadata: %w", err)
+				}
+				logger.Infof("Episode metadata written to %s", metadataOut)
 			}
 
 			logger.Info("Step 1 completed successfully!")
@@ -132,87 +648,359 @@ func Step1Cmd() *cobra.Command {
 	}
 
 	// Set flags
-	cmd.Flags().StringVarP(&inputTranscript, "input-transcript", "t", "", "Path to transcript file (required)")
+	cmd.Flags().StringVarP(&inputTranscript, "input-transcript", "t", "", "Path to transcript file: plain text, SRT or VTT (required unless --from-candidates is set)")
 	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Output directory for generated files")
+	cmd.Flags().BoolVar(&stream, "stream", false, "Print the response as it is generated instead of waiting for it")
+	cmd.Flags().BoolVar(&jsonMode, "json-mode", false, "Ask OpenAI for a JSON response instead of parsing [TITLE]/[SHOW NOTE] markers")
+	cmd.Flags().BoolVar(&toolCalling, "tool-calling", false, "Have OpenAI return the content as emit_content tool call arguments with a strict schema (takes precedence over --json-mode)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Regenerate once if the show note doesn't follow the required format")
+	cmd.Flags().BoolVar(&adTimecodes, "ad-timecodes", false, "Also suggest ad break timecodes (requires an SRT or VTT transcript)")
+	cmd.Flags().BoolVar(&timestampedNotes, "timestamped-notes", false, "Add the (MM:SS) start time of each topic to the show note bullet points (requires an SRT or VTT transcript)")
+	cmd.Flags().BoolVar(&genChapters, "gen-chapters", false, "Also generate chapter markers and save them as chapters.xml (Podlove Simple Chapters) and chapters.txt (requires an SRT or VTT transcript)")
+	cmd.Flags().BoolVar(&cleanTranscript, "clean-transcript", false, "Remove filler words such as えーと and um, and collapse whitespace, before generation")
+	cmd.Flags().StringSliceVar(&fillerWords, "filler-words", nil, "Comma-separated filler words removed by --clean-transcript (default: built-in list for --language)")
+	cmd.Flags().BoolVar(&removeSpeakerLabels, "remove-speaker-labels", false, "With --clean-transcript, also remove speaker labels such as \"Speaker 1:\" at the start of lines")
+	cmd.Flags().BoolVar(&speakerTurns, "speaker-turns", true, "Tell the model who is speaking when the transcript has speaker labels such as \"Host:\" or \"[Guest]\"")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate the transcript and print the prompt without calling the API")
+	cmd.Flags().BoolVar(&printPrompt, "print-prompt", false, "Print the complete system and user messages, with chunking applied, and exit without calling the API")
+	cmd.Flags().StringVar(&outputPrefix, "output-prefix", "", "Prefix for the names of the files written to --output-dir, e.g. ep42_ for ep42_selected_content.json")
+	cmd.Flags().BoolVar(&appendCandidates, "append", false, "Append this run's candidates to all_candidates.txt as a timestamped section with the model and temperature, instead of overwriting it")
+	cmd.Flags().BoolVar(&noClobber, "no-clobber", false, "Fail before generating anything if the output files already exist in --output-dir")
+	cmd.Flags().StringVar(&promptOut, "prompt-out", "", "Write the --print-prompt output to this file instead of stdout (implies --print-prompt)")
+	cmd.Flags().StringVar(&fromCandidates, "from-candidates", "", "Skip generation and select from a candidates.json saved by a previous run")
 	cmd.Flags().StringVar(&openAIKey, "openai-key", "", "OpenAI API key (can also be set via OPENAI_API_KEY environment variable)")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	cmd.Flags().StringVar(&openAIBaseURL, "openai-base-url", "", "OpenAI-compatible endpoint or Azure OpenAI resource URL (can also be set via OPENAI_BASE_URL environment variable)")
+	cmd.Flags().StringVar(&provider, "provider", "openai", "Content generation backend: openai or anthropic")
+	cmd.Flags().StringVar(&anthropicKey, "anthropic-key", "", "Anthropic API key (can also be set via ANTHROPIC_API_KEY environment variable)")
+	cmd.Flags().StringVar(&promptTemplateFile, "prompt-template", "", "Prompt template file using {{.Transcript}}, {{.EpisodeNumber}}, {{.Hosts}}, {{.Language}}, {{.Speakers}} (can also be set via PROMPT_TEMPLATE environment variable)")
+	cmd.Flags().StringVar(&language, "language", services.DefaultLanguage, "Language of the generated titles and show notes as an ISO-639-1 code (e.g. ja, en)")
+	cmd.Flags().StringSliceVar(&hosts, "hosts", nil, "Comma-separated host handles for the prompt's credits")
+	cmd.Flags().IntVar(&episodeNumber, "episode-number", 0, "Episode number for the title (default: next number derived from RSS_FEED_URL)")
+	cmd.Flags().IntVar(&episodeBase, "episode-base", 1, "Number of the first episode in the feed, used when titles don't start with a number")
+	cmd.Flags().BoolVar(&lint, "lint", false, "Check the selected content against house style rules and warn on violations")
+	cmd.Flags().BoolVar(&lintStrict, "lint-strict", false, "Fail if the selected content violates house style rules")
+	cmd.Flags().BoolVar(&lintFix, "lint-fix", false, "Automatically fix safe house style violations such as punctuation")
+	cmd.Flags().StringVar(&lintRulesFile, "lint-rules", "", "JSON file of house style rules (default: built-in ruleset)")
 	cmd.Flags().BoolVar(&titlesOnly, "titles-only", false, "Generate only titles, skip show notes")
 	cmd.Flags().BoolVar(&generateShowNotes, "gen-shownotes", true, "Generate show notes (default: true)")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Select the first candidates without prompting (for CI)")
+	cmd.Flags().IntVar(&titleIndex, "title-index", 0, "Select the Nth title candidate (1-based) without prompting")
+	cmd.Flags().IntVar(&showNoteIndex, "shownote-index", 0, "Select the Nth show note candidate (1-based) without prompting")
+	cmd.Flags().IntVar(&maxRegenerations, "max-regenerations", ui.DefaultMaxRegenerations, "Maximum number of times candidates can be regenerated during selection")
+	cmd.Flags().IntVar(&numTitles, "num-titles", services.DefaultNumTitles, "Number of title candidates to generate")
+	cmd.Flags().IntVar(&maxCandidates, "max-candidates", 0, "Maximum number of title candidates to choose from, keeping the most distinct ones (0 keeps all)")
+	cmd.Flags().Float64Var(&titleSimilarity, "title-similarity", processor.DefaultTitleSimilarityThreshold, "Drop title candidates at least this similar (0.0-1.0) to an earlier one; 1.0 drops only identical titles")
+	cmd.Flags().StringVar(&examplesFile, "examples-file", "", "JSON file of past approved title/show note pairs used as style examples")
+	cmd.Flags().StringVar(&metadataOut, "metadata-out", "", "Episode metadata JSON file to create or update (optional)")
+	cmd.Flags().IntVar(&maxTranscriptTokens, "max-transcript-tokens", services.DefaultMaxTranscriptTokens, "Transcripts longer than this many estimated tokens are chunked and summarized first")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", services.DefaultMaxRetries, "Number of retries for OpenAI rate-limit and server errors")
+	cmd.Flags().Float64Var(&temperature, "temperature", services.DefaultTemperature, "Sampling temperature from 0.0 (focused) to 2.0 (varied); anthropic accepts up to 1.0")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", services.DefaultMaxResponseTokens, "Maximum number of tokens in the generated response")
+	cmd.Flags().DurationVar(&rateLimitDelay, "rate-limit-delay", 0, "Minimum pause between consecutive OpenAI calls, e.g. 5s for low rate-limit tiers (0 disables it)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Call OpenAI even if a cached response exists for the same transcript and prompt")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", services.DefaultCacheDir(), "Directory for cached OpenAI responses (empty disables caching)")
 
-	// Set required flags
-	if err := cmd.MarkFlagRequired("input-transcript"); err != nil {
-		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
-	}
+	// Set required flags; saved candidates replace the transcript
+	cmd.MarkFlagsOneRequired("input-transcript", "from-candidates")
+	cmd.MarkFlagsMutuallyExclusive("print-prompt", "from-candidates")
+	cmd.MarkFlagsMutuallyExclusive("prompt-out", "from-candidates")
+	cmd.MarkFlagsMutuallyExclusive("print-prompt", "dry-run")
+	cmd.MarkFlagsMutuallyExclusive("prompt-out", "dry-run")
 
 	return cmd
 }
 
+// logMCPHint prominently logs how to fix a Playwright MCP server failure
+func logMCPHint(logger *logrus.Logger, err error) {
+	var mcpErr *services.MCPError
+	if errors.As(err, &mcpErr) {
+		logger.Errorf(">>> Hint: %s", mcpErr.Hint())
+	}
+}
+
+// appendToFile appends text to a file, creating it if needed, separated from earlier content by a blank line
+func appendToFile(path, text string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		text = "\n" + text
+	}
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// validateOutputPrefix checks an --output-prefix, which names step1's files in --output-dir
+func validateOutputPrefix(prefix, outputDir string) error {
+	if prefix == "" {
+		return nil
+	}
+	if outputDir == "" {
+		return fmt.Errorf("--output-prefix needs --output-dir")
+	}
+	if strings.ContainsAny(prefix, `/\`) {
+		return fmt.Errorf("--output-prefix must be a file name prefix without path separators, got %q", prefix)
+	}
+	return nil
+}
+
+// checkNoClobber returns an error listing the output files that already exist
+func checkNoClobber(paths []string) error {
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("output files already exist (--no-clobber): %s; use another --output-dir or --output-prefix", strings.Join(existing, ", "))
+	}
+	return nil
+}
+
+// logShowNoteViolations warns about show note candidates that don't follow the required format
+// and returns the number of violations found
+func logShowNoteViolations(candidates *model.ContentCandidates, logger *logrus.Logger) int {
+	total := 0
+	for i, note := range candidates.ShowNotes {
+		violations := processor.ValidateShowNote(note)
+		for _, violation := range violations {
+			logger.Warnf("Show note %d: %s", i+1, violation)
+		}
+		total += len(violations)
+	}
+	return total
+}
+
 // Step2Cmd creates a command for uploading to Art19
 func Step2Cmd() *cobra.Command {
 	var inputAudio string
 	var contentFile string
-	var verbose bool
+	var title string
+	var showNote string
+	var showNoteFile string
+	var mcpTimeout time.Duration
+	var outputDir string
+	var metadataOut string
+	var dryRun bool
+	var adMarkers string
+	var episodeDuration string
+	var noDuplicateCheck bool
+	var force bool
+	var verify bool
+	var preview bool
 
 	cmd := &cobra.Command{
 		Use:   "step2",
 		Short: "Upload to Art19",
 		Long:  `Upload title, shownote and audio to the Art19 platform.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Initialize logger
-			logger := logrus.New()
-			if verbose {
-				logger.SetLevel(logrus.DebugLevel)
-			} else {
-				logger.SetLevel(logrus.InfoLevel)
-			}
-			logger.SetFormatter(&logrus.TextFormatter{
-				FullTimestamp: true,
-			})
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
 
-			// Load configuration
-			cfg, err := config.LoadConfig()
+			// Load configuration; only the Art19 credentials are required here
+			cfg, err := config.LoadConfig(config.FeatureArt19)
 			if err != nil {
 				return fmt.Errorf("failed to load configuration: %w", err)
 			}
 
-			// Load selected content from file
-			var selectedContent *model.SelectedContent
+			// Load selected content from file; --title and --shownote override it
+			selectedContent := &model.SelectedContent{}
 			if contentFile != "" {
-				// Read content from file
 				logger.Infof("Loading content from %s", contentFile)
-				content, err := os.ReadFile(contentFile)
+				selectedContent, err = processor.LoadSelectedContent(contentFile, logger)
 				if err != nil {
-					return fmt.Errorf("failed to read content file: %w", err)
+					return err
 				}
+			}
+			if cmd.Flags().Changed("title") {
+				selectedContent.Title = strings.TrimSpace(title)
+			}
+			if cmd.Flags().Changed("shownote") {
+				selectedContent.ShowNote = strings.TrimSpace(showNote)
+			}
+			if showNoteFile != "" {
+				data, err := os.ReadFile(showNoteFile)
+				if err != nil {
+					return fmt.Errorf("failed to read show note file: %w", err)
+				}
+				selectedContent.ShowNote = strings.TrimSpace(string(data))
+			}
+			if strings.TrimSpace(selectedContent.Title) == "" {
+				return fmt.Errorf("a title is required: set --title or use a --content-file with a title")
+			}
 
-				// Parse content
-				contentStr := string(content)
-				titleStart := strings.Index(contentStr, "Title: ")
-				showNotesStart := strings.Index(contentStr, "Show Notes:")
+			// Show how the show note will render and let the user stop before anything is uploaded
+			if preview {
+				var violations []string
+				if selectedContent.ShowNote != "" {
+					violations = processor.ValidateShowNote(selectedContent.ShowNote)
+				}
+				proceed, err := ui.NewInteractiveUI(logger).PreviewShowNote(selectedContent.Title, selectedContent.ShowNote, violations)
+				if err != nil {
+					return err
+				}
+				if !proceed {
+					logger.Info("Upload cancelled after the preview; nothing was sent to Art19")
+					return nil
+				}
+			}
 
-				if titleStart >= 0 && showNotesStart > titleStart {
-					title := strings.TrimSpace(contentStr[titleStart+len("Title: ") : showNotesStart])
-					showNote := strings.TrimSpace(contentStr[showNotesStart+len("Show Notes:"):])
+			// Parse and validate the ad markers before uploading anything
+			var markers []float64
+			var duration float64
+			if adMarkers != "" {
+				markers, err = processor.ParseTimestamps(adMarkers)
+				if err != nil {
+					return fmt.Errorf("invalid --ad-markers: %w", err)
+				}
 
-					selectedContent = &model.SelectedContent{
-						Title:    title,
-						ShowNote: showNote,
+				// The episode duration comes from the flag or the episode metadata
+				if episodeDuration != "" {
+					duration, err = processor.ParseTimestamp(episodeDuration)
+					if err != nil {
+						return fmt.Errorf("invalid --episode-duration: %w", err)
 					}
-				} else {
-					return fmt.Errorf("failed to parse content file, expected format not found")
+				} else if metadataOut != "" {
+					meta, err := processor.LoadMetadata(metadataOut)
+					if err != nil {
+						return err
+					}
+					duration = meta.DurationSeconds
+				}
+				if duration == 0 {
+					logger.Warn("Episode duration is unknown; ad markers are not checked against it (use --episode-duration)")
+				}
+
+				if err := services.ValidateAdMarkers(markers, duration); err != nil {
+					return fmt.Errorf("invalid --ad-markers: %w", err)
 				}
-			} else {
-				return fmt.Errorf("content file is required")
 			}
 
 			// Initialize Art19 service
 			art19Service := services.NewArt19Service(cfg.Art19Username, cfg.Art19Password, logger)
+			art19Service.SetTimeout(mcpTimeout)
+			art19Service.DryRun = dryRun
 			art19Processor := processor.NewArt19Processor(art19Service, logger)
+			art19Processor.RSSFeedURL = cfg.RSSFeedURL
+			art19Processor.AllowDuplicates = noDuplicateCheck
+			art19Processor.Verify = verify && !dryRun
+
+			// Check the state file for an earlier upload of the same content, so a re-run doesn't create a second draft
+			var state *processor.State
+			var contentHash string
+			var resumed bool
+			var episode *services.Art19Episode
+			if outputDir != "" && !dryRun {
+				state, err = processor.LoadState(outputDir)
+				if err != nil {
+					return err
+				}
+				contentHash, err = processor.HashDraftContent(selectedContent, inputAudio)
+				if err != nil {
+					return err
+				}
+
+				if draft := state.Art19Draft; draft != nil && draft.ContentHash == contentHash && !force {
+					if !draft.Completed() {
+						reason := "it was interrupted"
+						if draft.LastError != "" {
+							reason = "it failed: " + draft.LastError
+						}
+						return fmt.Errorf("a previous step2 run started uploading this content at %s but %s. It may have created a draft already; check Art19 and re-run with --force to upload again",
+							draft.StartedAt.Format(time.RFC3339), reason)
+					}
+					logger.Infof("A draft was already created for this content at %s, resuming without uploading again (use --force to upload again)", draft.CompletedAt.Format(time.RFC3339))
+					episode = &services.Art19Episode{ID: draft.EpisodeID, URL: draft.EpisodeURL}
+					resumed = true
+				}
+			}
 
 			// Upload to Art19
-			logger.Info("Starting Art19 upload process...")
-			if err := art19Processor.UploadDraft(cmd.Context(), inputAudio, selectedContent); err != nil {
-				return fmt.Errorf("Art19 upload failed: %w", err)
+			if !resumed {
+				// Record the attempt before uploading, so a failure halfway is detected on re-run
+				if state != nil {
+					state.Art19Draft = &processor.Art19Draft{ContentHash: contentHash, StartedAt: time.Now()}
+					if err := processor.SaveState(outputDir, state); err != nil {
+						return err
+					}
+				}
+
+				logger.Info("Starting Art19 upload process...")
+				// The dry run prints the payload instead, so only show a spinner for real uploads
+				var spinner *ui.Spinner
+				if !dryRun {
+					spinner = ui.StartSpinner(logger, "Uploading to Art19...")
+				}
+				episode, err = art19Processor.UploadDraft(cmd.Context(), inputAudio, selectedContent)
+				spinner.Stop()
+
+				// A duplicate found in the RSS feed means nothing was uploaded, so there is nothing to resume
+				if state != nil && errors.Is(err, processor.ErrDuplicateEpisode) {
+					state.Art19Draft = nil
+				} else if state != nil && err != nil {
+					state.Art19Draft.LastError = err.Error()
+				} else if state != nil {
+					state.Art19Draft.CompletedAt = time.Now()
+					state.Art19Draft.EpisodeID = episode.ID
+					state.Art19Draft.EpisodeURL = episode.URL
+				}
+				if state != nil {
+					if saveErr := processor.SaveState(outputDir, state); saveErr != nil {
+						logger.Warnf("Failed to save Art19 upload state: %v", saveErr)
+					}
+				}
+				if err != nil {
+					logMCPHint(logger, err)
+					return fmt.Errorf("Art19 upload failed: %w", err)
+				}
+			}
+
+			// Set the ad insertion points on the new episode
+			if len(markers) > 0 {
+				if err := art19Service.SetAdMarkers(cmd.Context(), episode.ID, markers, duration); err != nil {
+					logMCPHint(logger, err)
+					return fmt.Errorf("Art19 upload succeeded but %w", err)
+				}
+			}
+
+			if dryRun {
+				logger.Info("Dry run: nothing was sent to Art19")
+				return nil
+			}
+
+			// Save the created episode reference so later steps can use it
+			if outputDir != "" && (episode.URL != "" || episode.ID != "") {
+				episodePath := filepath.Join(outputDir, processor.Art19EpisodeFileName)
+				episodeInfo := fmt.Sprintf("URL: %s\nID: %s\n", episode.URL, episode.ID)
+				if err := os.WriteFile(episodePath, []byte(episodeInfo), 0644); err != nil {
+					logger.Warnf("Failed to save Art19 episode to file: %v", err)
+				} else {
+					logger.Infof("Art19 episode saved to %s", episodePath)
+				}
+			}
+
+			// Record the Art19 episode in the episode metadata document
+			if metadataOut != "" && episode.URL != "" {
+				err := processor.UpdateMetadata(metadataOut, func(meta *model.EpisodeMetadata) {
+					if meta.URLs == nil {
+						meta.URLs = make(map[string]string)
+					}
+					meta.URLs["art19"] = episode.URL
+				})
+				if err != nil {
+					return fmt.Errorf("failed to write episode metadata: %w", err)
+				}
+				logger.Infof("Episode metadata written to %s", metadataOut)
+			}
+
+			// Let the team know the draft is ready for review; they already heard about a resumed one
+			if !resumed {
+				notify(cmd.Context(), cfg, episodeMessage("📝 New Art19 draft created", selectedContent.Title, episode.URL), logger)
 			}
 
 			logger.Info("Step 2 completed successfully!")
@@ -222,40 +1010,57 @@ func Step2Cmd() *cobra.Command {
 
 	// Set flags
 	cmd.Flags().StringVarP(&inputAudio, "input-audio", "a", "", "Path to audio file (required)")
-	cmd.Flags().StringVarP(&contentFile, "content-file", "c", "", "Path to content file (required)")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	cmd.Flags().StringVarP(&contentFile, "content-file", "c", "", "Path to the selected_content.json written by step1; the legacy selected_content.txt is also accepted (required unless --title is set)")
+	cmd.Flags().StringVar(&title, "title", "", "Episode title, overriding the one in --content-file")
+	cmd.Flags().StringVar(&showNote, "shownote", "", "Show note text, overriding the one in --content-file")
+	cmd.Flags().StringVar(&showNoteFile, "shownote-file", "", "File with the show note text, overriding the one in --content-file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate the content file and audio path and print the MCP payload without sending it")
+	cmd.Flags().DurationVar(&mcpTimeout, "mcp-timeout", services.DefaultMCPTimeout, "Timeout for each Playwright MCP browser automation call")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Output directory to save the created Art19 episode reference and the upload state that makes re-runs safe")
+	cmd.Flags().StringVar(&metadataOut, "metadata-out", "", "Episode metadata JSON file to create or update (optional)")
+	cmd.Flags().StringVar(&adMarkers, "ad-markers", "", "Comma-separated ad marker timestamps in seconds, MM:SS or HH:MM:SS (e.g. 90,15:30)")
+	cmd.Flags().StringVar(&episodeDuration, "episode-duration", "", "Episode duration used to validate --ad-markers (default: duration_seconds from --metadata-out)")
+	cmd.Flags().BoolVar(&force, "force", false, "Upload even if the state file in --output-dir shows this content was already uploaded")
+	cmd.Flags().BoolVar(&verify, "verify", false, "Read the draft back from Art19 after the upload and warn if its title or show note doesn't match")
+	cmd.Flags().BoolVar(&preview, "preview", false, "Render the title and show note in the terminal, list format problems and ask before uploading")
+	cmd.Flags().BoolVar(&noDuplicateCheck, "no-duplicate-check", false, "Only warn instead of aborting when an episode with the same number or title is already in RSS_FEED_URL")
 
 	// Set required flags
 	if err := cmd.MarkFlagRequired("input-audio"); err != nil {
 		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
 	}
-	if err := cmd.MarkFlagRequired("content-file"); err != nil {
-		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
-	}
+	cmd.MarkFlagsOneRequired("content-file", "title")
+	cmd.MarkFlagsMutuallyExclusive("shownote", "shownote-file")
 
 	return cmd
 }
 
-// Step3Cmd creates a command for redeploying on Vercel
+// providerNames are the display names of the deployment providers
+var providerNames = map[string]string{
+	services.ProviderVercel:  "Vercel",
+	services.ProviderNetlify: "Netlify",
+}
+
+// Step3Cmd creates a command for redeploying on Vercel or Netlify
 func Step3Cmd() *cobra.Command {
-	var verbose bool
 	var dryRun bool
+	var retries int
+	var outputDir string
+	var outputPrefix string
+	var forceDeploy bool
+	var wait bool
+	var waitTimeout time.Duration
+	var hooks []string
+	var provider string
 
 	cmd := &cobra.Command{
 		Use:   "step3",
-		Short: "Redeploy on Vercel",
-		Long:  `Call Redeploy button in the Vercel via API to trigger a website redeployment.`,
+		Short: "Redeploy on Vercel or Netlify",
+		Long: `Call Redeploy button in the Vercel via API to trigger a website redeployment.
+Netlify is used instead with --provider netlify, or when only NETLIFY_BUILD_HOOK is configured.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Initialize logger
-			logger := logrus.New()
-			if verbose {
-				logger.SetLevel(logrus.DebugLevel)
-			} else {
-				logger.SetLevel(logrus.InfoLevel)
-			}
-			logger.SetFormatter(&logrus.TextFormatter{
-				FullTimestamp: true,
-			})
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
 
 			// Load .env file if it exists
 			if err := godotenv.Load(); err != nil {
@@ -264,26 +1069,119 @@ func Step3Cmd() *cobra.Command {
 				logger.Debug("Loaded environment variables from .env file")
 			}
 
-			// Initialize Vercel service directly from environment variables
-			vercelService := services.NewVercelServiceFromEnv(logger)
+			// Pick the deployment provider; Vercel stays the default
+			cfg := config.LoadEnvConfig()
+			if provider == "" {
+				provider = services.ProviderVercel
+				if cfg.NetlifyBuildHook != "" && cfg.VercelDeployHook == "" && len(hooks) == 0 {
+					provider = services.ProviderNetlify
+				}
+			}
+			logger.Debugf("Deployment provider: %s", provider)
+
+			var deployer services.Deployer
+			var vercelService *services.VercelService
+			switch provider {
+			case services.ProviderVercel:
+				// Initialize Vercel service directly from environment variables
+				vercelService = services.NewVercelServiceFromEnv(logger)
+				vercelService.MaxRetries = retries
+				vercelService.WaitTimeout = waitTimeout
 
-			// Check if Vercel deploy hook is configured
-			deployHookURL := os.Getenv("VERCEL_DEPLOY_HOOK")
-			if deployHookURL == "" {
-				return fmt.Errorf("Vercel deploy hook URL is not configured. Please set the VERCEL_DEPLOY_HOOK environment variable")
+				// Check if Vercel deploy hook is configured; --hook flags replace VERCEL_DEPLOY_HOOK
+				if len(hooks) > 0 {
+					vercelService.SetDeployHooks(hooks)
+				} else if err := cfg.ValidateFor(config.FeatureVercel); err != nil {
+					return fmt.Errorf("Vercel deploy hook URL is not configured: %w", err)
+				}
+
+				// Following the deployment needs the Vercel API
+				if wait {
+					if err := cfg.ValidateFor(config.FeatureVercelAPI); err != nil {
+						return fmt.Errorf("--wait requires Vercel API credentials: %w", err)
+					}
+				}
+				deployer = vercelService
+			case services.ProviderNetlify:
+				if len(hooks) > 0 || wait {
+					return fmt.Errorf("--hook and --wait are only supported for the %s provider", services.ProviderVercel)
+				}
+				if err := cfg.ValidateFor(config.FeatureNetlify); err != nil {
+					return fmt.Errorf("Netlify build hook URL is not configured: %w", err)
+				}
+				netlifyService := services.NewNetlifyService(cfg.NetlifyBuildHook, logger)
+				netlifyService.MaxRetries = retries
+				deployer = netlifyService
+			default:
+				return fmt.Errorf("unknown deployment provider %q (expected %s or %s)", provider, services.ProviderVercel, services.ProviderNetlify)
+			}
+
+			// Skip the redeploy when the generated content hasn't changed since the last deploy
+			var state *processor.State
+			var contentHash string
+			if outputDir != "" {
+				var err error
+				state, err = processor.LoadState(outputDir)
+				if err != nil {
+					return err
+				}
+				contentHash, err = processor.HashArtifacts(outputDir, outputPrefix, processor.DeployArtifacts...)
+				if err != nil {
+					return fmt.Errorf("failed to hash generated content: %w", err)
+				}
+				logger.Infof("Content hash: %s (last deployed: %s)", contentHash, state.LastDeployHash)
+
+				if contentHash == "" {
+					logger.Warnf("No generated content found in %s, redeploying anyway", outputDir)
+				} else if contentHash == state.LastDeployHash && !forceDeploy {
+					logger.Info("Content unchanged since the last deploy, skipping redeployment (use --force-deploy to override)")
+					logger.Info("Step 3 completed successfully!")
+					return nil
+				} else if contentHash == state.LastDeployHash {
+					logger.Info("Content unchanged since the last deploy, redeploying because --force-deploy is set")
+				}
 			}
 
 			// If dry run, just log the action without actually triggering the deployment
 			if dryRun {
-				logger.Info("Dry run mode: Would trigger Vercel redeployment using hook URL")
-				logger.Info("Vercel hook URL is configured correctly")
+				logger.Infof("Dry run mode: Would trigger %s redeployment using hook URL", providerNames[provider])
+				logger.Infof("%s hook URL is configured correctly", providerNames[provider])
+				if wait {
+					logger.Infof("Dry run mode: Would wait up to %s for the deployment to finish", waitTimeout)
+				}
 			} else {
 				// Trigger the redeployment
-				logger.Info("Triggering Vercel redeployment...")
-				if err := vercelService.TriggerRedeploy(cmd.Context()); err != nil {
-					return fmt.Errorf("failed to trigger Vercel redeployment: %w", err)
+				logger.Infof("Triggering %s redeployment...", providerNames[provider])
+				if err := deployer.TriggerRedeploy(cmd.Context()); err != nil {
+					return fmt.Errorf("failed to trigger %s redeployment: %w", providerNames[provider], err)
+				}
+				logger.Infof("%s redeployment triggered successfully", providerNames[provider])
+
+				// Wait for the build to finish so a failed deployment fails the step
+				if wait {
+					if err := vercelService.WaitForDeployment(cmd.Context(), cfg.VercelToken, cfg.VercelProjectID); err != nil {
+						return fmt.Errorf("Vercel deployment did not succeed: %w", err)
+					}
+				}
+
+				// Remember what was deployed
+				if state != nil && contentHash != "" {
+					state.LastDeployHash = contentHash
+					state.LastDeployAt = time.Now()
+					if err := processor.SaveState(outputDir, state); err != nil {
+						logger.Warnf("Failed to save deploy state: %v", err)
+					}
+				}
+
+				// Let the team know, with the episode from the output directory of the earlier steps
+				var title, art19URL string
+				if outputDir != "" {
+					if content, err := processor.LoadSelectedContent(processor.OutputPath(outputDir, outputPrefix, processor.SelectedContentFileName), logger); err == nil {
+						title = content.Title
+					}
+					art19URL = processor.LoadArt19EpisodeURL(outputDir)
 				}
-				logger.Info("Vercel redeployment triggered successfully")
+				notify(cmd.Context(), cfg, episodeMessage(fmt.Sprintf("🚀 Website redeployment triggered on %s", providerNames[provider]), title, art19URL), logger)
 			}
 
 			logger.Info("Step 3 completed successfully!")
@@ -292,36 +1190,58 @@ func Step3Cmd() *cobra.Command {
 	}
 
 	// Set flags
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate configuration without triggering actual redeployment")
+	cmd.Flags().IntVar(&retries, "retries", services.DefaultVercelRetries, "Number of times to retry a failed deploy hook call")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Output directory of step1; redeploy is skipped when its content is unchanged")
+	cmd.Flags().StringVar(&outputPrefix, "output-prefix", "", "Prefix of step1's file names in --output-dir, as given to step1's --output-prefix")
+	cmd.Flags().BoolVar(&forceDeploy, "force-deploy", false, "Redeploy even if the content hasn't changed since the last deploy")
+	cmd.Flags().StringVar(&provider, "provider", "", "Deployment provider: vercel or netlify (default: netlify if only NETLIFY_BUILD_HOOK is set, otherwise vercel)")
+	cmd.Flags().StringArrayVar(&hooks, "hook", nil, "Vercel deploy hook URL to fire; repeat for multiple sites (default: VERCEL_DEPLOY_HOOK, comma-separated)")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait for the deployment to finish and fail if it fails (requires VERCEL_TOKEN and VERCEL_PROJECT_ID)")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", services.DefaultVercelWaitTimeout, "Maximum time to wait for the deployment with --wait")
 
 	return cmd
 }
 
 // Step4Cmd creates a command for generating social media post text
 func Step4Cmd() *cobra.Command {
-	var verbose bool
 	var dryRun bool
 	var rssURL string
 	var spotifyShowURL string
 	var applePodcastShowURL string
 	var outputFile string
+	var outputJSON string
+	var metadataOut string
+	var post bool
+	var mastodon bool
+	var bluesky bool
+	var linkedin bool
+	var snsTemplate string
+	var youtubeChannelURL string
+	var strictURLs bool
+	var retries int
+	var requestTimeout time.Duration
+	var userAgent string
+	var withImage bool
+	var imageOut string
+	var imageBackground string
+	var imageFont string
+	var scheduleAt string
+	var queueFile string
+	var platformList string
+	var platformOutputDir string
+	var platformTemplates map[string]string
+	var since time.Duration
+	var stateFile string
+	var force bool
 
 	cmd := &cobra.Command{
 		Use:   "step4",
 		Short: "Generate SNS post",
 		Long:  `Generate text to post to social media platforms from podcast RSS feed.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Initialize logger
-			logger := logrus.New()
-			if verbose {
-				logger.SetLevel(logrus.DebugLevel)
-			} else {
-				logger.SetLevel(logrus.InfoLevel)
-			}
-			logger.SetFormatter(&logrus.TextFormatter{
-				FullTimestamp: true,
-			})
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
 
 			// Load .env file if it exists
 			if err := godotenv.Load(); err != nil {
@@ -330,6 +1250,25 @@ func Step4Cmd() *cobra.Command {
 				logger.Debug("Loaded environment variables from .env file")
 			}
 
+			// Parse the platforms to generate post text variants for
+			platforms, err := services.ParseSNSPlatforms(platformList)
+			if err != nil {
+				return fmt.Errorf("invalid --platforms: %w", err)
+			}
+
+			// Validate the scheduled time before fetching anything
+			var scheduledAt time.Time
+			if scheduleAt != "" {
+				if !post && !mastodon && !bluesky && !linkedin {
+					return fmt.Errorf("--schedule-at needs --post, --mastodon, --bluesky or --linkedin")
+				}
+				t, err := processor.ParseScheduleTime(scheduleAt, time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid --schedule-at: %w", err)
+				}
+				scheduledAt = t
+			}
+
 			// Set values from environment variables or command-line flags
 			if rssURL == "" {
 				rssURL = os.Getenv("RSS_FEED_URL")
@@ -364,44 +1303,220 @@ func Step4Cmd() *cobra.Command {
 			}
 			logger.Infof("Apple Podcast URL: %s", applePodcastShowURL)
 
+			// Read SNS settings and credentials; each platform checks the values it needs
+			cfg := config.LoadEnvConfig()
+
 			// Initialize SNS service
 			snsService := services.NewSNSService(logger)
+			if cfg.SNSHeader != "" {
+				snsService.Header = cfg.SNSHeader
+			}
+			if cfg.SNSHashtags != "" {
+				snsService.Hashtags = cfg.SNSHashtags
+			}
+			if cfg.SNSHostHandle != "" {
+				snsService.HostHandle = cfg.SNSHostHandle
+			}
+			snsService.SpotifyClientID = cfg.SpotifyClientID
+			snsService.SpotifyClientSecret = cfg.SpotifyClientSecret
+			snsService.SpotifyMarket = cfg.SpotifyMarket
+			snsService.MaxRetries = retries
+			snsService.RequestTimeout = requestTimeout
+			snsService.UserAgent = userAgent
+
+			// Load a custom post template if provided
+			if snsTemplate == "" {
+				snsTemplate = os.Getenv("SNS_TEMPLATE")
+			}
+			if snsTemplate != "" {
+				templateText, err := processor.LoadSNSTemplate(snsTemplate)
+				if err != nil {
+					return err
+				}
+				snsService.PostTemplate = templateText
+				logger.Infof("Using SNS post template from %s", snsTemplate)
+			}
+			if len(platformTemplates) > 0 {
+				snsService.PlatformTemplates = make(map[string]string)
+				for platform, path := range platformTemplates {
+					if _, err := services.PlatformLimit(platform); err != nil {
+						return fmt.Errorf("invalid --platform-template: %w", err)
+					}
+					templateText, err := processor.LoadSNSTemplate(path)
+					if err != nil {
+						return err
+					}
+					snsService.PlatformTemplates[platform] = templateText
+					logger.Infof("Using %s post template from %s", platform, path)
+				}
+			}
+
+			// A dry run checks the configuration and shows the post text without any external request
+			if dryRun {
+				if youtubeChannelURL == "" {
+					youtubeChannelURL = os.Getenv("YOUTUBE_CHANNEL_URL")
+				}
+				return logDryRunPosts(snsService, cfg, dryRunTargets{
+					services.PlatformX:        post,
+					services.PlatformMastodon: mastodon,
+					services.PlatformBluesky:  bluesky,
+					services.PlatformLinkedIn: linkedin,
+				}, platforms, spotifyShowURL, applePodcastShowURL, youtubeChannelURL, scheduledAt, logger)
+			}
 
 			// Fetch latest episode title from RSS feed
 			logger.Info("Fetching latest episode title from RSS feed...")
-			title, err := snsService.GetLatestEpisodeTitle(cmd.Context(), rssURL)
+			latestEpisode, err := snsService.GetLatestEpisode(cmd.Context(), rssURL)
 			if err != nil {
 				return fmt.Errorf("failed to fetch latest episode title: %w", err)
 			}
+			title := latestEpisode.Title
 			logger.Infof("Latest episode title: %s", title)
 
+			// Skip episodes that are too old or were already posted, unless forced
+			publishedAt, pubDateErr := latestEpisode.PublishedAt()
+			if since > 0 && !force {
+				if pubDateErr != nil {
+					return fmt.Errorf("cannot apply --since to the latest episode: %w", pubDateErr)
+				}
+				if time.Since(publishedAt) > since {
+					logger.Infof("Latest episode was published %s, more than %s ago; skipping (use --force to post anyway)",
+						publishedAt.Format(time.RFC3339), since)
+					return nil
+				}
+			}
+			// Platforms the latest episode was already posted to are skipped, so a re-run after a
+			// failed post only posts to the platforms that didn't succeed
+			var posted *processor.PostedEpisode
+			if stateFile != "" {
+				lastPosted, err := processor.LoadPostedEpisode(stateFile)
+				if err != nil {
+					return err
+				}
+				if lastPosted != nil && !force && !lastPosted.IsNewer(latestEpisode.GUID, title, publishedAt) {
+					remaining := 0
+					for _, target := range []struct {
+						platform  string
+						requested *bool
+					}{
+						{services.PlatformX, &post},
+						{services.PlatformMastodon, &mastodon},
+						{services.PlatformBluesky, &bluesky},
+						{services.PlatformLinkedIn, &linkedin},
+					} {
+						if *target.requested && lastPosted.PostedTo(target.platform) {
+							logger.Infof("Latest episode %q was already posted to %s; skipping it", title, postingPlatforms[target.platform].name)
+							*target.requested = false
+						}
+						if *target.requested {
+							remaining++
+						}
+					}
+					if remaining == 0 {
+						logger.Infof("Latest episode %q is not newer than the last posted episode %q (posted %s); skipping (use --force to post anyway)",
+							title, lastPosted.Title, lastPosted.PostedAt.Format(time.RFC3339))
+						return nil
+					}
+					posted = lastPosted
+				}
+				if posted == nil {
+					posted = &processor.PostedEpisode{GUID: latestEpisode.GUID, Title: title, PublishedAt: publishedAt}
+				}
+			}
+
+			// recordPost saves each successful post to the state file right away, so that a later
+			// platform's failure doesn't make the next run post to this platform again
+			recordPost := func(platform string) error {
+				if posted == nil {
+					return nil
+				}
+				posted.RecordPost(platform, time.Now().UTC())
+				if err := processor.SavePostedEpisode(stateFile, posted); err != nil {
+					return err
+				}
+				logger.Debugf("Recorded the %s post in %s", postingPlatforms[platform].name, stateFile)
+				return nil
+			}
+
 			// Fetch latest Spotify episode URL
 			logger.Info("Fetching latest Spotify episode URL...")
 			spotifyURL, err := snsService.GetLatestSpotifyURL(cmd.Context(), spotifyShowURL)
-			if err != nil {
-				logger.Warnf("Failed to fetch latest Spotify episode URL: %v", err)
-				logger.Warn("Using Spotify show URL as fallback")
-				spotifyURL = spotifyShowURL
+			if err := checkURLFallback("Spotify episode URL", err, strictURLs, logger); err != nil {
+				return err
 			}
 			logger.Infof("Spotify URL: %s", spotifyURL)
 
 			// Fetch latest Apple Podcast episode URL
 			logger.Info("Fetching latest Apple Podcast episode URL...")
 			appleURL, err := snsService.GetLatestApplePodcastURL(cmd.Context(), applePodcastShowURL)
-			if err != nil {
-				logger.Warnf("Failed to fetch latest Apple Podcast episode URL: %v", err)
-				logger.Warn("Using Apple Podcast show URL as fallback")
-				appleURL = applePodcastShowURL
+			if err := checkURLFallback("Apple Podcast episode URL", err, strictURLs, logger); err != nil {
+				return err
 			}
 			logger.Infof("Apple Podcast URL: %s", appleURL)
 
-			// Generate post text
-			postText := snsService.CreateSNSPostText(title, spotifyURL, appleURL)
+			// Fetch latest YouTube video URL if the show is on YouTube
+			youtubeURL := ""
+			if youtubeChannelURL == "" {
+				youtubeChannelURL = os.Getenv("YOUTUBE_CHANNEL_URL")
+			}
+			if youtubeChannelURL != "" {
+				logger.Info("Fetching latest YouTube video URL...")
+				youtubeURL, err = snsService.GetLatestYouTubeURL(cmd.Context(), youtubeChannelURL)
+				if err := checkURLFallback("YouTube video URL", err, strictURLs, logger); err != nil {
+					return err
+				}
+				logger.Infof("YouTube URL: %s", youtubeURL)
+			}
+
+			// Generate post text within the Twitter/X character limit
+			postText, truncated, err := snsService.CreatePostText(services.PlatformX, title, spotifyURL, appleURL, youtubeURL)
+			if err != nil {
+				return fmt.Errorf("failed to create post text: %w", err)
+			}
+			if truncated {
+				logger.Warnf("Post text exceeded the %d character limit and the title was truncated to fit (%d characters)",
+					services.TwitterMaxLength, services.TwitterTextLength(postText))
+			}
 
 			// Display the post text
 			logger.Info("Generated social media post text:")
 			fmt.Println("\n" + postText + "\n")
-			logger.Infof("Character count: %d", len(postText))
+			logger.Infof("Character count: %d/%d", services.TwitterTextLength(postText), services.TwitterMaxLength)
+
+			// Generate and display the variants of the other requested platforms
+			variants := map[string]string{services.PlatformX: postText}
+			for _, platform := range platforms {
+				if platform == services.PlatformX {
+					continue
+				}
+				text, truncated, err := snsService.CreatePostText(platform, title, spotifyURL, appleURL, youtubeURL)
+				if err != nil {
+					return fmt.Errorf("failed to create %s post text: %w", platform, err)
+				}
+				limit, _ := services.PlatformLimit(platform)
+				if truncated {
+					logger.Warnf("%s post exceeded the %d character limit and the title was truncated", platform, limit.MaxLength)
+				}
+				variants[platform] = text
+
+				logger.Infof("Generated %s post text:", platform)
+				fmt.Println("\n" + text + "\n")
+				logger.Infof("Character count: %d/%d", limit.Length(text), limit.MaxLength)
+			}
+
+			// Save each requested variant to its own file
+			if platformOutputDir != "" {
+				if err := os.MkdirAll(platformOutputDir, 0755); err != nil {
+					return fmt.Errorf("failed to create platform output directory: %w", err)
+				}
+				for _, platform := range platforms {
+					path := filepath.Join(platformOutputDir, processor.PlatformPostFileName(platform))
+					if err := os.WriteFile(path, []byte(variants[platform]), 0644); err != nil {
+						return fmt.Errorf("failed to save %s post text: %w", platform, err)
+					}
+					logger.Infof("%s post text saved to %s", platform, path)
+				}
+			}
 
 			// Save to file if output file is specified
 			if outputFile != "" {
@@ -412,18 +1527,329 @@ func Step4Cmd() *cobra.Command {
 				logger.Info("Post text saved to file successfully")
 			}
 
+			// Save the post with its metadata for downstream tooling
+			if outputJSON != "" {
+				postJSON := &model.SNSPost{
+					Title:          title,
+					SpotifyURL:     spotifyURL,
+					AppleURL:       appleURL,
+					YouTubeURL:     youtubeURL,
+					Text:           postText,
+					CharacterCount: services.TwitterTextLength(postText),
+					MaxLength:      services.TwitterMaxLength,
+					Truncated:      truncated,
+					Variants:       variants,
+					GeneratedAt:    time.Now().UTC(),
+				}
+				if err := processor.SaveSNSPost(outputJSON, postJSON); err != nil {
+					return err
+				}
+				logger.Infof("Post JSON saved to %s", outputJSON)
+			}
+
+			// Render the share image attached to the posts
+			var shareImage *services.ShareImage
+			if withImage {
+				if imageOut == "" {
+					imageOut = "share_image.png"
+					if outputFile != "" {
+						imageOut = strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".png"
+					}
+				}
+				imageService := services.NewImageService(logger)
+				imageService.BackgroundPath = imageBackground
+				imageService.FontPath = imageFont
+				imagePath, err := imageService.RenderShareImage(title, imageOut)
+				if err != nil {
+					return fmt.Errorf("failed to render share image: %w", err)
+				}
+				logger.Infof("Share image saved to %s", imagePath)
+				shareImage = &services.ShareImage{Path: imagePath, AltText: title}
+			}
+
+			// Publish the post to Twitter/X if requested
+			if post {
+				if err := cfg.ValidateFor(config.FeatureTwitter); err != nil {
+					return fmt.Errorf("cannot post to Twitter/X: %w", err)
+				}
+				twitterService := services.NewTwitterService(
+					cfg.TwitterAPIKey,
+					cfg.TwitterAPISecret,
+					cfg.TwitterAccessToken,
+					cfg.TwitterAccessSecret,
+					logger,
+				)
+
+				// The X API has no scheduled posts, so scheduled tweets wait in the local queue
+				if !scheduledAt.IsZero() {
+					if err := queuePost(queueFile, model.PlatformTwitter, postText, shareImage, nil, scheduledAt, logger); err != nil {
+						return err
+					}
+				} else {
+					logger.Info("Posting to Twitter/X...")
+					tweetID, err := twitterService.PostTweet(cmd.Context(), postText, shareImage)
+					if err != nil {
+						return fmt.Errorf("failed to post to Twitter/X: %w", err)
+					}
+					logger.Infof("Posted to Twitter/X: %s", services.TweetURL(tweetID))
+				}
+				if err := recordPost(services.PlatformX); err != nil {
+					return err
+				}
+			}
+
+			// Publish the post to Mastodon if requested
+			if mastodon {
+				if err := cfg.ValidateFor(config.FeatureMastodon); err != nil {
+					return fmt.Errorf("cannot post to Mastodon: %w", err)
+				}
+
+				mastodonText, truncated, err := snsService.CreatePostText(services.PlatformMastodon, title, spotifyURL, appleURL, youtubeURL)
+				if err != nil {
+					return fmt.Errorf("failed to create post text: %w", err)
+				}
+				if truncated {
+					logger.Warnf("Mastodon post exceeded the %d character limit and the title was truncated", services.MastodonMaxLength)
+				}
+
+				// Mastodon schedules statuses itself, unless they are due too soon for it
+				mastodonService := services.NewMastodonService(logger)
+				switch {
+				case scheduledAt.IsZero():
+					logger.Info("Posting to Mastodon...")
+					if err := mastodonService.PostStatus(cmd.Context(), cfg.MastodonInstanceURL, cfg.MastodonAccessToken, mastodonText, shareImage); err != nil {
+						return fmt.Errorf("failed to post to Mastodon: %w", err)
+					}
+				case time.Until(scheduledAt) < services.MastodonMinScheduleDelay:
+					logger.Infof("Mastodon can't schedule statuses less than %v ahead, using the local queue", services.MastodonMinScheduleDelay)
+					if err := queuePost(queueFile, model.PlatformMastodon, mastodonText, shareImage, nil, scheduledAt, logger); err != nil {
+						return err
+					}
+				default:
+					logger.Info("Scheduling Mastodon status...")
+					if err := mastodonService.ScheduleStatus(cmd.Context(), cfg.MastodonInstanceURL, cfg.MastodonAccessToken, mastodonText, shareImage, scheduledAt); err != nil {
+						return fmt.Errorf("failed to schedule Mastodon status: %w", err)
+					}
+				}
+				if err := recordPost(services.PlatformMastodon); err != nil {
+					return err
+				}
+			}
+
+			// Publish the post to Bluesky if requested
+			if bluesky {
+				if err := cfg.ValidateFor(config.FeatureBluesky); err != nil {
+					return fmt.Errorf("cannot post to Bluesky: %w", err)
+				}
+
+				blueskyText, truncated, err := snsService.CreatePostText(services.PlatformBluesky, title, spotifyURL, appleURL, youtubeURL)
+				if err != nil {
+					return fmt.Errorf("failed to create post text: %w", err)
+				}
+				if truncated {
+					logger.Warnf("Bluesky post exceeded the %d character limit and the title was truncated", services.BlueskyMaxLength)
+				}
+
+				// Bluesky has no scheduled posts, so scheduled posts wait in the local queue
+				if !scheduledAt.IsZero() {
+					if err := queuePost(queueFile, model.PlatformBluesky, blueskyText, shareImage, nil, scheduledAt, logger); err != nil {
+						return err
+					}
+				} else {
+					logger.Info("Posting to Bluesky...")
+					blueskyService := services.NewBlueskyService(cfg.BlueskyPDS, logger)
+					postURL, err := blueskyService.PostText(cmd.Context(), cfg.BlueskyIdentifier, cfg.BlueskyAppPassword, blueskyText, shareImage)
+					if err != nil {
+						return fmt.Errorf("failed to post to Bluesky: %w", err)
+					}
+					logger.Infof("Posted to Bluesky: %s", postURL)
+				}
+				if err := recordPost(services.PlatformBluesky); err != nil {
+					return err
+				}
+			}
+
+			// Publish the post to LinkedIn if requested
+			if linkedin {
+				if err := cfg.ValidateFor(config.FeatureLinkedIn); err != nil {
+					return fmt.Errorf("cannot post to LinkedIn: %w", err)
+				}
+
+				linkedinText, truncated, err := snsService.CreatePostText(services.PlatformLinkedIn, title, spotifyURL, appleURL, youtubeURL)
+				if err != nil {
+					return fmt.Errorf("failed to create post text: %w", err)
+				}
+				if truncated {
+					logger.Warnf("LinkedIn post exceeded the %d character limit and the title was truncated", services.LinkedInMaxLength)
+				}
+
+				// LinkedIn previews the episode page from the feed, or the Spotify episode without one
+				article := &model.PostLink{URL: latestEpisode.Link, Title: title, Description: latestEpisode.Description}
+				if article.URL == "" {
+					article.URL = spotifyURL
+				}
+				if shareImage != nil {
+					logger.Info("LinkedIn shows the link preview of the episode instead of the share image")
+				}
+
+				// LinkedIn has no scheduled posts in its API, so scheduled posts wait in the local queue
+				if !scheduledAt.IsZero() {
+					if err := queuePost(queueFile, model.PlatformLinkedIn, linkedinText, nil, article, scheduledAt, logger); err != nil {
+						return err
+					}
+				} else {
+					logger.Info("Posting to LinkedIn...")
+					linkedinService := services.NewLinkedInService(logger)
+					postURL, err := linkedinService.PostShare(cmd.Context(), cfg.LinkedInAccessToken, cfg.LinkedInAuthorURN, linkedinText,
+						&services.LinkedInArticle{URL: article.URL, Title: article.Title, Description: article.Description})
+					if err != nil {
+						return fmt.Errorf("failed to post to LinkedIn: %w", err)
+					}
+					logger.Infof("Posted to LinkedIn: %s", postURL)
+				}
+				if err := recordPost(services.PlatformLinkedIn); err != nil {
+					return err
+				}
+			}
+
+			// Record the platform URLs in the episode metadata document
+			if metadataOut != "" {
+				err := processor.UpdateMetadata(metadataOut, func(meta *model.EpisodeMetadata) {
+					if meta.Title == "" {
+						meta.Title = title
+						meta.Number = processor.ParseEpisodeNumber(title)
+						meta.Slug = processor.Slugify(title)
+					}
+					if meta.URLs == nil {
+						meta.URLs = make(map[string]string)
+					}
+					meta.URLs["rss"] = rssURL
+					meta.URLs["spotify"] = spotifyURL
+					meta.URLs["apple_podcasts"] = appleURL
+					if youtubeURL != "" {
+						meta.URLs["youtube"] = youtubeURL
+					}
+				})
+				if err != nil {
+					return fmt.Errorf("failed to write episode metadata: %w", err)
+				}
+				logger.Infof("Episode metadata written to %s", metadataOut)
+			}
+
+			if posted != nil && (post || mastodon || bluesky || linkedin) {
+				logger.Infof("Posted episode recorded in %s", stateFile)
+			}
+
 			logger.Info("Step 4 completed successfully!")
 			return nil
 		},
 	}
 
 	// Set flags
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate configuration without making external requests")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate configuration and print the post text of each platform without making external requests")
 	cmd.Flags().StringVar(&rssURL, "rss-url", "", "URL of the podcast RSS feed (required, can also be set via RSS_FEED_URL environment variable)")
 	cmd.Flags().StringVar(&spotifyShowURL, "spotify-url", "", "URL of the Spotify show (required, can also be set via SPOTIFY_SHOW_URL environment variable)")
 	cmd.Flags().StringVar(&applePodcastShowURL, "apple-url", "", "URL of the Apple Podcast show (required, can also be set via APPLE_PODCAST_URL environment variable)")
+	cmd.Flags().StringVar(&youtubeChannelURL, "youtube-url", "", "URL of the YouTube channel (optional, can also be set via YOUTUBE_CHANNEL_URL environment variable)")
 	cmd.Flags().StringVar(&outputFile, "output", "", "File to save the generated post text (optional)")
+	cmd.Flags().StringVar(&outputJSON, "output-json", "", "File to save the post text with the title, platform URLs, character count and timestamp as JSON (optional)")
+	cmd.Flags().StringVar(&metadataOut, "metadata-out", "", "Episode metadata JSON file to create or update (optional)")
+	cmd.Flags().BoolVar(&post, "post", false, "Publish the generated text to Twitter/X using the TWITTER_* credentials")
+	cmd.Flags().BoolVar(&mastodon, "mastodon", false, "Publish the generated text to Mastodon using MASTODON_INSTANCE_URL and MASTODON_ACCESS_TOKEN")
+	cmd.Flags().StringVar(&snsTemplate, "sns-template", "", "text/template file for the post text (can also be set via SNS_TEMPLATE environment variable)")
+	cmd.Flags().BoolVar(&bluesky, "bluesky", false, "Publish the generated text to Bluesky using BLUESKY_IDENTIFIER and BLUESKY_APP_PASSWORD")
+	cmd.Flags().BoolVar(&linkedin, "linkedin", false, "Publish the linkedin post text variant to LinkedIn using LINKEDIN_ACCESS_TOKEN and LINKEDIN_AUTHOR_URN")
+	cmd.Flags().BoolVar(&strictURLs, "strict", false, "Fail instead of falling back to the show URL when the latest episode URL can't be found")
+	cmd.Flags().IntVar(&retries, "retries", services.DefaultSNSRetries, "Number of times to retry a failed RSS feed or episode URL request")
+	cmd.Flags().DurationVar(&requestTimeout, "request-timeout", services.DefaultSNSRequestTimeout, "Timeout of each RSS feed or episode URL request attempt")
+	cmd.Flags().BoolVar(&withImage, "with-image", false, "Render a share image with the episode title and attach it to the posts")
+	cmd.Flags().StringVar(&imageOut, "image-out", "", "Path of the share image (default: --output with a .png extension, or share_image.png)")
+	cmd.Flags().StringVar(&imageBackground, "image-background", "", "PNG or JPEG background of the share image (default: a solid color)")
+	cmd.Flags().StringVar(&imageFont, "image-font", "", "TrueType/OpenType font of the share image title (default: a Japanese system font if found)")
+	cmd.Flags().StringVar(&userAgent, "user-agent", services.DefaultSNSUserAgent, "User-Agent header sent with RSS feed and episode URL requests")
+	cmd.Flags().StringVar(&platformList, "platforms", services.PlatformX, "Comma-separated platforms to generate post text variants for: x, mastodon, bluesky, linkedin")
+	cmd.Flags().StringVar(&platformOutputDir, "platform-output-dir", "", "Directory to save each --platforms variant to as post_<platform>.txt (optional)")
+	cmd.Flags().StringToStringVar(&platformTemplates, "platform-template", nil, "text/template file for one platform's post text as platform=path, e.g. linkedin=linkedin.tmpl (repeatable)")
+	cmd.Flags().StringVar(&scheduleAt, "schedule-at", "", "Schedule the posts for an RFC3339 time (e.g. 2025-05-01T09:00:00+09:00) instead of posting now")
+	cmd.Flags().StringVar(&queueFile, "queue-file", processor.DefaultPostQueueFile, "Queue of scheduled posts for platforms without native scheduling, published by flush-queue")
+	cmd.Flags().DurationVar(&since, "since", 0, "Skip the run if the latest episode was published longer ago than this, e.g. 24h (default: no limit)")
+	cmd.Flags().StringVar(&stateFile, "state-file", "", "File recording the last posted episode; skip the run if the latest episode is not newer (optional)")
+	cmd.Flags().BoolVar(&force, "force", false, "Post even if --since or --state-file would skip the latest episode")
 
 	return cmd
 }
+
+// dryRunEpisodeTitle stands in for the latest episode title in step4 --dry-run, which doesn't fetch the feed
+const dryRunEpisodeTitle = "<latest episode title>"
+
+// dryRunTargets maps each platform to whether step4 posts to it
+type dryRunTargets map[string]bool
+
+// postingPlatforms holds the display name of each platform and the configuration feature group it posts with
+var postingPlatforms = map[string]struct{ name, feature string }{
+	services.PlatformX:        {"Twitter/X", config.FeatureTwitter},
+	services.PlatformMastodon: {"Mastodon", config.FeatureMastodon},
+	services.PlatformBluesky:  {"Bluesky", config.FeatureBluesky},
+	services.PlatformLinkedIn: {"LinkedIn", config.FeatureLinkedIn},
+}
+
+// logDryRunPosts validates the credentials of the platforms step4 would post to and prints the text
+// it would post or generate for each platform. The show URLs stand in for the episode URLs, so
+// nothing is fetched, posted or scheduled.
+func logDryRunPosts(snsService *services.SNSService, cfg *config.Config, targets dryRunTargets, platforms []string,
+	spotifyURL, appleURL, youtubeURL string, scheduledAt time.Time, logger *logrus.Logger) error {
+	generated := make(map[string]bool)
+	for _, platform := range platforms {
+		generated[platform] = true
+	}
+
+	for _, platform := range services.SNSPlatforms {
+		if !targets[platform] && !generated[platform] {
+			continue
+		}
+		name := postingPlatforms[platform].name
+		if targets[platform] {
+			if err := cfg.ValidateFor(postingPlatforms[platform].feature); err != nil {
+				return fmt.Errorf("cannot post to %s: %w", name, err)
+			}
+		}
+
+		text, truncated, err := snsService.CreatePostText(platform, dryRunEpisodeTitle, spotifyURL, appleURL, youtubeURL)
+		if err != nil {
+			return fmt.Errorf("failed to create %s post text: %w", platform, err)
+		}
+		if truncated {
+			limit, _ := services.PlatformLimit(platform)
+			logger.Warnf("%s post exceeded the %d character limit and the title was truncated", name, limit.MaxLength)
+		}
+
+		switch {
+		case !targets[platform]:
+			logger.Infof("Dry run: %s post text that would be generated:", name)
+		case !scheduledAt.IsZero():
+			logger.Infof("Dry run: would schedule this %s post for %s:", name, scheduledAt.Format(time.RFC3339))
+		default:
+			logger.Infof("Dry run: would post this to %s:", name)
+		}
+		fmt.Println("\n" + text + "\n")
+	}
+
+	logger.Info("Dry run: the feed and episode URLs were not fetched and nothing was posted")
+	return nil
+}
+
+// checkURLFallback handles the error of a latest episode URL lookup. A fallback to the show URL
+// is logged as a warning, or returned as an error in strict mode; other errors are returned as is.
+func checkURLFallback(name string, err error, strict bool, logger *logrus.Logger) error {
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, services.ErrShowURLFallback) {
+		return fmt.Errorf("failed to fetch latest %s: %w", name, err)
+	}
+	if strict {
+		return fmt.Errorf("failed to fetch latest %s (--strict): %w", name, err)
+	}
+	logger.Warnf("Failed to fetch latest %s, using the show URL instead: %v", name, err)
+	return nil
+}
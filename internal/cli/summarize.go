@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/internal/ui"
+	"github.com/automate-podcast/services"
+	"github.com/spf13/cobra"
+)
+
+// NewSummarizeCmd creates a command for writing a short episode summary from a transcript
+func NewSummarizeCmd() *cobra.Command {
+	var inputTranscript string
+	var outputFile string
+	var openAIKey string
+	var openAIBaseURL string
+	var sentences int
+
+	cmd := &cobra.Command{
+		Use:   "summarize",
+		Short: "Summarize a transcript for directory listings",
+		Long: `Write a plain 2-3 sentence summary of an episode for podcast directory listings.
+Unlike show notes, the summary is prose without bullets, emojis or links.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			// Get OpenAI API key from flag or environment
+			if openAIKey == "" {
+				openAIKey = os.Getenv("OPENAI_API_KEY")
+				if openAIKey == "" {
+					return fmt.Errorf("OpenAI API key is required. Set it with --openai-key flag or OPENAI_API_KEY environment variable")
+				}
+			}
+
+			if sentences <= 0 {
+				return fmt.Errorf("--sentences must be at least 1")
+			}
+
+			// Load the transcript; SRT/VTT timecodes are dropped
+			transcript, err := processor.LoadTranscript(inputTranscript, logger)
+			if err != nil {
+				return fmt.Errorf("failed to load transcript: %w", err)
+			}
+
+			// Summarize the transcript
+			aiService := services.NewAIService(openAIKey, logger)
+			// Route requests through a proxy or Azure OpenAI if configured
+			if openAIBaseURL == "" {
+				openAIBaseURL = os.Getenv("OPENAI_BASE_URL")
+			}
+			aiService.SetBaseURL(openAIBaseURL, os.Getenv("AZURE_OPENAI_DEPLOYMENT"))
+			spinner := ui.StartSpinner(logger, "Summarizing transcript...")
+			summary, err := aiService.Summarize(cmd.Context(), transcript.Text, sentences)
+			spinner.Stop()
+			if err != nil {
+				return err
+			}
+
+			// Display the summary
+			fmt.Println(summary)
+
+			// Save to file if output file is specified
+			if outputFile != "" {
+				if err := os.WriteFile(outputFile, []byte(summary+"\n"), 0644); err != nil {
+					return fmt.Errorf("failed to save summary: %w", err)
+				}
+				logger.Infof("Summary saved to %s", outputFile)
+			}
+
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVarP(&inputTranscript, "input-transcript", "t", "", "Path to transcript file: plain text, SRT or VTT (required)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "File to save the summary (optional)")
+	cmd.Flags().StringVar(&openAIKey, "openai-key", "", "OpenAI API key (can also be set via OPENAI_API_KEY environment variable)")
+	cmd.Flags().StringVar(&openAIBaseURL, "openai-base-url", "", "OpenAI-compatible endpoint or Azure OpenAI resource URL (can also be set via OPENAI_BASE_URL environment variable)")
+	cmd.Flags().IntVar(&sentences, "sentences", services.DefaultSummarySentences, "Maximum number of sentences in the summary")
+
+	// Set required flags
+	if err := cmd.MarkFlagRequired("input-transcript"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return cmd
+}
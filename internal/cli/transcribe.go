@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/internal/ui"
+	"github.com/automate-podcast/services"
+	"github.com/spf13/cobra"
+)
+
+// NewTranscribeCmd creates a command for transcribing an audio file with OpenAI Whisper
+func NewTranscribeCmd() *cobra.Command {
+	var inputAudio string
+	var outputFile string
+	var openAIKey string
+	var openAIBaseURL string
+	var language string
+	var format string
+	var chunkSizeMB int
+	var whisperPrompt string
+	var vocabFile string
+	var backend string
+	var whisperModel string
+	var whisperBinary string
+
+	cmd := &cobra.Command{
+		Use:   "transcribe",
+		Short: "Transcribe an audio file",
+		Long: `Transcribe an audio file with OpenAI Whisper, or locally with whisper.cpp, and save the
+transcript for use as the input of step1.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			// Check the settings of the selected backend
+			switch backend {
+			case services.TranscriptionBackendOpenAI:
+				// Get OpenAI API key from flag or environment
+				if openAIKey == "" {
+					openAIKey = os.Getenv("OPENAI_API_KEY")
+					if openAIKey == "" {
+						return fmt.Errorf("OpenAI API key is required. Set it with --openai-key flag or OPENAI_API_KEY environment variable")
+					}
+				}
+			case services.TranscriptionBackendLocal:
+				if whisperModel == "" {
+					return fmt.Errorf("--whisper-model is required with --backend local, e.g. --whisper-model models/ggml-large-v3.bin")
+				}
+			default:
+				return fmt.Errorf("unknown backend %q, expected openai or local", backend)
+			}
+
+			// Parse the requested output formats
+			formats, err := processor.ParseTranscriptFormats(format)
+			if err != nil {
+				return err
+			}
+
+			// Audio chunks must stay below the Whisper upload limit
+			if chunkSizeMB <= 0 || chunkSizeMB > services.WhisperMaxFileSize>>20 {
+				return fmt.Errorf("--chunk-size-mb must be between 1 and %d", services.WhisperMaxFileSize>>20)
+			}
+
+			// Build the vocabulary prompt from the glossary and vocabulary file
+			var vocabulary string
+			if vocabFile != "" {
+				data, err := os.ReadFile(vocabFile)
+				if err != nil {
+					return fmt.Errorf("failed to read vocabulary file: %w", err)
+				}
+				vocabulary = string(data)
+			}
+			prompt, truncated := services.BuildWhisperPrompt(whisperPrompt, vocabulary)
+			if truncated {
+				logger.Warnf("Vocabulary is longer than Whisper's %d token prompt limit; only the first terms are used: %s", services.WhisperPromptMaxTokens, prompt)
+			}
+
+			// Outputs are named <dir>/<base>.<ext>, derived from --output or the audio file name
+			if outputFile == "" {
+				outputFile = inputAudio
+			}
+			outputDir := filepath.Dir(outputFile)
+			outputBase := strings.TrimSuffix(filepath.Base(outputFile), filepath.Ext(outputFile))
+
+			// Initialize the transcription backend
+			var transcriber services.Transcriber
+			if backend == services.TranscriptionBackendLocal {
+				transcriber = services.NewLocalWhisperService(whisperBinary, whisperModel, logger)
+			} else {
+				transcriptionService := services.NewTranscriptionService(openAIKey, logger)
+				transcriptionService.ChunkSize = int64(chunkSizeMB) << 20
+				// Route requests through a proxy or Azure OpenAI if configured
+				if openAIBaseURL == "" {
+					openAIBaseURL = os.Getenv("OPENAI_BASE_URL")
+				}
+				transcriptionService.SetBaseURL(openAIBaseURL, os.Getenv("AZURE_OPENAI_WHISPER_DEPLOYMENT"))
+				transcriber = transcriptionService
+			}
+
+			// Transcribe the audio once, with segments if any format needs them
+			transcribeOpts := services.TranscribeOptions{
+				Language: language,
+				Prompt:   prompt,
+			}
+			result := &services.TranscriptionResult{}
+			spinner := ui.StartSpinner(logger, "Transcribing audio...")
+			if processor.RequiresSegments(formats) {
+				result, err = transcriber.TranscribeWithTimestamps(cmd.Context(), inputAudio, transcribeOpts)
+			} else {
+				result.Text, err = transcriber.Transcribe(cmd.Context(), inputAudio, transcribeOpts)
+			}
+			spinner.Stop()
+			if err != nil {
+				return fmt.Errorf("transcription failed: %w", err)
+			}
+
+			// Write each requested format
+			for _, f := range formats {
+				content, err := processor.RenderTranscript(result, f)
+				if err != nil {
+					return err
+				}
+
+				path := processor.TranscriptOutputPath(outputDir, outputBase, f)
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					return fmt.Errorf("failed to save %s transcript: %w", f, err)
+				}
+				logger.Infof("Transcript (%s) saved to %s", f, path)
+			}
+
+			logger.Infof("Next: podcast-cli process step1 --input-transcript %s", processor.TranscriptOutputPath(outputDir, outputBase, formats[0]))
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVarP(&inputAudio, "input-audio", "a", "", "Path to audio file (required)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Path to save the transcript; other formats use the same name with their own extension (default: next to the audio file)")
+	cmd.Flags().StringVar(&openAIKey, "openai-key", "", "OpenAI API key (can also be set via OPENAI_API_KEY environment variable)")
+	cmd.Flags().StringVar(&openAIBaseURL, "openai-base-url", "", "OpenAI-compatible endpoint or Azure OpenAI resource URL (can also be set via OPENAI_BASE_URL environment variable)")
+	cmd.Flags().StringVar(&language, "language", "", "Language of the audio as an ISO-639-1 code (e.g. ja), auto-detected if empty")
+	cmd.Flags().IntVar(&chunkSizeMB, "chunk-size-mb", services.DefaultChunkSize>>20, "Size in MB of the chunks that audio files over Whisper's 25 MB limit are split into (uses ffmpeg if installed)")
+	cmd.Flags().StringVar(&whisperPrompt, "whisper-prompt", "", "Comma-separated glossary of names and terms to bias recognition (limited to ~224 tokens)")
+	cmd.Flags().StringVar(&vocabFile, "vocab-file", "", "File of names and terms to bias recognition, one per line or comma-separated (combined with --whisper-prompt)")
+	cmd.Flags().StringVar(&format, "format", "text", "Comma-separated output formats: text, srt, vtt, json")
+	cmd.Flags().StringVar(&backend, "backend", services.TranscriptionBackendOpenAI, "Transcription backend: openai (Whisper API) or local (whisper.cpp on this machine)")
+	cmd.Flags().StringVar(&whisperModel, "whisper-model", "", "Path to the whisper.cpp ggml model file (required with --backend local)")
+	cmd.Flags().StringVar(&whisperBinary, "whisper-binary", services.DefaultWhisperBinary, "whisper.cpp command line program used with --backend local")
+
+	// Set required flags
+	if err := cmd.MarkFlagRequired("input-audio"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return cmd
+}
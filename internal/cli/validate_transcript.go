@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/automate-podcast/internal/processor"
+	"github.com/automate-podcast/services"
+	"github.com/spf13/cobra"
+)
+
+// NewValidateTranscriptCmd creates a command for checking that a transcript is usable before generation
+func NewValidateTranscriptCmd() *cobra.Command {
+	var inputTranscript string
+	var language string
+	var maxTranscriptTokens int
+
+	cmd := &cobra.Command{
+		Use:   "validate-transcript",
+		Short: "Check that a transcript is usable before generation",
+		Long: `Load a transcript the way step1 does and report its size, estimated token count,
+detected language and encoding, without calling any API. Exits with an error if the
+transcript looks unusable, e.g. a binary file, an undecodable encoding or an empty file.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get the logger initialized by the root command
+			logger := loggerFromContext(cmd.Context())
+
+			// Load the transcript; binary files and undecodable encodings fail here
+			transcript, err := processor.LoadTranscript(inputTranscript, logger)
+			if err != nil {
+				fmt.Printf("FAIL %v\n", err)
+				return fmt.Errorf("transcript %s is not usable", inputTranscript)
+			}
+
+			tokens := services.EstimateTokens(transcript.Text)
+			fmt.Printf("File:       %s\n", inputTranscript)
+			fmt.Printf("Format:     %s\n", transcript.Format)
+			fmt.Printf("Encoding:   %s\n", transcript.Encoding)
+			fmt.Printf("Characters: %d\n", utf8.RuneCountInString(transcript.Text))
+			fmt.Printf("Words:      %d\n", len(strings.Fields(transcript.Text)))
+			if tokens > maxTranscriptTokens {
+				fmt.Printf("Tokens:     about %d (over %d, step1 chunks and summarizes it first)\n", tokens, maxTranscriptTokens)
+			} else {
+				fmt.Printf("Tokens:     about %d\n", tokens)
+			}
+
+			detected := processor.DetectLanguage(transcript.Text)
+			if name, err := services.LanguageName(detected); err == nil {
+				fmt.Printf("Language:   %s (%s)\n", name, detected)
+			} else {
+				fmt.Println("Language:   unknown")
+			}
+			if len(transcript.Segments) > 0 {
+				fmt.Printf("Segments:   %d timed segments\n", len(transcript.Segments))
+			}
+			if len(transcript.Turns) > 0 {
+				fmt.Printf("Speakers:   %s (%d turns)\n", strings.Join(services.TurnSpeakers(transcript.Turns), ", "), len(transcript.Turns))
+			}
+
+			// Warn about a language other than the one content will be generated in
+			if language != "" && detected != "" && !strings.EqualFold(language, detected) {
+				fmt.Printf("WARN transcript looks like %s but --language is %s\n", detected, language)
+			}
+
+			problems := transcript.Problems()
+			for _, problem := range problems {
+				fmt.Printf("FAIL %s\n", problem)
+			}
+			if len(problems) > 0 {
+				return fmt.Errorf("transcript %s is not usable", inputTranscript)
+			}
+
+			fmt.Println("OK   transcript is ready for generation")
+			return nil
+		},
+	}
+
+	// Set flags
+	cmd.Flags().StringVarP(&inputTranscript, "input-transcript", "t", "", "Path to transcript file: plain text, SRT or VTT (required)")
+	cmd.Flags().StringVar(&language, "language", "", "Warn if the transcript isn't in this language, as an ISO-639-1 code (e.g. ja, en)")
+	cmd.Flags().IntVar(&maxTranscriptTokens, "max-transcript-tokens", services.DefaultMaxTranscriptTokens, "Token count above which step1 chunks and summarizes the transcript first")
+
+	// Set required flags
+	if err := cmd.MarkFlagRequired("input-transcript"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return cmd
+}
@@ -1,13 +1,19 @@
 package model
 
+// CandidatesSchemaVersion is the version of the saved ContentCandidates schema
+const CandidatesSchemaVersion = 1
+
 // ContentCandidates is a struct that holds content candidates generated by AI
 type ContentCandidates struct {
-	Titles      []string   // Title proposal (single optimal title)
-	ShowNotes   []string   // ShowNote proposal (single optimal show note)
+	SchemaVersion int        `json:"schema_version"`
+	Titles        []string   `json:"titles"`                 // Title candidates
+	ShowNotes     []string   `json:"show_notes"`             // Show note candidates
+	AdTimecodes   [][]string `json:"ad_timecodes,omitempty"` // Suggested ad breaks as [HH:MM:SS, rationale] pairs
+	Chapters      []Chapter  `json:"chapters,omitempty"`     // Suggested chapter markers in chronological order
 }
 
 // SelectedContent is a struct that holds content selected by the user
 type SelectedContent struct {
-	Title       string   // Selected title
-	ShowNote    string   // Selected show note
+	Title    string `json:"title"`     // Selected title
+	ShowNote string `json:"show_note"` // Selected show note
 }
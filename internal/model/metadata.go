@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// MetadataSchemaVersion is the version of the EpisodeMetadata document schema
+const MetadataSchemaVersion = 1
+
+// EpisodeMetadata is the canonical record of an episode's generated metadata.
+// It is written as a JSON sidecar and filled in progressively by each pipeline step.
+type EpisodeMetadata struct {
+	SchemaVersion   int               `json:"schema_version"`
+	Title           string            `json:"title,omitempty"`
+	Number          int               `json:"number,omitempty"`
+	Season          int               `json:"season,omitempty"`
+	Summary         string            `json:"summary,omitempty"`
+	ShowNote        string            `json:"show_note,omitempty"`
+	ShowNoteHTML    string            `json:"show_note_html,omitempty"`
+	Tags            []string          `json:"tags,omitempty"`
+	Chapters        []Chapter         `json:"chapters,omitempty"`
+	DurationSeconds float64           `json:"duration_seconds,omitempty"`
+	PullQuote       string            `json:"pull_quote,omitempty"`
+	Slug            string            `json:"slug,omitempty"`
+	Hosts           []string          `json:"hosts,omitempty"`
+	URLs            map[string]string `json:"urls,omitempty"` // Platform name to URL
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// Chapter is a chapter marker within an episode
+type Chapter struct {
+	StartSeconds float64 `json:"start_seconds"`
+	Title        string  `json:"title"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// SNSPost is the generated social media post of an episode, written by step4 for downstream tooling
+type SNSPost struct {
+	Title          string            `json:"title"`
+	SpotifyURL     string            `json:"spotify_url"`
+	AppleURL       string            `json:"apple_podcasts_url"`
+	YouTubeURL     string            `json:"youtube_url,omitempty"`
+	Text           string            `json:"text"`
+	CharacterCount int               `json:"character_count"` // Length as counted by Twitter/X
+	MaxLength      int               `json:"max_length"`
+	Truncated      bool              `json:"truncated"`          // Whether the title was shortened to fit MaxLength
+	Variants       map[string]string `json:"variants,omitempty"` // Post text by platform, including x
+	GeneratedAt    time.Time         `json:"generated_at"`
+}
+
+// Platforms that posts can be published to
+const (
+	PlatformTwitter  = "twitter"
+	PlatformMastodon = "mastodon"
+	PlatformBluesky  = "bluesky"
+	PlatformLinkedIn = "linkedin"
+)
+
+// ScheduledPost is a post waiting in the local queue until it is due
+type ScheduledPost struct {
+	ID          string    `json:"id"`
+	Platform    string    `json:"platform"`
+	Text        string    `json:"text"`
+	ImagePath   string    `json:"image_path,omitempty"`
+	ImageAlt    string    `json:"image_alt,omitempty"`
+	Article     *PostLink `json:"article,omitempty"` // Link shared as an article, used by LinkedIn
+	ScheduledAt time.Time `json:"scheduled_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	Attempts    int       `json:"attempts,omitempty"`
+	LastError   string    `json:"last_error,omitempty"` // Error of the last failed publish attempt
+}
+
+// PostLink is a link shared with a post along with its preview title and description
+type PostLink struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PostQueue is the local queue of scheduled posts for platforms without native scheduling
+type PostQueue struct {
+	Posts []ScheduledPost `json:"posts"`
+}
@@ -2,17 +2,35 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/automate-podcast/internal/model"
 	"github.com/automate-podcast/services"
 	"github.com/sirupsen/logrus"
 )
 
+// ErrDuplicateEpisode is returned by UploadDraft when the episode is already in the RSS feed
+var ErrDuplicateEpisode = errors.New("episode already published")
+
 // Art19Processor is responsible for uploading content to Art19
 type Art19Processor struct {
 	art19Service *services.Art19Service
 	logger       *logrus.Logger
+
+	// RSSFeedURL is the podcast feed checked for an already published episode
+	// with the same number or title. The check is skipped if it is empty.
+	RSSFeedURL string
+	// AllowDuplicates only warns about an already published episode instead of aborting
+	AllowDuplicates bool
+	// Verify reads the draft back after the upload and warns if its title or show note
+	// doesn't match what was sent
+	Verify bool
 }
 
 // NewArt19Processor creates a new Art19Processor instance
@@ -23,17 +41,28 @@ func NewArt19Processor(art19Service *services.Art19Service, logger *logrus.Logge
 	}
 }
 
-// UploadDraft uploads the selected content to Art19 as a draft
-func (p *Art19Processor) UploadDraft(ctx context.Context, audioPath string, content *model.SelectedContent) error {
-	// If no audio file is specified, upload only the title as a draft
+// UploadDraft uploads the selected content to Art19 as a draft and returns the created episode
+func (p *Art19Processor) UploadDraft(ctx context.Context, audioPath string, content *model.SelectedContent) (*services.Art19Episode, error) {
+	// Make sure the episode hasn't been published already, e.g. by an earlier run of the pipeline
+	if err := p.checkDuplicate(ctx, content); err != nil {
+		return nil, err
+	}
+
+	// If no audio file is specified, upload only the title and show note as a draft
 	if audioPath == "" {
-		p.logger.Info("No audio file specified, uploading only title to Art19 as draft")
+		p.logger.Info("No audio file specified, uploading title and show note to Art19 as draft")
 		p.logger.Infof("Uploading draft title: %s", content.Title)
-		if err := p.art19Service.UploadDraftTitle(ctx, content.Title); err != nil {
-			return fmt.Errorf("failed to upload draft title to Art19: %w", err)
+		episode, err := p.art19Service.UploadDraftTitleAndShowNote(ctx, content.Title, content.ShowNote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload draft to Art19: %w", err)
 		}
-		p.logger.Info("Successfully uploaded draft title to Art19!")
-		return nil
+		if p.art19Service.DryRun {
+			return episode, nil
+		}
+		p.logger.Info("Successfully uploaded draft title and show note to Art19!")
+		p.logEpisode(episode)
+		p.verifyDraft(ctx, episode, content)
+		return episode, nil
 	}
 
 	// If audio file is present, proceed with full upload (existing logic)
@@ -46,11 +75,133 @@ func (p *Art19Processor) UploadDraft(ctx context.Context, audioPath string, cont
 	
 	// Upload to Art19
 	p.logger.Info("Uploading to Art19 as draft...")
-	err := p.art19Service.PublishEpisode(ctx, audioPath, content.Title, content.ShowNote)
+	episode, err := p.art19Service.PublishEpisode(ctx, audioPath, content.Title, content.ShowNote)
 	if err != nil {
-		return fmt.Errorf("failed to upload to Art19: %w", err)
+		return nil, fmt.Errorf("failed to upload to Art19: %w", err)
+	}
+	if p.art19Service.DryRun {
+		return episode, nil
 	}
 	
 	p.logger.Info("Successfully uploaded draft to Art19!")
-	return nil
+	p.logEpisode(episode)
+	p.verifyDraft(ctx, episode, content)
+	return episode, nil
+}
+
+// verifyDraft reads the uploaded draft back when Verify is set and warns if the title or show note
+// didn't land, e.g. when the Playwright script "succeeded" without filling the form.
+// The upload itself succeeded, so failures are only logged.
+func (p *Art19Processor) verifyDraft(ctx context.Context, episode *services.Art19Episode, content *model.SelectedContent) {
+	if !p.Verify {
+		return
+	}
+	if episode.ID == "" {
+		p.logger.Warn("Cannot verify the Art19 draft: the Playwright script did not report its episode ID")
+		return
+	}
+
+	p.logger.Info("Verifying the Art19 draft...")
+	draft, err := p.art19Service.ReadEpisode(ctx, episode.ID)
+	if err != nil {
+		p.logger.Warnf("Cannot verify the Art19 draft: %v", err)
+		return
+	}
+
+	ok := true
+	if got, want := strings.TrimSpace(draft.Title), strings.TrimSpace(content.Title); got != want {
+		p.logger.Warnf("Art19 draft title doesn't match: got %q, sent %q", got, want)
+		ok = false
+	}
+	if content.ShowNote != "" {
+		got, want := htmlText(draft.ShowNote), htmlText(services.FormatShowNoteHTML(content.ShowNote))
+		switch {
+		case got == "":
+			p.logger.Warn("Art19 draft show note is empty, but a show note was sent")
+			ok = false
+		case got != want:
+			p.logger.Warnf("Art19 draft show note doesn't match what was sent (%d characters on Art19, %d sent)", len([]rune(got)), len([]rune(want)))
+			ok = false
+		}
+	}
+	if ok {
+		p.logger.Info("Verified: the Art19 draft has the title and show note that were sent")
+	}
+}
+
+// htmlTagPattern matches an HTML tag
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// htmlText returns the text of an HTML fragment with whitespace collapsed, for comparing
+// HTML that an editor may have reformatted
+func htmlText(fragment string) string {
+	text := html.UnescapeString(htmlTagPattern.ReplaceAllString(fragment, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// checkDuplicate looks for an episode with the same number or title in the RSS feed.
+// A feed that can't be fetched only produces a warning, so an outage doesn't block uploads.
+func (p *Art19Processor) checkDuplicate(ctx context.Context, content *model.SelectedContent) error {
+	if p.RSSFeedURL == "" {
+		p.logger.Debug("RSS feed URL not set, skipping duplicate episode check")
+		return nil
+	}
+
+	number := ParseEpisodeNumber(content.Title)
+	existing, err := services.NewSNSService(p.logger).FindEpisode(ctx, p.RSSFeedURL, number, content.Title)
+	if err != nil {
+		p.logger.Warnf("Could not check the RSS feed for a duplicate episode: %v", err)
+		return nil
+	}
+	if existing == nil {
+		p.logger.Debugf("Episode %d is not in the RSS feed yet", number)
+		return nil
+	}
+
+	if p.AllowDuplicates {
+		p.logger.Warnf("An episode matching %q is already published: %q (%s)", content.Title, existing.Title, existing.Link)
+		return nil
+	}
+	return fmt.Errorf("%w: %q matches %q (%s); use --no-duplicate-check to upload anyway", ErrDuplicateEpisode, content.Title, existing.Title, existing.Link)
+}
+
+// logEpisode logs the created episode's URL or ID prominently
+func (p *Art19Processor) logEpisode(episode *services.Art19Episode) {
+	switch {
+	case episode.URL != "":
+		p.logger.Infof(">>> Art19 draft episode: %s", episode.URL)
+	case episode.ID != "":
+		p.logger.Infof(">>> Art19 draft episode ID: %s", episode.ID)
+	default:
+		p.logger.Warn("The Playwright script did not report the created episode's URL or ID")
+	}
+}
+
+// Art19EpisodeFileName is the name of the Art19 episode reference that step2 saves in the output directory
+const Art19EpisodeFileName = "art19_episode.txt"
+
+// LoadArt19EpisodeURL returns the episode URL from the Art19 episode reference in dir,
+// or an empty string if step2 hasn't saved one
+func LoadArt19EpisodeURL(dir string) string {
+	return loadArt19EpisodeField(dir, "URL: ")
+}
+
+// LoadArt19EpisodeID returns the episode ID from the Art19 episode reference in dir,
+// or an empty string if step2 hasn't saved one
+func LoadArt19EpisodeID(dir string) string {
+	return loadArt19EpisodeField(dir, "ID: ")
+}
+
+// loadArt19EpisodeField returns the value of the line starting with prefix in the Art19 episode reference in dir
+func loadArt19EpisodeField(dir, prefix string) string {
+	data, err := os.ReadFile(filepath.Join(dir, Art19EpisodeFileName))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, prefix); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
 }
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// CandidatesFileName is the name of the machine-readable candidates file in the output directory
+const CandidatesFileName = "candidates.json"
+
+// LoadCandidates reads content candidates saved by SaveCandidates
+func LoadCandidates(path string) (*model.ContentCandidates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read candidates file: %w", err)
+	}
+
+	var candidates model.ContentCandidates
+	if err := json.Unmarshal(data, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to parse candidates file: %w", err)
+	}
+	if candidates.SchemaVersion > model.CandidatesSchemaVersion {
+		return nil, fmt.Errorf("candidates schema version %d is newer than supported version %d", candidates.SchemaVersion, model.CandidatesSchemaVersion)
+	}
+	if len(candidates.Titles) == 0 && len(candidates.ShowNotes) == 0 {
+		return nil, fmt.Errorf("candidates file %s contains no candidates", path)
+	}
+
+	return &candidates, nil
+}
+
+// SaveCandidates writes content candidates as indented JSON
+func SaveCandidates(path string, candidates *model.ContentCandidates) error {
+	candidates.SchemaVersion = model.CandidatesSchemaVersion
+
+	data, err := json.MarshalIndent(candidates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal candidates: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write candidates file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// ChaptersXMLFileName is the name of the Podlove Simple Chapters file written by step1
+const ChaptersXMLFileName = "chapters.xml"
+
+// ChaptersTextFileName is the name of the plain-text chapter list written by step1
+const ChaptersTextFileName = "chapters.txt"
+
+// pscNamespace is the XML namespace of Podlove Simple Chapters
+const pscNamespace = "http://podlove.org/simple-chapters"
+
+// pscChapters is the root element of a Podlove Simple Chapters document
+type pscChapters struct {
+	XMLName  xml.Name     `xml:"psc:chapters"`
+	Version  string       `xml:"version,attr"`
+	XMLNS    string       `xml:"xmlns:psc,attr"`
+	Chapters []pscChapter `xml:"psc:chapter"`
+}
+
+// pscChapter is a single chapter of a Podlove Simple Chapters document
+type pscChapter struct {
+	Start string `xml:"start,attr"`
+	Title string `xml:"title,attr"`
+}
+
+// ChaptersToPSC formats chapters as a Podlove Simple Chapters (PSC) XML document
+func ChaptersToPSC(chapters []model.Chapter) (string, error) {
+	doc := pscChapters{Version: "1.2", XMLNS: pscNamespace}
+	for _, chapter := range chapters {
+		doc.Chapters = append(doc.Chapters, pscChapter{
+			Start: formatTimestamp(chapter.StartSeconds, "."),
+			Title: chapter.Title,
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal chapters: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// ChaptersToText formats chapters as "HH:MM:SS Title" lines, the format YouTube and most
+// show note pages recognize
+func ChaptersToText(chapters []model.Chapter) string {
+	var b strings.Builder
+	for _, chapter := range chapters {
+		// Drop the milliseconds of HH:MM:SS.mmm
+		fmt.Fprintf(&b, "%s %s\n", formatTimestamp(chapter.StartSeconds, ".")[:8], chapter.Title)
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package processor
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultFillerWords are the filler words removed by CleanTranscript per language code.
+// Japanese fillers are only removed when followed by punctuation, a space or the end of
+// a line, so that e.g. "あの人" and "その後" are kept.
+var DefaultFillerWords = map[string][]string{
+	"ja": {"えーっと", "えっと", "ええと", "えーと", "えー", "あのー", "あの", "そのー", "その", "うーん", "んー", "まあ", "なんか"},
+	"en": {"um", "umm", "uh", "uhh", "uh-huh", "er", "erm", "hmm", "mm-hmm"},
+}
+
+// CleanOptions configures CleanTranscript
+type CleanOptions struct {
+	// FillerWords are removed where they stand alone; see FillerWordsFor for the defaults
+	FillerWords []string
+	// RemoveSpeakerLabels removes labels such as "Speaker 1:", "田中：" or "[Host]" at the start of lines
+	RemoveSpeakerLabels bool
+}
+
+// FillerWordsFor returns the default filler words of a language code, or nil if there are none
+func FillerWordsFor(language string) []string {
+	return DefaultFillerWords[strings.ToLower(language)]
+}
+
+var (
+	// speakerLabelPattern matches a speaker label at the start of a line. A half-width colon
+	// must be followed by a space so that timecodes and URLs are kept.
+	speakerLabelPattern = regexp.MustCompile(`(?m)^[ \t]*(?:\[[^\]\n]{1,30}\][ \t]*|[^\s:：\[][^:：\n]{0,29}(?::[ \t]+|：[ \t]*))`)
+	// horizontalSpacePattern matches runs of spaces, including full-width ones
+	horizontalSpacePattern = regexp.MustCompile(`[ \t\x{3000}]+`)
+	// blankLinesPattern matches more than one blank line
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+	// orphanPunctuationPattern matches a comma or period left at the start of a line or after
+	// a sentence end when the filler before it was removed
+	orphanPunctuationPattern = regexp.MustCompile(`(?m)(^[ \t]*|[。！？][ \t]*|[.!?][ \t]+)(?:[、，,。]|\.(?:[ \t]|$))[ \t]*`)
+	// danglingCommaPattern matches a comma left before a sentence end when the filler after it was removed
+	danglingCommaPattern = regexp.MustCompile(`(?m)[、，,][ \t]*([。！？]|[.!?](?:[ \t]|$))`)
+)
+
+// CleanTranscript removes filler words and optionally speaker labels from a transcript,
+// and collapses repeated whitespace, so that they don't dilute the AI output
+func CleanTranscript(text string, opts CleanOptions) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	if opts.RemoveSpeakerLabels {
+		text = speakerLabelPattern.ReplaceAllString(text, "")
+	}
+
+	if patterns := fillerPatterns(opts.FillerWords); len(patterns) > 0 {
+		// A match consumes the separator before the next filler, so repeat until nothing changes
+		for {
+			cleaned := text
+			for _, pattern := range patterns {
+				cleaned = pattern.ReplaceAllString(cleaned, "$1$2")
+			}
+			cleaned = orphanPunctuationPattern.ReplaceAllString(cleaned, "$1")
+			cleaned = danglingCommaPattern.ReplaceAllString(cleaned, "$1")
+			if cleaned == text {
+				break
+			}
+			text = cleaned
+		}
+	}
+
+	// Collapse whitespace
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(horizontalSpacePattern.ReplaceAllString(line, " "))
+	}
+	text = blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+
+	return strings.TrimSpace(text)
+}
+
+// fillerPatterns builds regexps that match any of the filler words standing alone, with
+// the comma or spaces that follow it. Group 1 is the text kept before the filler and
+// group 2 the sentence end kept after it.
+func fillerPatterns(words []string) []*regexp.Regexp {
+	var spaced, unspaced []string
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		// Words of languages written with spaces must also start at a word boundary
+		r, _ := utf8.DecodeRuneInString(word)
+		if unicode.In(r, unicode.Latin) {
+			spaced = append(spaced, word)
+		} else {
+			unspaced = append(unspaced, word)
+		}
+	}
+
+	const separator = `(?:[、，,…][ \t]*|[ \t]+|([。！？.!?」』)）]|$))`
+	var patterns []*regexp.Regexp
+	if len(spaced) > 0 {
+		patterns = append(patterns, regexp.MustCompile(`(?im)(^|[^\p{L}\p{N}'’-])(?:`+quoteAlternatives(spaced)+`)`+separator))
+	}
+	if len(unspaced) > 0 {
+		patterns = append(patterns, regexp.MustCompile(`(?m)()(?:`+quoteAlternatives(unspaced)+`)ー*`+separator))
+	}
+	return patterns
+}
+
+// quoteAlternatives joins words into a regexp alternation, longest first so that e.g.
+// "えーっと" is matched before "えー"
+func quoteAlternatives(words []string) string {
+	sorted := append([]string{}, words...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return utf8.RuneCountInString(sorted[i]) > utf8.RuneCountInString(sorted[j])
+	})
+	quoted := make([]string, len(sorted))
+	for i, word := range sorted {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	return strings.Join(quoted, "|")
+}
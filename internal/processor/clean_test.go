@@ -0,0 +1,135 @@
+package processor
+
+import "testing"
+
+func TestCleanTranscriptFillerWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		text     string
+		want     string
+	}{
+		{
+			name:     "japanese fillers followed by punctuation",
+			language: "ja",
+			text:     "えーっと、今日は夜泣きの話です。まあ、大変ですよね。",
+			want:     "今日は夜泣きの話です。大変ですよね。",
+		},
+		{
+			name:     "japanese fillers with long vowels and spaces",
+			language: "ja",
+			text:     "あのーー 最近 うーん、アプリを使ってます",
+			want:     "最近 アプリを使ってます",
+		},
+		{
+			name:     "repeated japanese fillers",
+			language: "ja",
+			text:     "えー、えー、あの、始めます。",
+			want:     "始めます。",
+		},
+		{
+			name:     "japanese filler before a sentence end",
+			language: "ja",
+			text:     "それは、なんか。",
+			want:     "それは。",
+		},
+		{
+			name:     "japanese words that contain fillers are kept",
+			language: "ja",
+			text:     "あの人はその後まあまあ元気です。",
+			want:     "あの人はその後まあまあ元気です。",
+		},
+		{
+			name:     "english fillers",
+			language: "en",
+			text:     "Um, so we tried the app. Uh it was, uh, great.",
+			want:     "so we tried the app. it was, great.",
+		},
+		{
+			name:     "english words that contain fillers are kept",
+			language: "en",
+			text:     "The umbrella was erm, a hummer-sized thing.",
+			want:     "The umbrella was a hummer-sized thing.",
+		},
+		{
+			name:     "english filler at the end of a sentence",
+			language: "en",
+			text:     "That's fine, hmm. Next topic.",
+			want:     "That's fine. Next topic.",
+		},
+		{
+			name:     "unknown language keeps fillers",
+			language: "fr",
+			text:     "えー、Um, bonjour.",
+			want:     "えー、Um, bonjour.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CleanTranscript(tt.text, CleanOptions{FillerWords: FillerWordsFor(tt.language)})
+			if got != tt.want {
+				t.Errorf("CleanTranscript() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanTranscriptSpeakerLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english labels",
+			text: "Speaker 1: Hello there.\nSpeaker 2: Hi!",
+			want: "Hello there.\nHi!",
+		},
+		{
+			name: "japanese labels with full-width colons",
+			text: "田中：こんにちは。\n佐藤： はい。",
+			want: "こんにちは。\nはい。",
+		},
+		{
+			name: "bracketed labels",
+			text: "[Host] Welcome back.\n  [Guest]Thanks.",
+			want: "Welcome back.\nThanks.",
+		},
+		{
+			name: "timecodes and urls are kept",
+			text: "See 10:30 in the episode at https://example.com",
+			want: "See 10:30 in the episode at https://example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanTranscript(tt.text, CleanOptions{RemoveSpeakerLabels: true}); got != tt.want {
+				t.Errorf("CleanTranscript() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	text := "Speaker 1: Hello there."
+	if got := CleanTranscript(text, CleanOptions{}); got != text {
+		t.Errorf("CleanTranscript() without RemoveSpeakerLabels = %q, want %q", got, text)
+	}
+}
+
+func TestCleanTranscriptWhitespace(t *testing.T) {
+	text := "  今日は　　いい天気。\r\n\r\n\r\n\r\nNext   line\t here.  \n"
+	want := "今日は いい天気。\n\nNext line here."
+	if got := CleanTranscript(text, CleanOptions{}); got != want {
+		t.Errorf("CleanTranscript() = %q, want %q", got, want)
+	}
+}
+
+func TestFillerWordsFor(t *testing.T) {
+	if words := FillerWordsFor("JA"); len(words) == 0 {
+		t.Error("FillerWordsFor(\"JA\") is empty, want the Japanese defaults")
+	}
+	if words := FillerWordsFor("de"); words != nil {
+		t.Errorf("FillerWordsFor(\"de\") = %v, want nil", words)
+	}
+}
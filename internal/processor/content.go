@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/automate-podcast/internal/model"
 	"github.com/automate-podcast/services"
@@ -11,35 +12,44 @@ import (
 
 // ContentProcessor is responsible for content generation processing
 type ContentProcessor struct {
-	aiService *services.AIService
+	generator services.ContentGenerator
 	logger    *logrus.Logger
+
+	// SimilarityThreshold is the TitleSimilarity from which a title candidate is dropped as a
+	// near-duplicate of an earlier one (default: DefaultTitleSimilarityThreshold)
+	SimilarityThreshold float64
+	// MaxCandidates is the maximum number of title candidates kept, 0 for no limit
+	MaxCandidates int
 }
 
 // NewContentProcessor creates a new ContentProcessor instance
-func NewContentProcessor(aiService *services.AIService, logger *logrus.Logger) *ContentProcessor {
+func NewContentProcessor(generator services.ContentGenerator, logger *logrus.Logger) *ContentProcessor {
 	return &ContentProcessor{
-		aiService: aiService,
-		logger:    logger,
+		generator:           generator,
+		logger:              logger,
+		SimilarityThreshold: DefaultTitleSimilarityThreshold,
 	}
 }
 
 // GenerateCandidates generates content candidates from a transcript
-func (p *ContentProcessor) GenerateCandidates(transcript string, generateShowNotes bool) (*model.ContentCandidates, error) {
-	ctx := context.Background()
+func (p *ContentProcessor) GenerateCandidates(ctx context.Context, transcript string, generateShowNotes bool, opts services.GenerateOptions) (*model.ContentCandidates, error) {
 	result := &model.ContentCandidates{}
 
 	p.logger.Info("Starting content generation process...")
 
 	// Generate all content in a single API call
 	p.logger.Info("Generating all content in a single API call...")
-	titles, showNotes, err := p.aiService.GenerateAllContent(ctx, transcript)
+	titles, showNotes, err := p.generator.GenerateAllContent(ctx, transcript, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
-	// Store the titles
+	// Store the titles, dropping near-duplicates
 	p.logger.Infof("Generated %d title candidates", len(titles))
-	result.Titles = titles
+	result.Titles = DedupTitles(titles, p.SimilarityThreshold, p.MaxCandidates)
+	if removed := len(titles) - len(result.Titles); removed > 0 {
+		p.logger.Infof("Dropped %d similar or surplus title candidates, %d left", removed, len(result.Titles))
+	}
 
 	// If we only need titles, return early
 	if !generateShowNotes {
@@ -52,3 +62,52 @@ func (p *ContentProcessor) GenerateCandidates(transcript string, generateShowNot
 
 	return result, nil
 }
+
+// CheckShowNoteTimecodes rewrites the (MM:SS) timecodes of the show note candidates in one
+// format and removes those after the end of the timestamped transcript
+func (p *ContentProcessor) CheckShowNoteTimecodes(candidates *model.ContentCandidates, segments []services.TranscriptSegment) {
+	duration := services.TranscriptDuration(segments)
+	for i, showNote := range candidates.ShowNotes {
+		normalized, dropped := services.NormalizeShowNoteTimecodes(showNote, duration)
+		if len(dropped) > 0 {
+			p.logger.Warnf("Removed invalid or out-of-range timecodes from show note candidate %d (episode ends at %s): %s",
+				i+1, services.FormatShowNoteTimecode(duration, duration), strings.Join(dropped, ", "))
+		}
+		if services.CountShowNoteTimecodes(normalized) == 0 {
+			p.logger.Warnf("Show note candidate %d has no timecodes", i+1)
+		}
+		candidates.ShowNotes[i] = normalized
+	}
+}
+
+// GenerateAdTimecodes suggests ad break points for a timestamped transcript and stores them in the candidates
+func (p *ContentProcessor) GenerateAdTimecodes(ctx context.Context, candidates *model.ContentCandidates, segments []services.TranscriptSegment, opts services.GenerateOptions) error {
+	generator, ok := p.generator.(services.AdTimecodeGenerator)
+	if !ok {
+		return fmt.Errorf("the selected AI provider does not support ad timecode suggestions")
+	}
+
+	timecodes, err := generator.GenerateAdTimecodes(ctx, segments, opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate ad timecodes: %w", err)
+	}
+
+	candidates.AdTimecodes = timecodes
+	return nil
+}
+
+// GenerateChapters suggests chapter markers for a timestamped transcript and stores them in the candidates
+func (p *ContentProcessor) GenerateChapters(ctx context.Context, candidates *model.ContentCandidates, segments []services.TranscriptSegment, opts services.GenerateOptions) error {
+	generator, ok := p.generator.(services.ChapterGenerator)
+	if !ok {
+		return fmt.Errorf("the selected AI provider does not support chapter generation")
+	}
+
+	chapters, err := generator.GenerateChapters(ctx, segments, opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate chapters: %w", err)
+	}
+
+	candidates.Chapters = chapters
+	return nil
+}
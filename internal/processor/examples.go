@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/automate-podcast/services"
+)
+
+// LoadExamples loads few-shot style examples from a JSON file.
+// The file holds an array of {"title", "show_note", "date"} objects. The result is ordered
+// with the most recent example first; undated examples are assumed to be listed oldest first.
+func LoadExamples(path string) ([]services.StyleExample, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read examples file: %w", err)
+	}
+
+	var examples []services.StyleExample
+	if err := json.Unmarshal(data, &examples); err != nil {
+		return nil, fmt.Errorf("failed to parse examples file: %w", err)
+	}
+
+	// Drop incomplete entries
+	valid := examples[:0]
+	for _, example := range examples {
+		if example.Title != "" && example.ShowNote != "" {
+			valid = append(valid, example)
+		}
+	}
+
+	// Reverse so the last listed (newest) example comes first, then order dated examples newest first
+	for i, j := 0, len(valid)-1; i < j; i, j = i+1, j-1 {
+		valid[i], valid[j] = valid[j], valid[i]
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].Date > valid[j].Date
+	})
+
+	return valid, nil
+}
@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minLanguageLetters is the number of letters needed to guess the language of a text
+const minLanguageLetters = 20
+
+// stopWords are frequent words used to tell apart languages written in the Latin alphabet
+var stopWords = map[string][]string{
+	"en": {"the", "and", "is", "to", "of", "it", "that", "you", "in", "this"},
+	"de": {"der", "die", "und", "ist", "das", "nicht", "ich", "es", "zu", "mit"},
+	"es": {"el", "la", "que", "y", "es", "de", "los", "en", "un", "por"},
+	"fr": {"le", "la", "et", "est", "les", "des", "que", "je", "pas", "une"},
+}
+
+// DetectLanguage guesses the ISO-639-1 code of the language a transcript is written in from its
+// script, and for the Latin alphabet from its most frequent words. It returns "" if the text is
+// too short or its language isn't one of those supported by --language.
+func DetectLanguage(text string) string {
+	var kana, hangul, han, latin, letters int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < minLanguageLetters {
+		return ""
+	}
+
+	// Japanese mixes kana with kanji; Chinese has kanji only
+	switch {
+	case kana > 0 && kana+han >= latin:
+		return "ja"
+	case hangul > 0 && hangul >= han && hangul >= latin:
+		return "ko"
+	case han > latin:
+		return "zh"
+	case latin == 0:
+		return ""
+	}
+
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for language, words := range stopWords {
+			for _, stopWord := range words {
+				if word == stopWord {
+					counts[language]++
+				}
+			}
+		}
+	}
+	best := ""
+	for _, language := range []string{"en", "de", "es", "fr"} {
+		if counts[language] > 0 && (best == "" || counts[language] > counts[best]) {
+			best = language
+		}
+	}
+	return best
+}
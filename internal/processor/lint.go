@@ -0,0 +1,182 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// Lint rule targets
+const (
+	LintTargetTitle    = "title"
+	LintTargetShowNote = "show_note"
+	LintTargetAll      = "all"
+)
+
+// LintRule is a regex-based house style rule applied to generated content
+type LintRule struct {
+	Name        string  `json:"name"`
+	Pattern     string  `json:"pattern"`
+	Target      string  `json:"target"` // title, show_note or all
+	Message     string  `json:"message"`
+	Replacement *string `json:"replacement,omitempty"` // Safe auto-fix replacement, nil if not fixable
+
+	re *regexp.Regexp
+}
+
+// LintViolation is a single house style violation
+type LintViolation struct {
+	Rule    string
+	Target  string
+	Match   string
+	Message string
+	Fixable bool
+}
+
+// String formats the violation for logging
+func (v LintViolation) String() string {
+	return fmt.Sprintf("[%s] %s: %s (%q)", v.Rule, v.Target, v.Message, v.Match)
+}
+
+// Linter checks selected content against a set of house style rules
+type Linter struct {
+	rules []LintRule
+}
+
+// DefaultLintRules returns the built-in house style ruleset
+func DefaultLintRules() []LintRule {
+	fullWidthExclamation := "${1}！"
+	fullWidthQuestion := "${1}？"
+	fullWidthComma := "${1}、"
+
+	return []LintRule{
+		{
+			Name:    "no-all-caps-title",
+			Pattern: `\b[A-Z]{2,}(?:[ \t]+[A-Z]{2,})+\b|\b[A-Z]{6,}\b`,
+			Target:  LintTargetTitle,
+			Message: "titles must not use ALL-CAPS words (short acronyms like AI or GPT are fine)",
+		},
+		{
+			Name:        "full-width-exclamation",
+			Pattern:     `([\p{Han}\p{Hiragana}\p{Katakana}ー])!`,
+			Target:      LintTargetAll,
+			Message:     "use a full-width exclamation mark after Japanese text",
+			Replacement: &fullWidthExclamation,
+		},
+		{
+			Name:        "full-width-question",
+			Pattern:     `([\p{Han}\p{Hiragana}\p{Katakana}ー])\?`,
+			Target:      LintTargetAll,
+			Message:     "use a full-width question mark after Japanese text",
+			Replacement: &fullWidthQuestion,
+		},
+		{
+			Name:        "full-width-comma",
+			Pattern:     `([\p{Han}\p{Hiragana}\p{Katakana}ー]),`,
+			Target:      LintTargetAll,
+			Message:     "use a Japanese comma (、) after Japanese text",
+			Replacement: &fullWidthComma,
+		},
+		{
+			Name:    "banned-phrase",
+			Pattern: `いかがでしたか|神回`,
+			Target:  LintTargetAll,
+			Message: "phrase is banned by the editorial guide",
+		},
+	}
+}
+
+// LoadLintRules reads a JSON array of lint rules from a file
+func LoadLintRules(path string) ([]LintRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint rules file: %w", err)
+	}
+
+	var rules []LintRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse lint rules file: %w", err)
+	}
+	return rules, nil
+}
+
+// NewLinter creates a new Linter, compiling and validating the rules
+func NewLinter(rules []LintRule) (*Linter, error) {
+	compiled := make([]LintRule, 0, len(rules))
+	for _, rule := range rules {
+		switch rule.Target {
+		case "":
+			rule.Target = LintTargetAll
+		case LintTargetTitle, LintTargetShowNote, LintTargetAll:
+		default:
+			return nil, fmt.Errorf("lint rule %q has unknown target %q", rule.Name, rule.Target)
+		}
+
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("lint rule %q has an invalid pattern: %w", rule.Name, err)
+		}
+		rule.re = re
+		compiled = append(compiled, rule)
+	}
+
+	return &Linter{rules: compiled}, nil
+}
+
+// Lint returns the violations found in the selected content
+func (l *Linter) Lint(content *model.SelectedContent) []LintViolation {
+	var violations []LintViolation
+	for _, rule := range l.rules {
+		for _, field := range lintFields(rule.Target, content) {
+			for _, match := range rule.re.FindAllString(*field.text, -1) {
+				violations = append(violations, LintViolation{
+					Rule:    rule.Name,
+					Target:  field.name,
+					Match:   match,
+					Message: rule.Message,
+					Fixable: rule.Replacement != nil,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// Fix applies the safe auto-fixes in place and returns the number of fixes made
+func (l *Linter) Fix(content *model.SelectedContent) int {
+	fixes := 0
+	for _, rule := range l.rules {
+		if rule.Replacement == nil {
+			continue
+		}
+		for _, field := range lintFields(rule.Target, content) {
+			fixes += len(rule.re.FindAllStringIndex(*field.text, -1))
+			*field.text = rule.re.ReplaceAllString(*field.text, *rule.Replacement)
+		}
+	}
+	return fixes
+}
+
+// lintField is a named, mutable field of the selected content
+type lintField struct {
+	name string
+	text *string
+}
+
+// lintFields returns the content fields a rule target applies to
+func lintFields(target string, content *model.SelectedContent) []lintField {
+	title := lintField{name: LintTargetTitle, text: &content.Title}
+	showNote := lintField{name: LintTargetShowNote, text: &content.ShowNote}
+
+	switch target {
+	case LintTargetTitle:
+		return []lintField{title}
+	case LintTargetShowNote:
+		return []lintField{showNote}
+	default:
+		return []lintField{title, showNote}
+	}
+}
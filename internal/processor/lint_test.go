@@ -0,0 +1,186 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// newDefaultLinter returns a linter with the built-in ruleset
+func newDefaultLinter(t *testing.T) *Linter {
+	t.Helper()
+	linter, err := NewLinter(DefaultLintRules())
+	if err != nil {
+		t.Fatalf("NewLinter(DefaultLintRules()) error = %v", err)
+	}
+	return linter
+}
+
+// violationRules returns the rule and target of each violation, e.g. "full-width-comma/title"
+func violationRules(violations []LintViolation) []string {
+	var rules []string
+	for _, v := range violations {
+		rules = append(rules, v.Rule+"/"+v.Target)
+	}
+	return rules
+}
+
+func TestLintDefaultRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content model.SelectedContent
+		want    []string
+		wantFix []bool
+	}{
+		{
+			name:    "clean content",
+			content: model.SelectedContent{Title: "42. AIと子育て / 夜泣き対策", ShowNote: "今回はGPTで夜泣きを分析しました！"},
+		},
+		{
+			name:    "short acronyms are allowed in titles",
+			content: model.SelectedContent{Title: "AI and GPT for parents"},
+		},
+		{
+			name:    "all-caps words in the title",
+			content: model.SelectedContent{Title: "THIS IS HUGE news"},
+			want:    []string{"no-all-caps-title/title"},
+			wantFix: []bool{false},
+		},
+		{
+			name:    "long all-caps word in the title",
+			content: model.SelectedContent{Title: "AMAZING episode"},
+			want:    []string{"no-all-caps-title/title"},
+			wantFix: []bool{false},
+		},
+		{
+			name:    "all-caps words are only checked in titles",
+			content: model.SelectedContent{ShowNote: "THIS IS HUGE news"},
+		},
+		{
+			name:    "half-width punctuation after Japanese text",
+			content: model.SelectedContent{Title: "すごい!", ShowNote: "本当?そうです,はい"},
+			want:    []string{"full-width-exclamation/title", "full-width-question/show_note", "full-width-comma/show_note"},
+			wantFix: []bool{true, true, true},
+		},
+		{
+			name:    "half-width punctuation after ASCII text is allowed",
+			content: model.SelectedContent{Title: "Hello, world!", ShowNote: "Why?"},
+		},
+		{
+			name:    "banned phrases",
+			content: model.SelectedContent{Title: "神回でした", ShowNote: "いかがでしたか"},
+			want:    []string{"banned-phrase/title", "banned-phrase/show_note"},
+			wantFix: []bool{false, false},
+		},
+	}
+
+	linter := newDefaultLinter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := linter.Lint(&tt.content)
+			if got := violationRules(violations); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Lint() rules = %v, want %v", got, tt.want)
+			}
+			for i, v := range violations {
+				if v.Fixable != tt.wantFix[i] {
+					t.Errorf("violation %s Fixable = %v, want %v", v.Rule, v.Fixable, tt.wantFix[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLintFix(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     model.SelectedContent
+		want        model.SelectedContent
+		wantFixes   int
+		wantRemains []string
+	}{
+		{
+			name:      "replaces half-width punctuation after Japanese text",
+			content:   model.SelectedContent{Title: "すごい!本当?", ShowNote: "そうです,はい! OK, thanks!"},
+			want:      model.SelectedContent{Title: "すごい！本当？", ShowNote: "そうです、はい！ OK, thanks!"},
+			wantFixes: 4,
+		},
+		{
+			name:        "leaves violations without a safe fix",
+			content:     model.SelectedContent{Title: "神回!", ShowNote: "いかがでしたか?"},
+			want:        model.SelectedContent{Title: "神回！", ShowNote: "いかがでしたか？"},
+			wantFixes:   2,
+			wantRemains: []string{"banned-phrase/title", "banned-phrase/show_note"},
+		},
+		{
+			name:      "clean content is unchanged",
+			content:   model.SelectedContent{Title: "42. AIと子育て", ShowNote: "今回のテーマです！"},
+			want:      model.SelectedContent{Title: "42. AIと子育て", ShowNote: "今回のテーマです！"},
+			wantFixes: 0,
+		},
+	}
+
+	linter := newDefaultLinter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := tt.content
+			if fixes := linter.Fix(&content); fixes != tt.wantFixes {
+				t.Errorf("Fix() = %d, want %d", fixes, tt.wantFixes)
+			}
+			if content != tt.want {
+				t.Errorf("Fix() content = %+v, want %+v", content, tt.want)
+			}
+			if got := violationRules(linter.Lint(&content)); !reflect.DeepEqual(got, tt.wantRemains) {
+				t.Errorf("Lint() after Fix() = %v, want %v", got, tt.wantRemains)
+			}
+		})
+	}
+}
+
+func TestNewLinterValidatesRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    LintRule
+		wantErr bool
+	}{
+		{name: "empty target applies to all", rule: LintRule{Name: "ok", Pattern: "x"}},
+		{name: "unknown target", rule: LintRule{Name: "bad-target", Pattern: "x", Target: "description"}, wantErr: true},
+		{name: "invalid pattern", rule: LintRule{Name: "bad-pattern", Pattern: "("}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLinter([]LintRule{tt.rule})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLinter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadLintRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	rules := `[{"name":"no-emoji-title","pattern":"🎉","target":"title","message":"no party emoji","replacement":""}]`
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLintRules(path)
+	if err != nil {
+		t.Fatalf("LoadLintRules() error = %v", err)
+	}
+	linter, err := NewLinter(loaded)
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+
+	content := model.SelectedContent{Title: "🎉 100回記念", ShowNote: "🎉"}
+	if fixes := linter.Fix(&content); fixes != 1 {
+		t.Errorf("Fix() = %d, want 1", fixes)
+	}
+	if content.Title != " 100回記念" || content.ShowNote != "🎉" {
+		t.Errorf("Fix() content = %+v, want the emoji removed from the title only", content)
+	}
+}
@@ -0,0 +1,143 @@
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+var (
+	// episodeNumberPattern matches a leading episode number such as "42." in a title
+	episodeNumberPattern = regexp.MustCompile(`^\s*(\d+)[.．]`)
+	// hashtagPattern matches hashtags such as #momitfm
+	hashtagPattern = regexp.MustCompile(`#[\p{L}\p{N}_]+`)
+	// slugInvalidPattern matches runs of characters not allowed in a slug
+	slugInvalidPattern = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// LoadMetadata reads an episode metadata document, returning an empty one if the file does not exist
+func LoadMetadata(path string) (*model.EpisodeMetadata, error) {
+	meta := &model.EpisodeMetadata{SchemaVersion: model.MetadataSchemaVersion}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return meta, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata file: %w", err)
+	}
+	if meta.SchemaVersion > model.MetadataSchemaVersion {
+		return nil, fmt.Errorf("metadata schema version %d is newer than supported version %d", meta.SchemaVersion, model.MetadataSchemaVersion)
+	}
+
+	return meta, nil
+}
+
+// SaveMetadata writes an episode metadata document as indented JSON
+func SaveMetadata(path string, meta *model.EpisodeMetadata) error {
+	meta.SchemaVersion = model.MetadataSchemaVersion
+	meta.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+	return nil
+}
+
+// UpdateMetadata loads the metadata document at path, applies update, and saves it back
+func UpdateMetadata(path string, update func(meta *model.EpisodeMetadata)) error {
+	meta, err := LoadMetadata(path)
+	if err != nil {
+		return err
+	}
+	update(meta)
+	return SaveMetadata(path, meta)
+}
+
+// ApplySelectedContent fills the metadata fields derived from the selected title and show note
+func ApplySelectedContent(meta *model.EpisodeMetadata, content *model.SelectedContent) {
+	meta.Title = content.Title
+	meta.Number = ParseEpisodeNumber(content.Title)
+	meta.Slug = Slugify(content.Title)
+	meta.ShowNote = content.ShowNote
+	meta.ShowNoteHTML = ShowNoteToHTML(content.ShowNote)
+	meta.Summary = summarizeShowNote(content.ShowNote)
+	meta.Tags = extractHashtags(content.ShowNote)
+}
+
+// ParseEpisodeNumber returns the leading episode number of a title, or 0 if there is none
+func ParseEpisodeNumber(title string) int {
+	m := episodeNumberPattern.FindStringSubmatch(title)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Slugify converts a title into a lowercase, hyphen-separated slug
+func Slugify(title string) string {
+	slug := slugInvalidPattern.ReplaceAllString(strings.ToLower(title), "-")
+	return strings.Trim(slug, "-")
+}
+
+// ShowNoteToHTML converts a plain-text show note into simple HTML paragraphs
+func ShowNoteToHTML(note string) string {
+	var paragraphs []string
+	for _, block := range strings.Split(strings.ReplaceAll(note, "\r\n", "\n"), "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		lines := strings.Split(block, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(strings.TrimSpace(line))
+		}
+		paragraphs = append(paragraphs, "<p>"+strings.Join(lines, "<br>")+"</p>")
+	}
+	return strings.Join(paragraphs, "\n")
+}
+
+// summarizeShowNote returns the opening paragraph of a show note
+func summarizeShowNote(note string) string {
+	for _, block := range strings.Split(strings.ReplaceAll(note, "\r\n", "\n"), "\n\n") {
+		if block = strings.TrimSpace(block); block != "" {
+			return block
+		}
+	}
+	return ""
+}
+
+// extractHashtags returns the unique hashtags found in a text, without the leading '#'
+func extractHashtags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range hashtagPattern.FindAllString(text, -1) {
+		tag = strings.TrimPrefix(tag, "#")
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,47 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// SNSPostFileName is the name of the post text that the run command saves in the output directory
+const SNSPostFileName = "sns_post.txt"
+
+// SNSPostJSONFileName is the name of the post JSON that the run command saves in the output directory
+const SNSPostJSONFileName = "sns_post.json"
+
+// SaveSNSPost writes the generated post and its metadata to path as JSON
+func SaveSNSPost(path string, post *model.SNSPost) error {
+	data, err := json.MarshalIndent(post, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal post: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write post JSON file: %w", err)
+	}
+	return nil
+}
+
+// LoadSNSPost reads a post saved by SaveSNSPost
+func LoadSNSPost(path string) (*model.SNSPost, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read post JSON file: %w", err)
+	}
+
+	var post model.SNSPost
+	if err := json.Unmarshal(data, &post); err != nil {
+		return nil, fmt.Errorf("failed to parse post JSON file %s: %w", path, err)
+	}
+	return &post, nil
+}
+
+// PlatformPostFileName returns the name of the file a platform's post text variant is saved to
+func PlatformPostFileName(platform string) string {
+	return "post_" + platform + ".txt"
+}
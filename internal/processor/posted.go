@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PostedEpisode records the last episode that step4 posted about, so cron runs skip it
+type PostedEpisode struct {
+	GUID        string    `json:"guid,omitempty"`
+	Title       string    `json:"title"`
+	PublishedAt time.Time `json:"published_at,omitempty"`
+	PostedAt    time.Time `json:"posted_at"`
+	// Platforms records when the episode was posted to each platform, such as "x" or "bluesky"
+	Platforms map[string]time.Time `json:"platforms,omitempty"`
+}
+
+// PostedTo reports whether the episode was posted to the platform. State files written before
+// posts were recorded per platform have no platforms and count as posted to all of them.
+func (p *PostedEpisode) PostedTo(platform string) bool {
+	if p.Platforms == nil {
+		return true
+	}
+	_, ok := p.Platforms[platform]
+	return ok
+}
+
+// RecordPost records that the episode was posted to the platform at the given time
+func (p *PostedEpisode) RecordPost(platform string, at time.Time) {
+	if p.Platforms == nil {
+		p.Platforms = make(map[string]time.Time)
+	}
+	p.Platforms[platform] = at
+	p.PostedAt = at
+}
+
+// IsNewer reports whether an episode with the GUID, title and publication time is newer than
+// the posted one. Episodes are matched by GUID, or by title if either has no GUID, and
+// compared by publication time when both times are known.
+func (p *PostedEpisode) IsNewer(guid, title string, publishedAt time.Time) bool {
+	if guid != "" && p.GUID != "" {
+		if guid == p.GUID {
+			return false
+		}
+	} else if title == p.Title {
+		return false
+	}
+	if !publishedAt.IsZero() && !p.PublishedAt.IsZero() {
+		return publishedAt.After(p.PublishedAt)
+	}
+	return true
+}
+
+// LoadPostedEpisode reads the posted episode state file at path, returning nil if it does not exist
+func LoadPostedEpisode(path string) (*PostedEpisode, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read posted episode state: %w", err)
+	}
+
+	var posted PostedEpisode
+	if err := json.Unmarshal(data, &posted); err != nil {
+		return nil, fmt.Errorf("failed to parse posted episode state %s: %w", path, err)
+	}
+	return &posted, nil
+}
+
+// SavePostedEpisode writes the posted episode state file at path
+func SavePostedEpisode(path string, posted *PostedEpisode) error {
+	data, err := json.MarshalIndent(posted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal posted episode state: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write posted episode state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPostedEpisodeIsNewer(t *testing.T) {
+	published := time.Date(2025, 6, 2, 6, 0, 0, 0, time.UTC)
+	posted := &PostedEpisode{GUID: "ep-42", Title: "42. AIと子育て", PublishedAt: published}
+
+	tests := []struct {
+		name        string
+		guid        string
+		title       string
+		publishedAt time.Time
+		want        bool
+	}{
+		{name: "same guid", guid: "ep-42", title: "42. AIと子育て (renamed)", publishedAt: published, want: false},
+		{name: "newer episode", guid: "ep-43", title: "43. 夜泣き", publishedAt: published.Add(7 * 24 * time.Hour), want: true},
+		{name: "older episode", guid: "ep-41", title: "41. 寝かしつけ", publishedAt: published.Add(-7 * 24 * time.Hour), want: false},
+		{name: "same title without guid", title: "42. AIと子育て", want: false},
+		{name: "other title without dates", guid: "ep-43", title: "43. 夜泣き", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := posted.IsNewer(tt.guid, tt.title, tt.publishedAt); got != tt.want {
+				t.Errorf("IsNewer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostedEpisodeRecordsPlatforms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sns_posted.json")
+	posted := &PostedEpisode{GUID: "ep-42", Title: "42. AIと子育て"}
+	postedAt := time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+	posted.RecordPost("x", postedAt)
+	if err := SavePostedEpisode(path, posted); err != nil {
+		t.Fatalf("SavePostedEpisode() error = %v", err)
+	}
+
+	loaded, err := LoadPostedEpisode(path)
+	if err != nil {
+		t.Fatalf("LoadPostedEpisode() error = %v", err)
+	}
+	if !loaded.PostedTo("x") {
+		t.Error("PostedTo(x) = false, want true after RecordPost")
+	}
+	if loaded.PostedTo("bluesky") {
+		t.Error("PostedTo(bluesky) = true, want false for a platform that wasn't posted to")
+	}
+	if !loaded.PostedAt.Equal(postedAt) {
+		t.Errorf("PostedAt = %v, want %v", loaded.PostedAt, postedAt)
+	}
+}
+
+func TestPostedEpisodeWithoutPlatforms(t *testing.T) {
+	// State files written before posts were recorded per platform
+	path := filepath.Join(t.TempDir(), "sns_posted.json")
+	data := `{"guid":"ep-42","title":"42. AIと子育て","posted_at":"2025-06-02T07:00:00Z"}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadPostedEpisode(path)
+	if err != nil {
+		t.Fatalf("LoadPostedEpisode() error = %v", err)
+	}
+	for _, platform := range []string{"x", "mastodon", "bluesky", "linkedin"} {
+		if !loaded.PostedTo(platform) {
+			t.Errorf("PostedTo(%s) = false, want true for a state file without platforms", platform)
+		}
+	}
+}
+
+func TestLoadPostedEpisodeMissing(t *testing.T) {
+	posted, err := LoadPostedEpisode(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || posted != nil {
+		t.Errorf("LoadPostedEpisode() = %v, %v, want nil, nil for a missing file", posted, err)
+	}
+}
@@ -0,0 +1,36 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/automate-podcast/services"
+)
+
+// LoadPromptTemplate reads a prompt template file and checks that it parses
+func LoadPromptTemplate(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt template: %w", err)
+	}
+
+	text := string(data)
+	if _, err := services.ParsePromptTemplate(text); err != nil {
+		return "", err
+	}
+	return text, nil
+}
+
+// LoadSNSTemplate reads an SNS post template file and checks that it parses
+func LoadSNSTemplate(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read SNS template: %w", err)
+	}
+
+	text := string(data)
+	if _, err := services.ParseSNSTemplate(text); err != nil {
+		return "", err
+	}
+	return text, nil
+}
@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// DefaultPostQueueFile is the queue file of scheduled posts used when none is specified
+const DefaultPostQueueFile = "post_queue.json"
+
+// LoadPostQueue reads the queue file at path, returning an empty queue if it does not exist
+func LoadPostQueue(path string) (*model.PostQueue, error) {
+	queue := &model.PostQueue{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return queue, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read post queue: %w", err)
+	}
+
+	if err := json.Unmarshal(data, queue); err != nil {
+		return nil, fmt.Errorf("failed to parse post queue %s: %w", path, err)
+	}
+	return queue, nil
+}
+
+// SavePostQueue writes the queue file at path
+func SavePostQueue(path string, queue *model.PostQueue) error {
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal post queue: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write post queue: %w", err)
+	}
+	return nil
+}
+
+// EnqueuePost adds a post to the queue file at path and returns the queued post
+func EnqueuePost(path string, post model.ScheduledPost) (*model.ScheduledPost, error) {
+	queue, err := LoadPostQueue(path)
+	if err != nil {
+		return nil, err
+	}
+
+	post.CreatedAt = time.Now().UTC()
+	if post.ID == "" {
+		post.ID = fmt.Sprintf("%s-%d", post.Platform, post.CreatedAt.UnixNano())
+	}
+	queue.Posts = append(queue.Posts, post)
+
+	if err := SavePostQueue(path, queue); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// ParseScheduleTime parses an RFC3339 time for scheduling a post and checks that it is in the future
+func ParseScheduleTime(value string, now time.Time) (time.Time, error) {
+	scheduledAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC3339 time such as 2025-05-01T09:00:00+09:00", value)
+	}
+	if !scheduledAt.After(now) {
+		return time.Time{}, fmt.Errorf("%s is not in the future", value)
+	}
+	return scheduledAt, nil
+}
@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/automate-podcast/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// SelectedContentFileName is the name of the machine-readable selected content file in the output directory
+const SelectedContentFileName = "selected_content.json"
+
+// LegacySelectedContentFileName is the name of the human-readable selected content file in the output directory
+const LegacySelectedContentFileName = "selected_content.txt"
+
+// OutputPath returns the path of a step1 output file in dir, with the --output-prefix of its name.
+// Every command that reads step1's files builds their paths with it.
+func OutputPath(dir, prefix, name string) string {
+	return filepath.Join(dir, prefix+name)
+}
+
+// LoadSelectedContent reads content saved by SaveSelectedContent. Text files in the legacy
+// "Title: ... Show Notes: ..." format written by older versions of step1 are still accepted.
+func LoadSelectedContent(path string, logger *logrus.Logger) (*model.SelectedContent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content file: %w", err)
+	}
+
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		logger.Warnf("%s is not JSON, parsing it as a legacy text content file; use %s from step1 instead", path, SelectedContentFileName)
+		return parseLegacySelectedContent(string(data))
+	}
+
+	var content model.SelectedContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to parse content file: %w", err)
+	}
+	if strings.TrimSpace(content.Title) == "" {
+		return nil, fmt.Errorf("content file %s has no title", path)
+	}
+
+	return &content, nil
+}
+
+// SaveSelectedContent writes the selected content as indented JSON
+func SaveSelectedContent(path string, content *model.SelectedContent) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal selected content: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write selected content file: %w", err)
+	}
+	return nil
+}
+
+// FormatSelectedContent renders the selected content in the legacy human-readable text format
+func FormatSelectedContent(content *model.SelectedContent) string {
+	return fmt.Sprintf("=== Selected Content ===\nTitle: %s\n\nShow Notes:\n%s", content.Title, content.ShowNote)
+}
+
+// parseLegacySelectedContent parses the text format written by FormatSelectedContent
+func parseLegacySelectedContent(content string) (*model.SelectedContent, error) {
+	titleStart := strings.Index(content, "Title: ")
+	showNotesStart := strings.Index(content, "Show Notes:")
+	if titleStart < 0 || showNotesStart <= titleStart {
+		return nil, fmt.Errorf("failed to parse content file, expected format not found")
+	}
+
+	return &model.SelectedContent{
+		Title:    strings.TrimSpace(content[titleStart+len("Title: ") : showNotesStart]),
+		ShowNote: strings.TrimSpace(content[showNotesStart+len("Show Notes:"):]),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/automate-podcast/services"
+)
+
+// Show note format required by the default prompt
+const (
+	minShowNoteBullets = 8
+	maxShowNoteBullets = 12
+	maxShowNoteOpening = 3
+)
+
+// ValidateShowNote checks a show note against the format required by the default prompt:
+// a 2-3 line opening whose lines end with "!", 8-12 emoji bullets, a CTA block wrapped in
+// dotted lines and a "✨🎧 Credits" section. It returns a description of each violation.
+func ValidateShowNote(note string) []string {
+	var violations []string
+
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(note, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return []string{"show note is empty"}
+	}
+
+	// The opening runs until the first bullet
+	opening := 0
+	for opening < len(lines) && !services.IsShowNoteBullet(lines[opening]) && !services.IsCTADivider(lines[opening]) {
+		opening++
+	}
+	switch {
+	case opening == 0:
+		violations = append(violations, "missing opening summary before the bullet points")
+	case opening > maxShowNoteOpening:
+		violations = append(violations, fmt.Sprintf("opening summary has %d lines, expected 2-3", opening))
+	}
+	for _, line := range lines[:opening] {
+		if !strings.HasSuffix(line, "!") && !strings.HasSuffix(line, "！") {
+			violations = append(violations, fmt.Sprintf("opening line does not end with an exclamation mark: %q", line))
+		}
+	}
+
+	// Bullets come before the CTA block; emoji lines in the credits are not bullets
+	bullets := 0
+	dividers := 0
+	credits := false
+	for _, line := range lines {
+		switch {
+		case services.IsCTADivider(line):
+			dividers++
+		case strings.HasPrefix(line, services.ShowNoteCreditsHeader):
+			credits = true
+		case dividers == 0 && !credits && services.IsShowNoteBullet(line):
+			bullets++
+		}
+	}
+
+	if bullets < minShowNoteBullets {
+		violations = append(violations, fmt.Sprintf("too few bullet points: %d, expected %d-%d", bullets, minShowNoteBullets, maxShowNoteBullets))
+	} else if bullets > maxShowNoteBullets {
+		violations = append(violations, fmt.Sprintf("too many bullet points: %d, expected %d-%d", bullets, minShowNoteBullets, maxShowNoteBullets))
+	}
+	if dividers < 2 {
+		violations = append(violations, `missing CTA block wrapped in dotted lines ("………")`)
+	}
+	if !credits {
+		violations = append(violations, fmt.Sprintf("missing %q section", services.ShowNoteCreditsHeader))
+	}
+
+	return violations
+}
@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+// compliantShowNote follows the format required by the default prompt
+const compliantShowNote = `今回は夜泣き対策アプリを試しました！
+AIで子育てはどこまで楽になる？を語ります！
+
+🍼 夜泣き: アプリで泣き声を分析
+📱 アプリ: 使ってみた感想
+🤖 AI: 泣き声の判定精度
+😴 睡眠: 親の睡眠時間の変化
+🏠 家事: 分担の見直し
+💡 気づき: 記録の大切さ
+📚 本: おすすめの育児書
+🎙️ お便り: リスナーからの質問
+
+………
+番組の感想は #momitfm でお寄せください
+https://example.com/form
+………
+
+✨🎧 Credits
+🎧 Host: @m2vela`
+
+// replaceLines returns the compliant show note with old replaced by new
+func replaceLines(old, new string) string {
+	return strings.Replace(compliantShowNote, old, new, 1)
+}
+
+func TestValidateShowNoteCompliant(t *testing.T) {
+	if violations := ValidateShowNote(compliantShowNote); len(violations) != 0 {
+		t.Errorf("ValidateShowNote() = %v, want no violations", violations)
+	}
+}
+
+func TestValidateShowNoteCompliantWithCRLF(t *testing.T) {
+	note := strings.ReplaceAll(compliantShowNote, "\n", "\r\n")
+	if violations := ValidateShowNote(note); len(violations) != 0 {
+		t.Errorf("ValidateShowNote() = %v, want no violations", violations)
+	}
+}
+
+func TestValidateShowNoteViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		note string
+		want []string
+	}{
+		{
+			name: "empty",
+			note: "  \n ",
+			want: []string{"show note is empty"},
+		},
+		{
+			name: "missing opening",
+			note: replaceLines("今回は夜泣き対策アプリを試しました！\nAIで子育てはどこまで楽になる？を語ります！\n\n", ""),
+			want: []string{"missing opening summary"},
+		},
+		{
+			name: "opening too long",
+			note: "一行目！\n二行目！\n三行目！\n" + compliantShowNote,
+			want: []string{"opening summary has 5 lines, expected 2-3"},
+		},
+		{
+			name: "opening without exclamation mark",
+			note: replaceLines("今回は夜泣き対策アプリを試しました！", "今回は夜泣き対策アプリを試しました。"),
+			want: []string{"opening line does not end with an exclamation mark"},
+		},
+		{
+			name: "too few bullets",
+			note: replaceLines("💡 気づき: 記録の大切さ\n📚 本: おすすめの育児書\n🎙️ お便り: リスナーからの質問\n", ""),
+			want: []string{"too few bullet points: 5, expected 8-12"},
+		},
+		{
+			name: "too many bullets",
+			note: replaceLines("🎙️ お便り: リスナーからの質問\n", strings.Repeat("🎙️ お便り: リスナーからの質問\n", 6)),
+			want: []string{"too many bullet points: 13, expected 8-12"},
+		},
+		{
+			name: "missing CTA block",
+			note: replaceLines("………\n番組の感想は #momitfm でお寄せください\nhttps://example.com/form\n………\n", ""),
+			want: []string{"missing CTA block"},
+		},
+		{
+			name: "missing credits",
+			note: replaceLines("✨🎧 Credits\n🎧 Host: @m2vela", ""),
+			want: []string{`missing "✨🎧 Credits" section`},
+		},
+		{
+			name: "several violations",
+			note: "こんにちは。\n🍼 夜泣き: アプリで泣き声を分析",
+			want: []string{
+				"opening line does not end with an exclamation mark",
+				"too few bullet points: 1, expected 8-12",
+				"missing CTA block",
+				"missing \"✨🎧 Credits\" section",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := ValidateShowNote(tt.note)
+			if len(violations) != len(tt.want) {
+				t.Fatalf("ValidateShowNote() = %q, want %d violations like %q", violations, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(violations[i], want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, violations[i], want)
+				}
+			}
+		})
+	}
+}
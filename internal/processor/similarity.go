@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultTitleSimilarityThreshold is the similarity from which two title candidates count as near-duplicates
+const DefaultTitleSimilarityThreshold = 0.8
+
+// TitleSimilarity returns how similar two titles are, from 0 (nothing in common) to 1 (identical),
+// as the normalized Levenshtein distance of their letters and digits. The episode number prefix,
+// case, spaces and punctuation are ignored.
+func TitleSimilarity(a, b string) float64 {
+	ra, rb := normalizeTitle(a), normalizeTitle(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// DedupTitles removes titles whose similarity to an earlier title is at least threshold, and then
+// keeps at most maxCount of the remaining titles (0 keeps all), choosing the most distinct ones.
+// The first title is always kept and the original order is preserved.
+func DedupTitles(titles []string, threshold float64, maxCount int) []string {
+	var unique []string
+	for _, title := range titles {
+		duplicate := false
+		for _, kept := range unique {
+			if TitleSimilarity(title, kept) >= threshold {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			unique = append(unique, title)
+		}
+	}
+
+	if maxCount <= 0 || len(unique) <= maxCount {
+		return unique
+	}
+
+	// Repeatedly pick the title least similar to those already picked
+	picked := []int{0}
+	closest := make([]float64, len(unique))
+	for i := range unique {
+		closest[i] = TitleSimilarity(unique[i], unique[0])
+	}
+	for len(picked) < maxCount {
+		next := -1
+		for i := range unique {
+			if containsIndex(picked, i) {
+				continue
+			}
+			if next < 0 || closest[i] < closest[next] {
+				next = i
+			}
+		}
+		picked = append(picked, next)
+		for i := range unique {
+			if similarity := TitleSimilarity(unique[i], unique[next]); similarity > closest[i] {
+				closest[i] = similarity
+			}
+		}
+	}
+
+	sort.Ints(picked)
+	result := make([]string, len(picked))
+	for i, index := range picked {
+		result[i] = unique[index]
+	}
+	return result
+}
+
+// normalizeTitle returns the lowercase letters and digits of a title without its episode number prefix
+func normalizeTitle(title string) []rune {
+	title = episodeNumberPattern.ReplaceAllString(title, "")
+	var runes []rune
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// levenshtein returns the number of single-rune edits needed to turn a into b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// containsIndex reports whether indexes contains i
+func containsIndex(indexes []int, i int) bool {
+	for _, index := range indexes {
+		if index == i {
+			return true
+		}
+	}
+	return false
+}
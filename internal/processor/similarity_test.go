@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/automate-podcast/services"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "identical", a: "夜泣き対策アプリ", b: "夜泣き対策アプリ", want: 1},
+		{name: "episode number prefix is ignored", a: "42. AIと子育て", b: "AIと子育て", want: 1},
+		{name: "case, spaces and punctuation are ignored", a: "Hello, World!", b: "hello world", want: 1},
+		{name: "no letters or digits", a: "", b: "!!", want: 1},
+		{name: "nothing in common", a: "abc", b: "xyz", want: 0},
+		{name: "one edit in four", a: "abcd", b: "abce", want: 0.75},
+		{name: "one japanese edit in five", a: "夜泣き対策", b: "夜泣き対処！", want: 0.8},
+		{name: "different lengths", a: "ab", b: "abcd", want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TitleSimilarity(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("TitleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if reverse := TitleSimilarity(tt.b, tt.a); reverse != got {
+				t.Errorf("TitleSimilarity(%q, %q) = %v, want the same as the reverse %v", tt.b, tt.a, reverse, got)
+			}
+		})
+	}
+}
+
+func TestDedupTitles(t *testing.T) {
+	tests := []struct {
+		name      string
+		titles    []string
+		threshold float64
+		maxCount  int
+		want      []string
+	}{
+		{
+			name:      "near-duplicates are dropped",
+			titles:    []string{"夜泣き対策", "夜泣き対処", "睡眠の話"},
+			threshold: DefaultTitleSimilarityThreshold,
+			want:      []string{"夜泣き対策", "睡眠の話"},
+		},
+		{
+			name:      "threshold 1 drops only identical titles",
+			titles:    []string{"1. Sleep!", "sleep", "Sleep tips"},
+			threshold: 1,
+			want:      []string{"1. Sleep!", "Sleep tips"},
+		},
+		{
+			name:      "max count keeps the most distinct titles in order",
+			titles:    []string{"abcdef", "abcdxy", "zzzzzz", "abcxyz"},
+			threshold: 1,
+			maxCount:  2,
+			want:      []string{"abcdef", "zzzzzz"},
+		},
+		{
+			name:      "max count picks the next least similar title",
+			titles:    []string{"abcdef", "abcdxy", "zzzzzz", "abcxyz"},
+			threshold: 1,
+			maxCount:  3,
+			want:      []string{"abcdef", "zzzzzz", "abcxyz"},
+		},
+		{
+			name:      "max count applies after dropping near-duplicates",
+			titles:    []string{"夜泣き対策", "夜泣き対処", "睡眠の話"},
+			threshold: DefaultTitleSimilarityThreshold,
+			maxCount:  2,
+			want:      []string{"夜泣き対策", "睡眠の話"},
+		},
+		{
+			name:      "max count 0 keeps all",
+			titles:    []string{"abcdef", "zzzzzz", "abcxyz"},
+			threshold: 1,
+			want:      []string{"abcdef", "zzzzzz", "abcxyz"},
+		},
+		{
+			name:      "no titles",
+			threshold: 1,
+			maxCount:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DedupTitles(tt.titles, tt.threshold, tt.maxCount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DedupTitles() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// stubGenerator returns fixed content candidates
+type stubGenerator struct {
+	titles    []string
+	showNotes []string
+}
+
+func (g *stubGenerator) GenerateAllContent(ctx context.Context, transcript string, opts services.GenerateOptions) ([]string, []string, error) {
+	return g.titles, g.showNotes, nil
+}
+
+func TestGenerateCandidatesMaxCandidates(t *testing.T) {
+	generator := &stubGenerator{
+		titles:    []string{"42. 夜泣き対策", "42. 夜泣き対処", "睡眠の話", "アプリ紹介"},
+		showNotes: []string{"show note"},
+	}
+	contentProcessor := NewContentProcessor(generator, newTestLogger())
+	contentProcessor.MaxCandidates = 2
+
+	candidates, err := contentProcessor.GenerateCandidates(context.Background(), "transcript", true, services.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("GenerateCandidates() error = %v", err)
+	}
+	if want := []string{"42. 夜泣き対策", "睡眠の話"}; !reflect.DeepEqual(candidates.Titles, want) {
+		t.Errorf("Titles = %q, want %q", candidates.Titles, want)
+	}
+	if !reflect.DeepEqual(candidates.ShowNotes, generator.showNotes) {
+		t.Errorf("ShowNotes = %q, want %q", candidates.ShowNotes, generator.showNotes)
+	}
+}
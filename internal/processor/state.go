@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// StateFileName is the name of the pipeline state file kept in the output directory
+const StateFileName = ".aipodflow-state.json"
+
+// DeployArtifacts are the generated files in the output directory that feed the website
+var DeployArtifacts = []string{SelectedContentFileName, LegacySelectedContentFileName}
+
+// State records what the pipeline has already done for an output directory
+type State struct {
+	LastDeployHash string      `json:"last_deploy_hash,omitempty"`
+	LastDeployAt   time.Time   `json:"last_deploy_at,omitempty"`
+	Art19Draft     *Art19Draft `json:"art19_draft,omitempty"`
+}
+
+// Art19Draft records a step2 upload. It is saved before the upload starts, so a run that
+// fails halfway leaves a record without CompletedAt behind.
+type Art19Draft struct {
+	ContentHash string    `json:"content_hash"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+	EpisodeID   string    `json:"episode_id,omitempty"`
+	EpisodeURL  string    `json:"episode_url,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Completed reports whether the upload finished and the draft was created
+func (d *Art19Draft) Completed() bool {
+	return !d.CompletedAt.IsZero()
+}
+
+// LoadState reads the state file in dir, returning an empty state if it does not exist
+func LoadState(dir string) (*State, error) {
+	state := &State{}
+
+	data, err := os.ReadFile(filepath.Join(dir, StateFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return state, nil
+}
+
+// SaveState writes the state file in dir
+func SaveState(dir string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, StateFileName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// HashArtifacts returns a SHA-256 hash over the named files in dir that exist, with the
+// file name prefix of step1's --output-prefix. It returns an empty string if none of the files exist.
+func HashArtifacts(dir, prefix string, names ...string) (string, error) {
+	hash := sha256.New()
+	found := false
+
+	for _, name := range names {
+		data, err := os.ReadFile(OutputPath(dir, prefix, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		found = true
+		fmt.Fprintf(hash, "%s\x00%d\x00", name, len(data))
+		hash.Write(data)
+	}
+
+	if !found {
+		return "", nil
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HashDraftContent returns a SHA-256 hash identifying an Art19 upload of the content and audio file.
+// The audio file's content is hashed too, so a re-exported file with the same name counts as new.
+func HashDraftContent(content *model.SelectedContent, audioPath string) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", content.Title, content.ShowNote, filepath.Base(audioPath))
+
+	if audioPath != "" {
+		file, err := os.Open(audioPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read audio file: %w", err)
+		}
+		defer file.Close()
+		if _, err := io.Copy(hash, file); err != nil {
+			return "", fmt.Errorf("failed to read audio file: %w", err)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
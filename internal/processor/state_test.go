@@ -0,0 +1,101 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// writeAudio writes a test file, such as a fake audio file, named name into dir
+func writeAudio(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// hashDraft returns HashDraftContent, failing the test on errors
+func hashDraft(t *testing.T, content *model.SelectedContent, audioPath string) string {
+	t.Helper()
+	hash, err := HashDraftContent(content, audioPath)
+	if err != nil {
+		t.Fatalf("HashDraftContent() error = %v", err)
+	}
+	return hash
+}
+
+func TestHashDraftContent(t *testing.T) {
+	content := &model.SelectedContent{Title: "42. AIと子育て", ShowNote: "今回は夜泣きの話です！"}
+	original := writeAudio(t, t.TempDir(), "episode.mp3", "ID3 original mix")
+	want := hashDraft(t, content, original)
+
+	if got := hashDraft(t, content, writeAudio(t, t.TempDir(), "episode.mp3", "ID3 original mix")); got != want {
+		t.Error("HashDraftContent() differs for the same content and audio in another directory")
+	}
+
+	tests := []struct {
+		name    string
+		content *model.SelectedContent
+		audio   string
+	}{
+		{name: "re-exported audio with the same name", content: content, audio: writeAudio(t, t.TempDir(), "episode.mp3", "ID3 fixed mix")},
+		{name: "renamed audio", content: content, audio: writeAudio(t, t.TempDir(), "episode-42.mp3", "ID3 original mix")},
+		{name: "changed title", content: &model.SelectedContent{Title: "42. AIと育児", ShowNote: content.ShowNote}, audio: original},
+		{name: "changed show note", content: &model.SelectedContent{Title: content.Title, ShowNote: "今回は寝かしつけの話です！"}, audio: original},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hashDraft(t, tt.content, tt.audio); got == want {
+				t.Error("HashDraftContent() is unchanged, want a different hash")
+			}
+		})
+	}
+}
+
+func TestHashDraftContentMissingAudio(t *testing.T) {
+	content := &model.SelectedContent{Title: "42. AIと子育て"}
+	if _, err := HashDraftContent(content, filepath.Join(t.TempDir(), "missing.mp3")); err == nil {
+		t.Error("HashDraftContent() error = nil, want an error for a missing audio file")
+	}
+}
+
+func TestHashArtifactsDeployArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	if hash, err := HashArtifacts(dir, "", DeployArtifacts...); err != nil || hash != "" {
+		t.Fatalf("HashArtifacts() = %q, %v, want an empty hash without artifacts", hash, err)
+	}
+
+	writeAudio(t, dir, SelectedContentFileName, `{"title":"42. AIと子育て"}`)
+	writeAudio(t, dir, LegacySelectedContentFileName, "42. AIと子育て")
+	before, err := HashArtifacts(dir, "", DeployArtifacts...)
+	if err != nil {
+		t.Fatalf("HashArtifacts() error = %v", err)
+	}
+
+	// A change to only the JSON file, which the site reads, must count as new content
+	writeAudio(t, dir, SelectedContentFileName, `{"title":"42. AIと育児"}`)
+	after, err := HashArtifacts(dir, "", DeployArtifacts...)
+	if err != nil {
+		t.Fatalf("HashArtifacts() error = %v", err)
+	}
+	if after == before {
+		t.Errorf("HashArtifacts() is unchanged after %s changed", SelectedContentFileName)
+	}
+}
+
+func TestHashArtifactsWithOutputPrefix(t *testing.T) {
+	dir := t.TempDir()
+	writeAudio(t, dir, "ep42_"+SelectedContentFileName, `{"title":"42. AIと子育て"}`)
+
+	if hash, err := HashArtifacts(dir, "", DeployArtifacts...); err != nil || hash != "" {
+		t.Errorf("HashArtifacts() without the prefix = %q, %v, want an empty hash", hash, err)
+	}
+	hash, err := HashArtifacts(dir, "ep42_", DeployArtifacts...)
+	if err != nil || hash == "" {
+		t.Errorf("HashArtifacts() with the prefix = %q, %v, want the hash of %s", hash, err, OutputPath(dir, "ep42_", SelectedContentFileName))
+	}
+}
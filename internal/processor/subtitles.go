@@ -0,0 +1,223 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/automate-podcast/services"
+)
+
+// SegmentsToSRT serializes transcript segments as SubRip (SRT) subtitles.
+// Segments with empty text are skipped and the remaining cues are numbered consecutively.
+func SegmentsToSRT(segments []services.TranscriptSegment) string {
+	var sb strings.Builder
+	index := 1
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", index, formatTimestamp(segment.Start, ","), formatTimestamp(segment.End, ","), text)
+		index++
+	}
+	return sb.String()
+}
+
+// SegmentsToVTT serializes transcript segments as WebVTT captions.
+// Segments with empty text are skipped.
+func SegmentsToVTT(segments []services.TranscriptSegment) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s --> %s\n%s\n\n", formatTimestamp(segment.Start, "."), formatTimestamp(segment.End, "."), text)
+	}
+	return sb.String()
+}
+
+// SegmentsToJSON serializes a transcription result with its segments as indented JSON
+func SegmentsToJSON(result *services.TranscriptionResult) (string, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal segments: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// formatTimestamp formats seconds as HH:MM:SS<sep>mmm, rounding to the nearest millisecond
+func formatTimestamp(seconds float64, msSeparator string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	totalMs := int64(math.Round(seconds * 1000))
+	hours := totalMs / 3600000
+	minutes := totalMs % 3600000 / 60000
+	secs := totalMs % 60000 / 1000
+	ms := totalMs % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, msSeparator, ms)
+}
+
+// cueTagPattern matches the markup allowed in cue text, e.g. <i>, <v Speaker>, <c.yellow> and <00:00:01.000>
+var cueTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// voiceTagPattern matches a VTT voice tag at the start of cue text, e.g. <v Host> or <v.loud Host>
+var voiceTagPattern = regexp.MustCompile(`^<v(?:\.[^\s>]*)?\s+([^>]+)>`)
+
+// DetectSubtitleFormat reports whether a transcript is SRT or VTT, based on the file
+// extension or, failing that, on the content. It returns FormatText for plain transcripts.
+func DetectSubtitleFormat(path, content string) TranscriptFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt":
+		return FormatSRT
+	case ".vtt":
+		return FormatVTT
+	}
+
+	content = strings.TrimLeft(content, "\ufeff \t\r\n")
+	if strings.HasPrefix(content, "WEBVTT") {
+		return FormatVTT
+	}
+
+	// SRT starts with a cue number followed by a timing line
+	lines := strings.SplitN(strings.ReplaceAll(content, "\r\n", "\n"), "\n", 3)
+	if len(lines) >= 2 {
+		if _, err := strconv.Atoi(strings.TrimSpace(lines[0])); err == nil && strings.Contains(lines[1], "-->") {
+			return FormatSRT
+		}
+	}
+	return FormatText
+}
+
+// ParseSubtitles parses SRT or VTT content into timed segments sorted by start time.
+// Cue numbers, VTT headers, NOTE/STYLE/REGION blocks and cue settings are dropped,
+// markup such as <i> is stripped from the cue text, and a <v Speaker> voice tag becomes
+// a "Speaker: " label so that ParseTurns can find the speaker turns.
+func ParseSubtitles(content string) ([]services.TranscriptSegment, error) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var segments []services.TranscriptSegment
+	for _, block := range strings.Split(content, "\n\n") {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+
+		// Find the timing line; blocks without one are headers, notes or styles
+		timing := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 || strings.HasPrefix(lines[0], "NOTE") {
+			continue
+		}
+
+		start, end, err := parseCueTiming(lines[timing])
+		if err != nil {
+			return nil, err
+		}
+
+		var text []string
+		for _, line := range lines[timing+1:] {
+			label := ""
+			if m := voiceTagPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				label = strings.TrimSpace(m[1]) + ": "
+			}
+			line = strings.TrimSpace(html.UnescapeString(cueTagPattern.ReplaceAllString(line, "")))
+			if line != "" {
+				text = append(text, label+line)
+			}
+		}
+		if len(text) == 0 {
+			continue
+		}
+
+		segments = append(segments, services.TranscriptSegment{
+			Start: start,
+			End:   end,
+			Text:  strings.Join(text, "\n"),
+		})
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no subtitle cues found")
+	}
+
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Start < segments[j].Start
+	})
+	return segments, nil
+}
+
+// SegmentsText joins the text of timed segments into a plain transcript.
+// Overlapping cues often repeat the previous line (e.g. rolling captions), so a line
+// identical to the one just emitted is skipped.
+func SegmentsText(segments []services.TranscriptSegment) string {
+	var lines []string
+	last := ""
+	for _, segment := range segments {
+		for _, line := range strings.Split(segment.Text, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || line == last {
+				continue
+			}
+			lines = append(lines, line)
+			last = line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseCueTiming parses a timing line such as "00:00:01,000 --> 00:00:04,000 align:start"
+func parseCueTiming(line string) (float64, float64, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	start, err := parseCueTimestamp(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// VTT cue settings may follow the end timestamp
+	endFields := strings.Fields(parts[1])
+	if len(endFields) == 0 {
+		return 0, 0, fmt.Errorf("invalid cue timing %q", line)
+	}
+	end, err := parseCueTimestamp(endFields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseCueTimestamp parses HH:MM:SS,mmm (SRT) or [HH:]MM:SS.mmm (VTT) into seconds
+func parseCueTimestamp(value string) (float64, error) {
+	value = strings.TrimSpace(strings.Replace(value, ",", ".", 1))
+	fields := strings.Split(value, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid cue timestamp %q", value)
+	}
+
+	seconds, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cue timestamp %q", value)
+	}
+	multiplier := 60.0
+	for i := len(fields) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid cue timestamp %q", value)
+		}
+		seconds += float64(n) * multiplier
+		multiplier *= 60
+	}
+	return seconds, nil
+}
@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseTimestamp parses a timestamp given as seconds ("90", "90.5"), MM:SS ("15:30")
+// or HH:MM:SS ("1:02:03") into seconds
+func ParseTimestamp(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	fields := strings.Split(value, ":")
+	if value == "" || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q, expected seconds, MM:SS or HH:MM:SS", value)
+	}
+
+	seconds, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+	if err != nil || seconds < 0 || (len(fields) > 1 && seconds >= 60) {
+		return 0, fmt.Errorf("invalid timestamp %q, expected seconds, MM:SS or HH:MM:SS", value)
+	}
+
+	multiplier := 60.0
+	for i := len(fields) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, fmt.Errorf("invalid timestamp %q, expected seconds, MM:SS or HH:MM:SS", value)
+		}
+		seconds += float64(n) * multiplier
+		multiplier *= 60
+	}
+	return seconds, nil
+}
+
+// ParseTimestamps parses a comma-separated list of timestamps such as "90,15:30,1:02:03" into seconds
+func ParseTimestamps(list string) ([]float64, error) {
+	var timestamps []float64
+	for _, part := range strings.Split(list, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		seconds, err := ParseTimestamp(part)
+		if err != nil {
+			return nil, err
+		}
+		timestamps = append(timestamps, seconds)
+	}
+	return timestamps, nil
+}
@@ -1,17 +1,62 @@
 package processor
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/automate-podcast/services"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// トランスクリプトとして不自然な文字数の目安
+const (
+	minTranscriptChars = 100
+	maxTranscriptChars = 1000000
 )
 
-// LoadTranscript はトランスクリプトファイルを読み込む
-func LoadTranscript(path string) (string, error) {
+// utf8BOM は UTF-8 の BOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// legacyEncodings は UTF-8 でないときに試す日本語のエンコーディング
+var legacyEncodings = []struct {
+	name     string
+	encoding encoding.Encoding
+}{
+	{"Shift-JIS", japanese.ShiftJIS},
+	{"EUC-JP", japanese.EUCJP},
+}
+
+// Transcript は読み込んだトランスクリプト
+type Transcript struct {
+	// Text は AI に渡すプレーンテキスト（SRT/VTT の場合は番号やタイムコードを除いたもの）
+	Text string
+	// Segments は SRT/VTT のタイムコード付きセグメント（プレーンテキストの場合は nil）
+	Segments []services.TranscriptSegment
+	// Format は入力ファイルの形式
+	Format TranscriptFormat
+	// Turns は話者ラベル付きのトランスクリプトの発言（ラベルがない場合は nil）
+	Turns []services.Turn
+	// Encoding は変換前のファイルの文字コード（UTF-8、UTF-16、Shift-JIS、EUC-JP）
+	Encoding string
+}
+
+// LoadTranscript はトランスクリプトファイルを読み込む。
+// バイナリファイル（音声ファイルなど）はエラーとし、Shift-JIS / EUC-JP / UTF-16 のファイルは UTF-8 に変換する。
+// SRT/VTT は拡張子または内容から判定し、タイムコードを Segments に残してテキストだけを Text にする。
+func LoadTranscript(path string, logger *logrus.Logger) (*Transcript, error) {
 	// ファイルパスが絶対パスでない場合は絶対パスに変換
 	if !filepath.IsAbs(path) {
 		absPath, err := filepath.Abs(path)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		path = absPath
 	}
@@ -19,8 +64,107 @@ func LoadTranscript(path string) (string, error) {
 	// ファイルを読み込む
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	content, encodingName, err := decodeTranscript(data, logger)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	transcript := &Transcript{Text: content, Format: FormatText, Encoding: encodingName}
+
+	// 字幕形式ならタイムコードを取り除く
+	if format := DetectSubtitleFormat(path, content); format != FormatText {
+		segments, err := ParseSubtitles(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to parse %s transcript: %w", path, format, err)
+		}
+		logger.Infof("Parsed %d timed segments from %s transcript", len(segments), strings.ToUpper(string(format)))
+		transcript = &Transcript{
+			Text:     SegmentsText(segments),
+			Segments: segments,
+			Format:   format,
+			Encoding: encodingName,
+		}
+	}
+
+	// 話者ラベルがあれば発言ごとに分ける
+	if turns := ParseTurns(transcript.Text); turns != nil {
+		transcript.Turns = turns
+		logger.Infof("Found %d speaker turns by %s", len(turns), strings.Join(services.TurnSpeakers(turns), ", "))
+	}
+
+	// 文字数が極端な場合は警告する
+	chars := utf8.RuneCountInString(strings.TrimSpace(transcript.Text))
+	if chars > 0 && chars < minTranscriptChars {
+		logger.Warnf("Transcript is suspiciously short (%d characters); check that %s is the right file", chars, path)
+	} else if chars > maxTranscriptChars {
+		logger.Warnf("Transcript is suspiciously large (%d characters); check that %s is the right file", chars, path)
 	}
-	
-	return string(data), nil
+
+	return transcript, nil
+}
+
+// Problems はトランスクリプトを生成に使えない理由を返す（問題がなければ nil）
+func (t *Transcript) Problems() []string {
+	var problems []string
+	chars := utf8.RuneCountInString(strings.TrimSpace(t.Text))
+	switch {
+	case chars == 0:
+		problems = append(problems, "transcript is empty")
+	case chars < minTranscriptChars:
+		problems = append(problems, fmt.Sprintf("transcript is too short to generate from (%d characters, expected at least %d)", chars, minTranscriptChars))
+	case chars > maxTranscriptChars:
+		problems = append(problems, fmt.Sprintf("transcript is too large (%d characters, expected at most %d)", chars, maxTranscriptChars))
+	}
+	if invalid := strings.Count(t.Text, string(utf8.RuneError)); invalid > 0 {
+		problems = append(problems, fmt.Sprintf("transcript contains %d undecodable characters (U+FFFD)", invalid))
+	}
+	return problems
+}
+
+// decodeTranscript はファイルの内容を判定し、UTF-8 の文字列と元の文字コード名を返す
+func decodeTranscript(data []byte, logger *logrus.Logger) (string, string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	// テキストでないファイルは拒否する
+	contentType := http.DetectContentType(data)
+	switch {
+	case strings.HasPrefix(contentType, "text/plain; charset=utf-16"):
+		logger.Info("Transcript is encoded in UTF-16, converting to UTF-8")
+		decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder().Bytes(data)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to decode UTF-16 transcript: %w", err)
+		}
+		return string(decoded), "UTF-16", nil
+	case !strings.HasPrefix(contentType, "text/"):
+		return "", "", fmt.Errorf("transcript is not a text file (detected %s)", contentType)
+	}
+
+	if utf8.Valid(data) {
+		return string(data), "UTF-8", nil
+	}
+
+	// UTF-8 でなければ、変換後の不正な文字が最も少ない日本語エンコーディングを採用する
+	bestName := ""
+	var best []byte
+	bestInvalid := -1
+	for _, legacy := range legacyEncodings {
+		decoded, err := legacy.encoding.NewDecoder().Bytes(data)
+		if err != nil {
+			continue
+		}
+		invalid := bytes.Count(decoded, []byte(string(utf8.RuneError)))
+		if bestInvalid < 0 || invalid < bestInvalid {
+			bestName, best, bestInvalid = legacy.name, decoded, invalid
+		}
+	}
+
+	if bestInvalid != 0 {
+		return "", "", fmt.Errorf("transcript is not valid UTF-8 and could not be decoded as Shift-JIS or EUC-JP")
+	}
+
+	logger.Infof("Transcript is encoded in %s, converting to UTF-8", bestName)
+	return string(best), bestName, nil
 }
@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/automate-podcast/services"
+)
+
+// TranscriptFormat is an output format for a transcription
+type TranscriptFormat string
+
+const (
+	FormatText TranscriptFormat = "text" // Plain transcript text
+	FormatSRT  TranscriptFormat = "srt"  // SubRip subtitles
+	FormatVTT  TranscriptFormat = "vtt"  // WebVTT captions
+	FormatJSON TranscriptFormat = "json" // Timestamped segments as JSON
+)
+
+// transcriptFormatExtensions maps each format to its output file extension
+var transcriptFormatExtensions = map[TranscriptFormat]string{
+	FormatText: ".txt",
+	FormatSRT:  ".srt",
+	FormatVTT:  ".vtt",
+	FormatJSON: ".json",
+}
+
+// ParseTranscriptFormats parses a comma-separated list of output formats such as "text,srt,vtt".
+// Duplicates are removed while preserving order.
+func ParseTranscriptFormats(list string) ([]TranscriptFormat, error) {
+	var formats []TranscriptFormat
+	seen := make(map[TranscriptFormat]bool)
+
+	for _, part := range strings.Split(list, ",") {
+		format := TranscriptFormat(strings.ToLower(strings.TrimSpace(part)))
+		if format == "" {
+			continue
+		}
+		if _, ok := transcriptFormatExtensions[format]; !ok {
+			return nil, fmt.Errorf("unknown transcript format %q, expected one of text, srt, vtt, json", format)
+		}
+		if !seen[format] {
+			seen[format] = true
+			formats = append(formats, format)
+		}
+	}
+
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("at least one transcript format is required")
+	}
+	return formats, nil
+}
+
+// RequiresSegments reports whether any of the formats needs timestamped segments
+func RequiresSegments(formats []TranscriptFormat) bool {
+	for _, format := range formats {
+		if format != FormatText {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTranscriptFormats checks that formats needing timestamped segments are only
+// requested when the transcription provides them
+func ValidateTranscriptFormats(formats []TranscriptFormat, segmentsAvailable bool) error {
+	if segmentsAvailable {
+		return nil
+	}
+	for _, format := range formats {
+		if format != FormatText {
+			return fmt.Errorf("format %q requires timestamped segments, but only plain-text transcription was requested", format)
+		}
+	}
+	return nil
+}
+
+// TranscriptOutputPath returns the output file path for a format, named consistently as <dir>/<base>.<ext>
+func TranscriptOutputPath(dir, base string, format TranscriptFormat) string {
+	return filepath.Join(dir, base+transcriptFormatExtensions[format])
+}
+
+// RenderTranscript serializes a transcription result in one output format
+func RenderTranscript(result *services.TranscriptionResult, format TranscriptFormat) (string, error) {
+	switch format {
+	case FormatText:
+		return result.Text, nil
+	case FormatSRT:
+		return SegmentsToSRT(result.Segments), nil
+	case FormatVTT:
+		return SegmentsToVTT(result.Segments), nil
+	case FormatJSON:
+		return SegmentsToJSON(result)
+	default:
+		return "", fmt.Errorf("unknown transcript format %q", format)
+	}
+}
@@ -0,0 +1,143 @@
+package processor
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/automate-podcast/services"
+)
+
+// testTranscription is a transcription with two segments and an empty one that renderers skip
+var testTranscription = &services.TranscriptionResult{
+	Text:     "こんにちは。今日は夜泣きの話です。",
+	Language: "japanese",
+	Duration: 3725.5,
+	Segments: []services.TranscriptSegment{
+		{Start: 0, End: 2.5, Text: " こんにちは。 "},
+		{Start: 2.5, End: 3.0, Text: "  "},
+		{Start: 3661.0004, End: 3725.5, Text: "今日は夜泣きの話です。"},
+	},
+}
+
+func TestRenderTranscript(t *testing.T) {
+	tests := []struct {
+		format TranscriptFormat
+		want   string
+	}{
+		{
+			format: FormatText,
+			want:   "こんにちは。今日は夜泣きの話です。",
+		},
+		{
+			format: FormatSRT,
+			want: "1\n00:00:00,000 --> 00:00:02,500\nこんにちは。\n\n" +
+				"2\n01:01:01,000 --> 01:02:05,500\n今日は夜泣きの話です。\n\n",
+		},
+		{
+			format: FormatVTT,
+			want: "WEBVTT\n\n" +
+				"00:00:00.000 --> 00:00:02.500\nこんにちは。\n\n" +
+				"01:01:01.000 --> 01:02:05.500\n今日は夜泣きの話です。\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := RenderTranscript(testTranscription, tt.format)
+			if err != nil {
+				t.Fatalf("RenderTranscript() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTranscript() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTranscriptJSON(t *testing.T) {
+	got, err := RenderTranscript(testTranscription, FormatJSON)
+	if err != nil {
+		t.Fatalf("RenderTranscript() error = %v", err)
+	}
+	if !strings.HasSuffix(got, "}\n") {
+		t.Errorf("RenderTranscript() = %q, want indented JSON ending with a newline", got)
+	}
+
+	var decoded services.TranscriptionResult
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("RenderTranscript() is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, testTranscription) {
+		t.Errorf("decoded JSON = %+v, want %+v", decoded, *testTranscription)
+	}
+}
+
+func TestRenderTranscriptUnknownFormat(t *testing.T) {
+	if _, err := RenderTranscript(testTranscription, "docx"); err == nil {
+		t.Error("RenderTranscript() error = nil, want an error for an unknown format")
+	}
+}
+
+func TestParseTranscriptFormats(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []TranscriptFormat
+		wantErr bool
+	}{
+		{list: "text", want: []TranscriptFormat{FormatText}},
+		{list: " SRT, vtt ,json", want: []TranscriptFormat{FormatSRT, FormatVTT, FormatJSON}},
+		{list: "srt,text,srt", want: []TranscriptFormat{FormatSRT, FormatText}},
+		{list: "text,docx", wantErr: true},
+		{list: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			got, err := ParseTranscriptFormats(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTranscriptFormats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTranscriptFormats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTranscriptFormats(t *testing.T) {
+	tests := []struct {
+		name              string
+		formats           []TranscriptFormat
+		segmentsAvailable bool
+		wantSegments      bool
+		wantErr           bool
+	}{
+		{name: "plain text without segments", formats: []TranscriptFormat{FormatText}},
+		{name: "subtitles with segments", formats: []TranscriptFormat{FormatText, FormatSRT, FormatVTT, FormatJSON}, segmentsAvailable: true, wantSegments: true},
+		{name: "srt from plain-only transcription", formats: []TranscriptFormat{FormatText, FormatSRT}, wantSegments: true, wantErr: true},
+		{name: "json from plain-only transcription", formats: []TranscriptFormat{FormatJSON}, wantSegments: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTranscriptFormats(tt.formats, tt.segmentsAvailable)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTranscriptFormats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := RequiresSegments(tt.formats); got != tt.wantSegments {
+				t.Errorf("RequiresSegments() = %v, want %v", got, tt.wantSegments)
+			}
+		})
+	}
+}
+
+func TestTranscriptOutputPath(t *testing.T) {
+	for format, ext := range map[TranscriptFormat]string{FormatText: ".txt", FormatSRT: ".srt", FormatVTT: ".vtt", FormatJSON: ".json"} {
+		if got, want := TranscriptOutputPath("out", "ep42", format), filepath.Join("out", "ep42"+ext); got != want {
+			t.Errorf("TranscriptOutputPath(%s) = %q, want %q", format, got, want)
+		}
+	}
+}
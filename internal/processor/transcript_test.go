@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newTestLogger returns a logger that discards its output
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// sjisTranscriptText is the content of testdata/transcript_sjis.txt
+const sjisTranscriptText = "ホスト: 今日は子育てとテクノロジーについて話します。\nゲスト: よろしくお願いします。夜泣き対策のアプリを使ってみました。\n"
+
+func TestLoadTranscriptRejectsBinaryFile(t *testing.T) {
+	_, err := LoadTranscript(filepath.Join("testdata", "episode.mp3"), newTestLogger())
+	if err == nil {
+		t.Fatal("LoadTranscript() error = nil, want an error for an audio file")
+	}
+	if !strings.Contains(err.Error(), "not a text file") {
+		t.Errorf("LoadTranscript() error = %q, want it to say the file is not a text file", err)
+	}
+}
+
+func TestLoadTranscriptConvertsShiftJIS(t *testing.T) {
+	transcript, err := LoadTranscript(filepath.Join("testdata", "transcript_sjis.txt"), newTestLogger())
+	if err != nil {
+		t.Fatalf("LoadTranscript() error = %v", err)
+	}
+	if transcript.Encoding != "Shift-JIS" {
+		t.Errorf("Encoding = %q, want Shift-JIS", transcript.Encoding)
+	}
+	if transcript.Text != sjisTranscriptText {
+		t.Errorf("Text = %q, want %q", transcript.Text, sjisTranscriptText)
+	}
+	if transcript.Format != FormatText {
+		t.Errorf("Format = %q, want %q", transcript.Format, FormatText)
+	}
+}
+
+func TestLoadTranscriptEncodings(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		wantText     string
+		wantEncoding string
+	}{
+		{
+			name:         "UTF-8",
+			data:         []byte("こんにちは、momit.fm です。"),
+			wantText:     "こんにちは、momit.fm です。",
+			wantEncoding: "UTF-8",
+		},
+		{
+			name:         "UTF-8 with BOM",
+			data:         append([]byte{0xEF, 0xBB, 0xBF}, "こんにちは"...),
+			wantText:     "こんにちは",
+			wantEncoding: "UTF-8",
+		},
+		{
+			name:         "UTF-16LE with BOM",
+			data:         []byte{0xFF, 0xFE, 'h', 0, 'i', 0, 0x53, 0x30},
+			wantText:     "hiこ",
+			wantEncoding: "UTF-16",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "transcript.txt")
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			transcript, err := LoadTranscript(path, newTestLogger())
+			if err != nil {
+				t.Fatalf("LoadTranscript() error = %v", err)
+			}
+			if transcript.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", transcript.Text, tt.wantText)
+			}
+			if transcript.Encoding != tt.wantEncoding {
+				t.Errorf("Encoding = %q, want %q", transcript.Encoding, tt.wantEncoding)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/automate-podcast/services"
+)
+
+// minSpeakerLabels is the number of labeled lines needed before a transcript is treated as speaker turns
+const minSpeakerLabels = 3
+
+// maxSpeakerNameWords is the number of words a speaker label can have, so that sentences with a colon aren't taken as labels
+const maxSpeakerNameWords = 3
+
+var (
+	// speakerTimestampPattern matches an optional timestamp before a speaker label, e.g. "[00:01:23]" or "1:23"
+	speakerTimestampPattern = regexp.MustCompile(`^(?:\[` + turnTimestamp + `\]|\(` + turnTimestamp + `\)|` + turnTimestamp + `)\s*`)
+	// bracketSpeakerPattern matches "[Name] text"
+	bracketSpeakerPattern = regexp.MustCompile(`^\[(` + turnSpeakerName + `)\]\s*(.*)$`)
+	// colonSpeakerPattern matches "Name: text", "Name：text" and "Name (00:01:23): text".
+	// A half-width colon must be followed by a space so that URLs aren't taken as labels.
+	colonSpeakerPattern = regexp.MustCompile(`^(` + turnSpeakerName + `?)\s*(?:[\[(]` + turnTimestamp + `[\])])?\s*(?::(?:\s+|$)|：\s*)(.*)$`)
+	// headingSpeakerPattern matches a speaker heading followed by a timestamp on a line of its own,
+	// e.g. "Host  00:01:23", as exported by some transcription services
+	headingSpeakerPattern = regexp.MustCompile(`^(` + turnSpeakerName + `?)\s+[\[(]?` + turnTimestamp + `[\])]?$`)
+	// timestampOnlyPattern matches a name that is actually a timestamp
+	timestampOnlyPattern = regexp.MustCompile(`^` + turnTimestamp + `$`)
+)
+
+// Building blocks of the speaker label patterns
+const (
+	turnTimestamp   = `(?:\d{1,2}:)?\d{1,2}:\d{2}(?:[.,]\d{1,3})?`
+	turnSpeakerName = `[^\s:：\[\]()（）][^:：\[\]()（）。、,.!?！？]{0,29}`
+)
+
+// ParseTurns splits a transcript with speaker labels such as "Host: ...", "[Guest] ..." or
+// "[00:01:23] Host: ..." into speaker turns. Lines without a label continue the current
+// turn, timestamps are dropped and consecutive turns of the same speaker are merged.
+// It returns nil if the transcript doesn't look speaker-labeled.
+func ParseTurns(text string) []services.Turn {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var turns []services.Turn
+	var current *services.Turn
+	labels := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		speaker, rest, ok := parseSpeakerLine(line)
+		if !ok {
+			// Unlabeled text continues the current turn
+			if current == nil {
+				turns = append(turns, services.Turn{})
+				current = &turns[len(turns)-1]
+			}
+			current.Text = joinTurnText(current.Text, line)
+			continue
+		}
+
+		labels++
+		if current == nil || current.Speaker != speaker {
+			turns = append(turns, services.Turn{Speaker: speaker})
+			current = &turns[len(turns)-1]
+		}
+		current.Text = joinTurnText(current.Text, rest)
+	}
+
+	if labels < minSpeakerLabels || len(services.TurnSpeakers(turns)) < 2 {
+		return nil
+	}
+
+	// Drop turns that only had a label
+	var result []services.Turn
+	for _, turn := range turns {
+		if turn.Text != "" {
+			result = append(result, turn)
+		}
+	}
+	return result
+}
+
+// parseSpeakerLine returns the speaker and the text after the label of a labeled line
+func parseSpeakerLine(line string) (string, string, bool) {
+	line = speakerTimestampPattern.ReplaceAllString(line, "")
+
+	for _, pattern := range []*regexp.Regexp{bracketSpeakerPattern, colonSpeakerPattern} {
+		if m := pattern.FindStringSubmatch(line); m != nil && validSpeakerName(m[1]) {
+			return strings.TrimSpace(m[1]), strings.TrimSpace(m[2]), true
+		}
+	}
+	if m := headingSpeakerPattern.FindStringSubmatch(line); m != nil && validSpeakerName(m[1]) {
+		return strings.TrimSpace(m[1]), "", true
+	}
+	return "", "", false
+}
+
+// validSpeakerName reports whether a label candidate looks like a name rather than part of a sentence
+func validSpeakerName(name string) bool {
+	name = strings.TrimSpace(name)
+	return name != "" && !timestampOnlyPattern.MatchString(name) && len(strings.Fields(name)) <= maxSpeakerNameWords
+}
+
+// joinTurnText appends a line to the text of a turn
+func joinTurnText(text, line string) string {
+	if text == "" || line == "" {
+		return text + line
+	}
+	return text + "\n" + line
+}
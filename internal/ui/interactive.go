@@ -1,6 +1,15 @@
 package ui
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
 	"github.com/automate-podcast/internal/model"
 	"github.com/sirupsen/logrus"
 )
@@ -8,19 +17,281 @@ import (
 // InteractiveUI provides an interactive user interface
 type InteractiveUI struct {
 	logger *logrus.Logger
+	in     *bufio.Reader
+	out    io.Writer
+
+	// NonInteractive selects the first candidates without prompting, e.g. for CI
+	NonInteractive bool
+	// Regenerate, when set, produces a new batch of candidates on request
+	Regenerate func() (*model.ContentCandidates, error)
+	// MaxRegenerations limits how many times candidates can be regenerated
+	MaxRegenerations int
+	// Editor is the command used to edit the selected content (default: $VISUAL or $EDITOR).
+	// When empty, edits are read inline from stdin.
+	Editor string
+	// TitleIndex and ShowNoteIndex select candidates by 1-based index without prompting.
+	// When either is set, the other defaults to the first candidate.
+	TitleIndex    int
+	ShowNoteIndex int
+
+	regenerations int
 }
 
+// DefaultMaxRegenerations is the default limit on candidate regenerations
+const DefaultMaxRegenerations = 5
+
+// errRegenerate signals that the user asked for new candidates
+var errRegenerate = errors.New("regenerate requested")
+
+// editorCommand creates the command that runs the editor; tests replace it with a stub
+var editorCommand = exec.Command
+
 // NewInteractiveUI creates a new InteractiveUI instance
 func NewInteractiveUI(logger *logrus.Logger) *InteractiveUI {
 	return &InteractiveUI{
 		logger: logger,
+		in:     bufio.NewReader(os.Stdin),
+		out:    os.Stdout,
+		Editor: defaultEditor(),
+
+		MaxRegenerations: DefaultMaxRegenerations,
 	}
 }
 
-// SelectContent allows users to select from content candidates
+// defaultEditor returns the user's preferred editor from the environment
+func defaultEditor() string {
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	return os.Getenv("EDITOR")
+}
+
+// SelectContent allows users to select from content candidates.
+// When Regenerate is set, the user can ask for a new batch; candidates is then updated in place.
 func (ui *InteractiveUI) SelectContent(candidates *model.ContentCandidates) (*model.SelectedContent, error) {
+	if ui.NonInteractive || ui.TitleIndex > 0 || ui.ShowNoteIndex > 0 {
+		return ui.selectByIndex(candidates)
+	}
+
+	selected, err := ui.selectCandidates(candidates)
+	for err == errRegenerate {
+		ui.regenerations++
+		ui.logger.Infof("Regenerating candidates (%d/%d)...", ui.regenerations, ui.MaxRegenerations)
+		regenerated, genErr := ui.Regenerate()
+		if genErr != nil {
+			return nil, fmt.Errorf("failed to regenerate candidates: %w", genErr)
+		}
+		*candidates = *regenerated
+		selected, err = ui.selectCandidates(candidates)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Offer to tweak the selected content before it is saved
+	if err := ui.editContent(selected); err != nil {
+		return nil, err
+	}
+
+	ui.logger.Infof("Selected title: %s", selected.Title)
+	return selected, nil
+}
+
+// editContent asks whether to edit the selected title and show note and applies the edits
+func (ui *InteractiveUI) editContent(selected *model.SelectedContent) error {
+	if selected.Title != "" {
+		edit, err := ui.Confirm("Edit the title?")
+		if err != nil {
+			return err
+		}
+		if edit {
+			title, err := ui.editText(selected.Title, false)
+			if err != nil {
+				return err
+			}
+			selected.Title = strings.TrimSpace(title)
+		}
+	}
+
+	if selected.ShowNote != "" {
+		edit, err := ui.Confirm("Edit the show note?")
+		if err != nil {
+			return err
+		}
+		if edit {
+			showNote, err := ui.editText(selected.ShowNote, true)
+			if err != nil {
+				return err
+			}
+			selected.ShowNote = strings.TrimSpace(showNote)
+		}
+	}
+
+	return nil
+}
+
+// Confirm asks a yes/no question; anything but y/yes (including EOF) means no
+func (ui *InteractiveUI) Confirm(question string) (bool, error) {
+	fmt.Fprintf(ui.out, "%s [y/N]: ", question)
+	line, err := ui.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	if err == io.EOF && line == "" {
+		fmt.Fprintln(ui.out)
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
+
+// editText lets the user edit a text in their editor, or inline when no editor is configured
+func (ui *InteractiveUI) editText(text string, multiline bool) (string, error) {
+	if ui.Editor == "" {
+		return ui.editInline(text, multiline)
+	}
+
+	// Write the text to a temp file for the editor
+	file, err := os.CreateTemp("", "aipodflow-edit-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file for editing: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write temp file for editing: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temp file for editing: %w", err)
+	}
+
+	// The editor setting may include arguments, e.g. "code --wait"
+	fields := strings.Fields(ui.Editor)
+	cmd := editorCommand(fields[0], append(fields[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %w", ui.Editor, err)
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited text: %w", err)
+	}
+	return string(edited), nil
+}
+
+// editInline reads a replacement text from stdin. Single-line texts take one line;
+// multi-line texts are read until a line containing only "." or EOF.
+// An empty answer keeps the original text.
+func (ui *InteractiveUI) editInline(text string, multiline bool) (string, error) {
+	if !multiline {
+		fmt.Fprintf(ui.out, "Current: %s\nNew (empty to keep): ", text)
+		line, err := ui.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read edited text: %w", err)
+		}
+		if strings.TrimSpace(line) == "" {
+			return text, nil
+		}
+		return line, nil
+	}
+
+	fmt.Fprintln(ui.out, "Enter the new text. Finish with a line containing only \".\" (empty to keep):")
+	var lines []string
+	for {
+		line, err := ui.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read edited text: %w", err)
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "." {
+			break
+		}
+		if line != "" {
+			lines = append(lines, trimmed)
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	edited := strings.Join(lines, "\n")
+	if strings.TrimSpace(edited) == "" {
+		return text, nil
+	}
+	return edited, nil
+}
+
+// selectCandidates prompts for a title and a show note.
+// It returns errRegenerate if the user asked for new candidates.
+func (ui *InteractiveUI) selectCandidates(candidates *model.ContentCandidates) (*model.SelectedContent, error) {
 	selected := &model.SelectedContent{}
 
+	// Select a title
+	if len(candidates.Titles) > 0 {
+		fmt.Fprintln(ui.out, "\n=== TITLE CANDIDATES ===")
+		for i, title := range candidates.Titles {
+			fmt.Fprintf(ui.out, "[%d] %s\n", i+1, title)
+		}
+		index, err := ui.promptIndex("Select a title", len(candidates.Titles))
+		if err != nil {
+			return nil, err
+		}
+		selected.Title = candidates.Titles[index]
+	} else {
+		ui.logger.Warn("No title proposal available")
+	}
+
+	// Select a show note
+	if len(candidates.ShowNotes) > 0 {
+		fmt.Fprintln(ui.out, "\n=== SHOW NOTE CANDIDATES ===")
+		for i, note := range candidates.ShowNotes {
+			fmt.Fprintf(ui.out, "[%d]\n%s\n\n", i+1, note)
+		}
+		index, err := ui.promptIndex("Select a show note", len(candidates.ShowNotes))
+		if err != nil {
+			return nil, err
+		}
+		selected.ShowNote = candidates.ShowNotes[index]
+	} else {
+		ui.logger.Warn("No show note proposal available")
+	}
+
+	// Show the suggested ad breaks for reference
+	if len(candidates.AdTimecodes) > 0 {
+		fmt.Fprintln(ui.out, "\n=== AD TIMECODE SUGGESTIONS ===")
+		lines, markers := adTimecodeLines(candidates.AdTimecodes)
+		for i, line := range lines {
+			fmt.Fprintf(ui.out, "[%d] %s\n", i+1, line)
+		}
+		fmt.Fprintf(ui.out, "Use with step2: --ad-markers %s\n", markers)
+	}
+
+	return selected, nil
+}
+
+// canRegenerate reports whether the user may still ask for new candidates
+func (ui *InteractiveUI) canRegenerate() bool {
+	return ui.Regenerate != nil && ui.regenerations < ui.MaxRegenerations
+}
+
+// selectByIndex displays the candidates and selects the ones at TitleIndex and ShowNoteIndex
+// (the first of each by default) without prompting
+func (ui *InteractiveUI) selectByIndex(candidates *model.ContentCandidates) (*model.SelectedContent, error) {
+	selected := &model.SelectedContent{}
+
+	titleIndex := max(ui.TitleIndex, 1)
+	if ui.TitleIndex > 0 && ui.TitleIndex > len(candidates.Titles) {
+		return nil, fmt.Errorf("--title-index %d is out of range: there are %d title candidates", ui.TitleIndex, len(candidates.Titles))
+	}
+	showNoteIndex := max(ui.ShowNoteIndex, 1)
+	if ui.ShowNoteIndex > 0 && ui.ShowNoteIndex > len(candidates.ShowNotes) {
+		return nil, fmt.Errorf("--shownote-index %d is out of range: there are %d show note candidates", ui.ShowNoteIndex, len(candidates.ShowNotes))
+	}
+
 	ui.logger.Info("Starting content display process...")
 
 	// Display all title candidates
@@ -35,26 +306,100 @@ func (ui *InteractiveUI) SelectContent(candidates *model.ContentCandidates) (*mo
 		ui.logger.Infof("[%d]\n%s\n", i+1, note)
 	}
 
-	// Automatically select the first candidates without prompting
+	// Select the candidates without prompting
 	if len(candidates.Titles) > 0 {
-		selected.Title = candidates.Titles[0]
+		selected.Title = candidates.Titles[titleIndex-1]
+		ui.logger.Infof("Selected title [%d]", titleIndex)
 	} else {
 		ui.logger.Warn("No title proposal available")
-		selected.Title = ""
 	}
 
 	if len(candidates.ShowNotes) > 0 {
-		selected.ShowNote = candidates.ShowNotes[0]
+		selected.ShowNote = candidates.ShowNotes[showNoteIndex-1]
+		ui.logger.Infof("Selected show note [%d]", showNoteIndex)
 	} else {
 		ui.logger.Warn("No show note proposal available")
-		selected.ShowNote = ""
+	}
+
+	// Display the suggested ad breaks
+	if len(candidates.AdTimecodes) > 0 {
+		ui.logger.Info("\n=== AD TIMECODE SUGGESTIONS ===")
+		lines, markers := adTimecodeLines(candidates.AdTimecodes)
+		for i, line := range lines {
+			ui.logger.Infof("[%d] %s", i+1, line)
+		}
+		ui.logger.Infof("Use with step2: --ad-markers %s", markers)
 	}
 
 	ui.logger.Info("Content display completed successfully")
 	return selected, nil
 }
 
-// Note: These formatting functions have been removed as they are no longer used
-// in the current implementation of the interactive UI.
+// adTimecodeLines formats ad timecode suggestions for display and returns the
+// matching comma-separated step2 --ad-markers value
+func adTimecodeLines(adTimecodes [][]string) ([]string, string) {
+	var lines []string
+	var markers []string
+	for _, suggestion := range adTimecodes {
+		if len(suggestion) == 0 {
+			continue
+		}
+		markers = append(markers, suggestion[0])
+		if len(suggestion) > 1 {
+			lines = append(lines, fmt.Sprintf("%s  %s", suggestion[0], suggestion[1]))
+		} else {
+			lines = append(lines, suggestion[0])
+		}
+	}
+	return lines, strings.Join(markers, ",")
+}
+
+// promptIndex asks for a 1-based choice between 1 and count and returns it as a 0-based index.
+// An empty answer selects the first candidate, and so does EOF (e.g. piped input).
+// Answering "r" returns errRegenerate while regenerations are allowed.
+func (ui *InteractiveUI) promptIndex(label string, count int) (int, error) {
+	if count == 1 && !ui.canRegenerate() {
+		return 0, nil
+	}
+
+	for {
+		if ui.canRegenerate() {
+			fmt.Fprintf(ui.out, "%s [1-%d, r) regenerate] (default 1): ", label, count)
+		} else {
+			fmt.Fprintf(ui.out, "%s [1-%d] (default 1): ", label, count)
+		}
+		line, err := ui.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return 0, fmt.Errorf("failed to read selection: %w", err)
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			if err == io.EOF {
+				fmt.Fprintln(ui.out)
+				ui.logger.Info("No input available, selecting the first candidate")
+			}
+			return 0, nil
+		}
+
+		if strings.EqualFold(answer, "r") {
+			if ui.canRegenerate() {
+				return 0, errRegenerate
+			}
+			if ui.Regenerate != nil {
+				fmt.Fprintf(ui.out, "The maximum of %d regenerations has been reached\n", ui.MaxRegenerations)
+			}
+		} else {
+			choice, convErr := strconv.Atoi(answer)
+			if convErr == nil && choice >= 1 && choice <= count {
+				return choice - 1, nil
+			}
+			fmt.Fprintf(ui.out, "Please enter a number between 1 and %d\n", count)
+		}
 
-// Ad timecode formatting removed
+		if err == io.EOF {
+			ui.logger.Info("No more input available, selecting the first candidate")
+			return 0, nil
+		}
+	}
+}
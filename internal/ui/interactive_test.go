@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/automate-podcast/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// newTestUI returns an InteractiveUI that reads the given input and discards its output
+func newTestUI(input, editor string) *InteractiveUI {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &InteractiveUI{
+		logger: logger,
+		in:     bufio.NewReader(strings.NewReader(input)),
+		out:    io.Discard,
+		Editor: editor,
+	}
+}
+
+// editorCall is one run of the stubbed editor command
+type editorCall struct {
+	name string
+	args []string
+}
+
+// stubEditor replaces the editor command with a run of this test binary as TestHelperEditor,
+// which replaces the file's content with edited, or fails when edited is empty
+func stubEditor(t *testing.T, edited string) *[]editorCall {
+	t.Helper()
+	var calls []editorCall
+	original := editorCommand
+	editorCommand = func(name string, args ...string) *exec.Cmd {
+		calls = append(calls, editorCall{name: name, args: args})
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperEditor$", "--", args[len(args)-1])
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_EDITOR=1", "HELPER_EDITOR_TEXT="+edited)
+		return cmd
+	}
+	t.Cleanup(func() { editorCommand = original })
+	return &calls
+}
+
+// TestHelperEditor is not a real test: it is the editor process run by stubEditor
+func TestHelperEditor(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_EDITOR") != "1" {
+		return
+	}
+	path := os.Args[len(os.Args)-1]
+	edited := os.Getenv("HELPER_EDITOR_TEXT")
+	if edited == "" {
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+func TestEditTextRunsEditor(t *testing.T) {
+	calls := stubEditor(t, "edited show note\n")
+	ui := newTestUI("", "code --wait")
+
+	edited, err := ui.editText("original show note", true)
+	if err != nil {
+		t.Fatalf("editText() error = %v", err)
+	}
+	if edited != "edited show note\n" {
+		t.Errorf("editText() = %q, want %q", edited, "edited show note\n")
+	}
+
+	if len(*calls) != 1 {
+		t.Fatalf("editor ran %d times, want 1", len(*calls))
+	}
+	call := (*calls)[0]
+	if call.name != "code" || len(call.args) != 2 || call.args[0] != "--wait" {
+		t.Errorf("editor command = %s %v, want code --wait <file>", call.name, call.args)
+	}
+	if _, err := os.Stat(call.args[1]); !os.IsNotExist(err) {
+		t.Errorf("temp file %s was not removed after editing", call.args[1])
+	}
+}
+
+func TestEditTextEditorFails(t *testing.T) {
+	stubEditor(t, "")
+	ui := newTestUI("", "vim")
+
+	if _, err := ui.editText("original", false); err == nil {
+		t.Fatal("editText() error = nil, want an error when the editor fails")
+	}
+}
+
+func TestSelectContentEditsWithEditor(t *testing.T) {
+	calls := stubEditor(t, "  Edited title\n")
+	// Select title 2 and show note 1, edit the title and keep the show note
+	ui := newTestUI("2\n1\ny\nn\n", "nano")
+
+	candidates := &model.ContentCandidates{
+		Titles:    []string{"First title", "Second title"},
+		ShowNotes: []string{"First show note", "Second show note"},
+	}
+	selected, err := ui.SelectContent(candidates)
+	if err != nil {
+		t.Fatalf("SelectContent() error = %v", err)
+	}
+	if selected.Title != "Edited title" {
+		t.Errorf("Title = %q, want the trimmed editor output %q", selected.Title, "Edited title")
+	}
+	if selected.ShowNote != "First show note" {
+		t.Errorf("ShowNote = %q, want %q", selected.ShowNote, "First show note")
+	}
+	if len(*calls) != 1 {
+		t.Errorf("editor ran %d times, want 1", len(*calls))
+	}
+}
+
+func TestEditTextInlineWithoutEditor(t *testing.T) {
+	calls := stubEditor(t, "unused")
+	ui := newTestUI("line one\nline two\n.\n", "")
+
+	edited, err := ui.editText("original", true)
+	if err != nil {
+		t.Fatalf("editText() error = %v", err)
+	}
+	if edited != "line one\nline two" {
+		t.Errorf("editText() = %q, want %q", edited, "line one\nline two")
+	}
+	if len(*calls) != 0 {
+		t.Errorf("editor ran %d times without an editor configured, want 0", len(*calls))
+	}
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/automate-podcast/services"
+	"golang.org/x/term"
+)
+
+// ANSI escape codes used by the show note preview
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiUnderline = "\033[4m"
+	ansiGreen     = "\033[32m"
+	ansiYellow    = "\033[33m"
+)
+
+var (
+	// previewBoldPattern matches markdown bold text such as **headline**
+	previewBoldPattern = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	// previewURLPattern matches URLs in show note text
+	previewURLPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+)
+
+// Blocks of a rendered show note; a blank line is printed between two blocks
+const (
+	previewParagraph = iota + 1
+	previewList
+	previewCTA
+	previewCredits
+)
+
+// previewStyle applies ANSI styles to the preview, or nothing when styles are disabled
+type previewStyle bool
+
+// wrap surrounds text with the given ANSI codes
+func (s previewStyle) wrap(text string, codes ...string) string {
+	if !s || text == "" {
+		return text
+	}
+	return strings.Join(codes, "") + text + ansiReset
+}
+
+// inline renders the **bold** text of a line in bold and underlines its URLs
+func (s previewStyle) inline(line string) string {
+	line = previewBoldPattern.ReplaceAllStringFunc(line, func(match string) string {
+		return s.wrap(previewBoldPattern.FindStringSubmatch(match)[1], ansiBold)
+	})
+	return previewURLPattern.ReplaceAllStringFunc(line, func(link string) string {
+		return s.wrap(link, ansiUnderline)
+	})
+}
+
+// bullet renders a "[emoji] headline: description" bullet with its headline in bold
+func (s previewStyle) bullet(line string) string {
+	i := strings.IndexAny(line, ":：")
+	if i <= 0 || !services.IsShowNoteBullet(line) {
+		return s.inline(line)
+	}
+	_, size := utf8.DecodeRuneInString(line[i:])
+	return s.wrap(line[:i+size], ansiBold) + s.inline(line[i+size:])
+}
+
+// RenderShowNote renders a title and show note as Art19 lays them out, to check the format before
+// uploading: bullets are grouped into one list with bold headlines, the CTA block is kept between
+// its dimmed dotted lines and the credits get a bold header. The format violations found by
+// processor.ValidateShowNote are listed at the end. ANSI styles are left out unless styled is set.
+func RenderShowNote(title, note string, violations []string, styled bool) string {
+	style := previewStyle(styled)
+	note = strings.ReplaceAll(strings.ReplaceAll(note, "\r\n", "\n"), "\r", "\n")
+
+	var sb strings.Builder
+	sb.WriteString("\n=== SHOW NOTE PREVIEW ===\n\n")
+	sb.WriteString(style.wrap(strings.TrimSpace(title), ansiBold, ansiUnderline) + "\n")
+
+	// Like the HTML, paragraphs are split at blank lines while blank lines between bullets are dropped
+	current, blank := 0, true
+	startBlock := func(block int) {
+		if block != current || blank {
+			sb.WriteString("\n")
+		}
+		current, blank = block, false
+	}
+
+	inCTA, inCredits := false, false
+	for _, line := range strings.Split(strings.TrimSpace(note), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case services.IsCTADivider(line):
+			// The dividers open and close the CTA block
+			if !inCTA {
+				startBlock(previewCTA)
+			}
+			sb.WriteString(style.wrap(line, ansiDim) + "\n")
+			if inCTA {
+				current = 0
+			}
+			inCTA = !inCTA
+		case inCTA:
+			if line != "" {
+				sb.WriteString("  " + style.inline(line) + "\n")
+			}
+		case line == "":
+			blank = true
+		case strings.HasPrefix(line, services.ShowNoteCreditsHeader):
+			current = 0
+			startBlock(previewCredits)
+			sb.WriteString(style.wrap(line, ansiBold) + "\n")
+			inCredits = true
+		case inCredits:
+			startBlock(previewCredits)
+			sb.WriteString(style.inline(line) + "\n")
+		case services.IsShowNoteListItem(line):
+			if current == previewList {
+				blank = false
+			}
+			startBlock(previewList)
+			sb.WriteString("  • " + style.bullet(services.TrimShowNoteListMarker(line)) + "\n")
+		default:
+			startBlock(previewParagraph)
+			sb.WriteString(style.inline(line) + "\n")
+		}
+	}
+
+	// List the format problems below the show note
+	sb.WriteString("\n")
+	if len(violations) == 0 {
+		sb.WriteString(style.wrap("✓ The show note follows the required format", ansiGreen) + "\n")
+		return sb.String()
+	}
+	sb.WriteString(style.wrap(fmt.Sprintf("⚠ %d format problem(s):", len(violations)), ansiBold, ansiYellow) + "\n")
+	for _, violation := range violations {
+		sb.WriteString(style.wrap("  - "+violation, ansiYellow) + "\n")
+	}
+	return sb.String()
+}
+
+// PreviewShowNote shows the rendered title and show note and asks whether to continue.
+// It is styled only when stdout is a terminal and NO_COLOR is not set.
+func (ui *InteractiveUI) PreviewShowNote(title, note string, violations []string) (bool, error) {
+	styled := ui.out == os.Stdout && term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == ""
+	fmt.Fprintln(ui.out, RenderShowNote(title, note, violations, styled))
+	return ui.Confirm("Continue with the upload?")
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+// spinnerFrames are the animation frames of the spinner
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinnerInterval is the time between spinner frames
+const spinnerInterval = 100 * time.Millisecond
+
+// Spinner shows an animated status line with the elapsed time on stderr while a long call runs
+type Spinner struct {
+	logger  *logrus.Logger
+	message string
+	started time.Time
+	hooks   logrus.LevelHooks
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// StartSpinner starts a spinner with the given message. It does nothing when stderr isn't
+// a terminal or debug logging is enabled, since the logs already show progress then.
+// Log lines written while the spinner runs clear its line first.
+func StartSpinner(logger *logrus.Logger, message string) *Spinner {
+	s := &Spinner{logger: logger, message: message, started: time.Now()}
+	if logger.IsLevelEnabled(logrus.DebugLevel) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return s
+	}
+
+	// Keep the existing hooks and add one that clears the spinner line before each log entry
+	hooks := make(logrus.LevelHooks)
+	s.hooks = logger.ReplaceHooks(hooks)
+	for level, levelHooks := range s.hooks {
+		hooks[level] = append(hooks[level], levelHooks...)
+	}
+	hooks.Add(&spinnerHook{spinner: s})
+
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.run()
+	return s
+}
+
+// Stop stops the spinner and clears its line. It is safe to call more than once.
+func (s *Spinner) Stop() {
+	if s == nil || s.stop == nil {
+		return
+	}
+
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+		s.mu.Unlock()
+		return
+	default:
+		close(s.stop)
+	}
+	s.mu.Unlock()
+
+	<-s.stopped
+	s.logger.ReplaceHooks(s.hooks)
+}
+
+// run redraws the spinner until it is stopped
+func (s *Spinner) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		s.mu.Lock()
+		fmt.Fprintf(os.Stderr, "\r%s %s %s\033[K", spinnerFrames[frame%len(spinnerFrames)], s.message, time.Since(s.started).Truncate(time.Second))
+		s.mu.Unlock()
+
+		select {
+		case <-s.stop:
+			s.clearLine()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// clearLine erases the spinner line
+func (s *Spinner) clearLine() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprint(os.Stderr, "\r\033[K")
+}
+
+// spinnerHook clears the spinner line before a log entry is written
+type spinnerHook struct {
+	spinner *Spinner
+}
+
+// Levels returns the log levels the hook fires for
+func (h *spinnerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire clears the spinner line; the spinner redraws itself on the next frame
+func (h *spinnerHook) Fire(*logrus.Entry) error {
+	h.spinner.clearLine()
+	return nil
+}
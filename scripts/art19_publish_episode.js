@@ -0,0 +1,55 @@
+const { chromium } = require('playwright');
+
+(async () => {
+  const browser = await chromium.launch();
+  const page = await browser.newPage();
+
+  // 1. Art19ログイン
+  await page.goto('https://art19.com/login');
+  await page.fill('input[name="email"]', process.env.ART19_USERNAME);
+  await page.fill('input[name="password"]', process.env.ART19_PASSWORD);
+  await page.click('button[type="submit"]');
+  await page.waitForNavigation();
+
+  // 2. エピソード編集画面へ遷移
+  const episodeID = process.env.ART19_EPISODE_ID;
+  await page.goto(`https://art19.com/episodes/${episodeID}/edit`);
+
+  // 3. 現在の公開状態を確認（ドラフト以外は公開しない）
+  // "Unpublish" や "Published" に一致しないよう、ボタン名とラベルは完全一致で探す
+  const publishButton = page.getByRole('button', { name: 'Publish', exact: true });
+  const publishedIndicator = page.getByText('Published', { exact: true }).first();
+  let status = 'unknown';
+  if (await publishButton.isVisible()) {
+    status = 'draft';
+  } else if (await publishedIndicator.isVisible()) {
+    status = 'published';
+  }
+
+  // 4. 確認のみでなければ公開する
+  if (status === 'draft' && process.env.ART19_PUBLISH_CHECK_ONLY !== '1') {
+    await publishButton.click();
+    // 確認ダイアログが表示された場合は承認する
+    const confirmButton = page.getByRole('dialog').getByRole('button', { name: 'Publish', exact: true });
+    try {
+      await confirmButton.waitFor({ state: 'visible', timeout: 2000 });
+      await confirmButton.click();
+    } catch (e) {
+      // 確認ダイアログなし
+    }
+
+    // 公開済みの表示を確認できた場合だけ公開済みとする
+    try {
+      await publishedIndicator.waitFor({ state: 'visible', timeout: 15000 });
+      status = 'published';
+    } catch (e) {
+      console.error('Published indicator did not appear after clicking Publish:', e.message);
+      status = 'unknown';
+    }
+  }
+
+  // 5. 結果を出力
+  console.log(JSON.stringify({ episodeID, episodeURL: page.url(), status }));
+
+  await browser.close();
+})();
@@ -0,0 +1,26 @@
+const { chromium } = require('playwright');
+
+(async () => {
+  const browser = await chromium.launch();
+  const page = await browser.newPage();
+
+  // 1. Art19ログイン
+  await page.goto('https://art19.com/login');
+  await page.fill('input[name="email"]', process.env.ART19_USERNAME);
+  await page.fill('input[name="password"]', process.env.ART19_PASSWORD);
+  await page.click('button[type="submit"]');
+  await page.waitForNavigation();
+
+  // 2. エピソード編集画面へ遷移
+  const episodeID = process.env.ART19_EPISODE_ID;
+  await page.goto(`https://art19.com/episodes/${episodeID}/edit`);
+
+  // 3. タイトルとShowNote（WYSIWYGエディタのHTML）を読み取る
+  const title = await page.inputValue('input[name="title"]');
+  const showNote = await page.$eval('div[contenteditable="true"]', (el) => el.innerHTML).catch(() => '');
+
+  // 4. 結果を出力
+  console.log(JSON.stringify({ episodeID, episodeURL: page.url(), title, showNote }));
+
+  await browser.close();
+})();
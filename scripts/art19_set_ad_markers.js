@@ -0,0 +1,38 @@
+const { chromium } = require('playwright');
+
+(async () => {
+  const browser = await chromium.launch();
+  const page = await browser.newPage();
+
+  // 1. Art19ログイン
+  await page.goto('https://art19.com/login');
+  await page.fill('input[name="email"]', process.env.ART19_USERNAME);
+  await page.fill('input[name="password"]', process.env.ART19_PASSWORD);
+  await page.click('button[type="submit"]');
+  await page.waitForNavigation();
+
+  // 2. エピソードのマーカー設定画面へ遷移
+  const episodeID = process.env.ART19_EPISODE_ID;
+  await page.goto(`https://art19.com/episodes/${episodeID}/marker_points`);
+
+  // 3. 広告マーカーを追加（秒数の配列、昇順）
+  const markers = JSON.parse(process.env.AD_MARKERS || '[]');
+  for (const seconds of markers) {
+    const h = Math.floor(seconds / 3600);
+    const m = Math.floor((seconds % 3600) / 60);
+    const s = (seconds % 60).toFixed(3).padStart(6, '0');
+    const timecode = `${String(h).padStart(2, '0')}:${String(m).padStart(2, '0')}:${s}`;
+
+    await page.click('button:has-text("Add Marker")');
+    await page.fill('input[name="start_position"] >> nth=-1', timecode);
+  }
+
+  // 4. 保存
+  await page.click('button:has-text("Save")');
+  await page.waitForTimeout(2000);
+
+  // 5. 結果を出力
+  console.log(JSON.stringify({ episodeID, episodeURL: page.url() }));
+
+  await browser.close();
+})();
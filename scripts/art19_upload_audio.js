@@ -0,0 +1,41 @@
+const { chromium } = require('playwright');
+
+(async () => {
+  const browser = await chromium.launch();
+  const page = await browser.newPage();
+
+  // 1. Art19ログイン
+  await page.goto('https://art19.com/login');
+  await page.fill('input[name="email"]', process.env.ART19_USERNAME);
+  await page.fill('input[name="password"]', process.env.ART19_PASSWORD);
+  await page.click('button[type="submit"]');
+  await page.waitForNavigation();
+
+  // 2. エピソード作成画面へ遷移（番組URLは要指定）
+  await page.goto(process.env.ART19_EPISODE_NEW_URL);
+
+  // 3. タイトル・説明の入力（指定されている場合のみ）
+  if (process.env.EPISODE_TITLE) {
+    await page.fill('input[name="title"]', process.env.EPISODE_TITLE);
+  }
+  if (process.env.EPISODE_SHOWNOTE) {
+    await page.$eval('div[contenteditable="true"]', (el, html) => {
+      el.innerHTML = html;
+      el.dispatchEvent(new Event('input', { bubbles: true }));
+    }, process.env.EPISODE_SHOWNOTE);
+  }
+
+  // 4. 音声ファイルのアップロード
+  await page.setInputFiles('input[type="file"]', process.env.ART19_AUDIO_PATH);
+  await page.waitForSelector('text=Upload complete', { timeout: 10 * 60 * 1000 });
+
+  // 5. ドラフト保存
+  await page.click('button:has-text("Save as Draft")');
+  await page.waitForTimeout(2000);
+
+  // 6. エピソードIDをURLから取得して出力
+  const match = page.url().match(/episodes\/([0-9a-f-]+)/);
+  console.log(JSON.stringify({ episodeID: match ? match[1] : '', episodeURL: page.url() }));
+
+  await browser.close();
+})();
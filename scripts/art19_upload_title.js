@@ -17,9 +17,21 @@ const { chromium } = require('playwright');
   // 3. タイトル入力
   await page.fill('input[name="title"]', process.env.EPISODE_TITLE);
 
-  // 4. ドラフト保存
+  // 4. ShowNote入力（WYSIWYGエディタのためHTMLとして流し込む）
+  if (process.env.EPISODE_SHOWNOTE) {
+    await page.$eval('div[contenteditable="true"]', (el, html) => {
+      el.innerHTML = html;
+      el.dispatchEvent(new Event('input', { bubbles: true }));
+    }, process.env.EPISODE_SHOWNOTE);
+  }
+
+  // 5. ドラフト保存
   await page.click('button:has-text("Save as Draft")');
   await page.waitForTimeout(2000);
 
+  // 6. 作成されたエピソードのURL・IDを出力
+  const match = page.url().match(/episodes\/([0-9a-f-]+)/);
+  console.log(JSON.stringify({ episodeID: match ? match[1] : '', episodeURL: page.url() }));
+
   await browser.close();
 })();
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// adTimecodesMaxTokens is the maximum number of tokens requested for ad break suggestions
+const adTimecodesMaxTokens = 1000
+
+// adTimecodesSystemPrompt is the system prompt used for ad break suggestions
+const adTimecodesSystemPrompt = "You are a podcast producer placing mid-roll ads. Choose natural ad breaks at topic transitions, never in the middle of a story or a sentence. Write the rationale in the transcript's language."
+
+// adTimecodesPrompt asks for ad break points in a timestamped transcript part
+const adTimecodesPrompt = `Below is part %d of %d of a podcast transcript. Each line starts with its start time as [HH:MM:SS].
+
+Suggest up to %d natural ad break points in this part, such as transitions between topics.
+Answer with one break per line, in this exact format and nothing else:
+HH:MM:SS | short rationale
+
+Use only start times that appear in the transcript.
+
+%s`
+
+// adTimecodeLinePattern matches a suggestion line such as "00:12:30 | 話題の切り替わり",
+// optionally with a list marker or the timecode in brackets
+var adTimecodeLinePattern = regexp.MustCompile(`^(?:[-*•・]|\d+[.)])?\s*\[?((?:\d{1,2}:)?\d{1,2}:\d{2})\]?\s*[|｜\-–—:]\s*(.+)$`)
+
+// maxAdBreaksPerPart is the number of ad breaks requested from each transcript part
+const maxAdBreaksPerPart = 3
+
+// GenerateAdTimecodes asks the model for natural ad break points in a timestamped transcript.
+// It returns pairs of [start timecode (HH:MM:SS), rationale] in chronological order.
+// Long transcripts are split with the same chunking as content generation, and suggestions
+// beyond the end of the transcript are dropped.
+func (s *AIService) GenerateAdTimecodes(ctx context.Context, segments []TranscriptSegment, opts GenerateOptions) ([][]string, error) {
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("ad timecodes need a timestamped transcript (SRT or VTT)")
+	}
+	s.logger.Info("Generating ad timecode suggestions...")
+
+	maxTokens := s.MaxTranscriptTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTranscriptTokens
+	}
+
+	duration := 0.0
+	var lines strings.Builder
+	for _, segment := range segments {
+		fmt.Fprintf(&lines, "[%s] %s\n", formatTimecode(segment.Start), strings.ReplaceAll(segment.Text, "\n", " "))
+		duration = math.Max(duration, segment.End)
+	}
+	chunks := splitTranscript(lines.String(), maxTokens)
+
+	var timecodes [][]string
+	last := -1.0
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			s.logger.Infof("Finding ad breaks in part %d/%d...", i+1, len(chunks))
+		}
+
+		req := openai.ChatCompletionRequest{
+			Model: openai.GPT4o,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: adTimecodesSystemPrompt,
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: fmt.Sprintf(adTimecodesPrompt, i+1, len(chunks), maxAdBreaksPerPart, chunk),
+				},
+			},
+			Temperature: 0.3,
+			MaxTokens:   adTimecodesMaxTokens,
+		}
+
+		resp, err := s.createChatCompletion(ctx, req)
+		if err != nil {
+			s.logger.Errorf("OpenAI API error: %v", err)
+			return nil, fmt.Errorf("failed to generate ad timecodes: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("empty response from OpenAI")
+		}
+		reportUsage(opts, req.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+
+		for _, suggestion := range parseAdTimecodes(resp.Choices[0].Message.Content) {
+			seconds, _ := timecodeSeconds(suggestion[0])
+			if seconds > duration {
+				s.logger.Warnf("Dropping ad timecode %s beyond the end of the transcript (%s)", suggestion[0], formatTimecode(duration))
+				continue
+			}
+			if seconds <= last {
+				s.logger.Debugf("Dropping out-of-order ad timecode %s", suggestion[0])
+				continue
+			}
+			last = seconds
+			timecodes = append(timecodes, []string{formatTimecode(seconds), suggestion[1]})
+		}
+	}
+
+	s.logger.Infof("Generated %d ad timecode suggestions", len(timecodes))
+	return timecodes, nil
+}
+
+// parseAdTimecodes extracts [timecode, rationale] pairs from the model response
+func parseAdTimecodes(response string) [][]string {
+	var suggestions [][]string
+	for _, line := range strings.Split(response, "\n") {
+		match := adTimecodeLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		if _, ok := timecodeSeconds(match[1]); !ok {
+			continue
+		}
+		suggestions = append(suggestions, []string{match[1], strings.TrimSpace(match[2])})
+	}
+	return suggestions
+}
+
+// timecodeSeconds parses an MM:SS or HH:MM:SS timecode into seconds
+func timecodeSeconds(timecode string) (float64, bool) {
+	seconds := 0
+	for _, field := range strings.Split(timecode, ":") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return float64(seconds), true
+}
+
+// formatTimecode formats seconds as HH:MM:SS
+func formatTimecode(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}
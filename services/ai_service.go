@@ -2,114 +2,234 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/sirupsen/logrus"
 )
 
+// OpenAIContentModel is the OpenAI model used for title and show note generation
+const OpenAIContentModel = openai.GPT4o
+
+// DefaultNumTitles is the number of title candidates requested when none is specified
+const DefaultNumTitles = 10
+
+// GenerateOptions holds options that control content generation
+type GenerateOptions struct {
+	NumTitles          int            // Number of distinct title candidates to request
+	Examples           []StyleExample // Few-shot examples of approved content, most recent first
+	ExampleTokenBudget int            // Maximum tokens spent on examples (default: DefaultExampleTokenBudget)
+	PromptTemplate     string         // text/template prompt rendered with PromptData (default: DefaultPromptTemplate)
+	EpisodeNumber      int            // Episode number passed to the prompt, 0 if unknown
+	Hosts              []string       // Host handles passed to the prompt
+	OnUsage            func(Usage)    // Called with the token usage of each API call
+	JSONMode           bool           // Request a JSON object response instead of [TITLE]/[SHOW NOTE] markers (OpenAI only)
+	StreamTo           io.Writer      // When set, the response is streamed and written here as it arrives (OpenAI only)
+	NoCache            bool           // Skip cached responses; the new response is still cached
+	Temperature        *float32       // Sampling temperature from 0.0 to 2.0 (default: DefaultTemperature)
+	MaxTokens          int            // Maximum number of tokens in the response (default: DefaultMaxResponseTokens)
+	Language           string         // ISO-639-1 code of the output language (default: DefaultLanguage)
+	Speakers           []string       // Speakers of a transcript formatted with FormatTurns, passed to the prompt
+	ToolCalling        bool           // Request the content as the arguments of the emit_content tool instead of text markers (OpenAI only)
+	TimestampedNotes   bool           // Ask for an (MM:SS) timecode after each show note bullet; the transcript must be formatted with FormatTimestampedTranscript
+}
+
+// DefaultTemperature is the sampling temperature used for content generation when none is specified
+const DefaultTemperature = 0.7
+
+// DefaultMaxResponseTokens is the maximum number of tokens requested for a generated response when none is specified
+const DefaultMaxResponseTokens = 8000
+
+// temperature returns the requested sampling temperature or the default
+func (o GenerateOptions) temperature() float32 {
+	if o.Temperature == nil {
+		return DefaultTemperature
+	}
+	return *o.Temperature
+}
+
+// language returns the requested output language code or the default
+func (o GenerateOptions) language() string {
+	if o.Language == "" {
+		return DefaultLanguage
+	}
+	return strings.ToLower(o.Language)
+}
+
+// maxTokens returns the requested response token limit or the default
+func (o GenerateOptions) maxTokens() int {
+	if o.MaxTokens <= 0 {
+		return DefaultMaxResponseTokens
+	}
+	return o.MaxTokens
+}
+
+// ValidateTemperature checks that a sampling temperature is within the range accepted by OpenAI
+func ValidateTemperature(temperature float64) error {
+	if temperature < 0 || temperature > 2 {
+		return fmt.Errorf("temperature must be between 0.0 and 2.0, got %g", temperature)
+	}
+	return nil
+}
+
+// DefaultMaxTranscriptTokens is the transcript size above which the transcript is chunked and summarized
+const DefaultMaxTranscriptTokens = 60000
+
 // AIService is a service responsible for AI-related processing
 type AIService struct {
 	openAIAPIKey string
 	client       *openai.Client
 	logger       *logrus.Logger
+
+	// MaxTranscriptTokens is the largest transcript (in estimated tokens) sent in a single prompt.
+	// Longer transcripts are split into chunks that are summarized first.
+	MaxTranscriptTokens int
+	// MaxRetries is the number of retries for rate-limit and 5xx errors from OpenAI
+	MaxRetries int
+	// CacheDir is the directory where responses are cached by transcript and prompt inputs; empty disables caching
+	CacheDir string
+	// RateLimitDelay is the minimum pause between consecutive OpenAI calls, such as chunk summaries; 0 disables it
+	RateLimitDelay time.Duration
+	// Limiter caps the rate of calls shared with other services, e.g. in a batch; nil for no cap
+	Limiter *RequestLimiter
+
+	lastRequestAt time.Time
 }
 
 // NewAIService creates a new AIService instance
 func NewAIService(openAIAPIKey string, logger *logrus.Logger) *AIService {
 	// Initialize OpenAI client
-	client := openai.NewClient(openAIAPIKey)
+	client := openai.NewClientWithConfig(OpenAIClientConfig(openAIAPIKey, "", ""))
 
 	return &AIService{
-		openAIAPIKey: openAIAPIKey,
-		client:       client,
-		logger:       logger,
+		openAIAPIKey:        openAIAPIKey,
+		client:              client,
+		logger:              logger,
+		MaxTranscriptTokens: DefaultMaxTranscriptTokens,
+		MaxRetries:          DefaultMaxRetries,
 	}
 }
 
-// GenerateAllContent generates both title and show note in a single API call
-func (s *AIService) GenerateAllContent(ctx context.Context, transcript string) ([]string, []string, error) {
+// SetBaseURL sends requests to an OpenAI-compatible endpoint or an Azure OpenAI resource
+// instead of the public OpenAI API; see OpenAIClientConfig. An empty base URL keeps the public API.
+func (s *AIService) SetBaseURL(baseURL, azureDeployment string) {
+	s.client = openai.NewClientWithConfig(OpenAIClientConfig(s.openAIAPIKey, baseURL, azureDeployment))
+}
+
+// GenerateAllContent generates title candidates and a show note in a single API call
+func (s *AIService) GenerateAllContent(ctx context.Context, transcript string, opts GenerateOptions) ([]string, []string, error) {
 	s.logger.Info("Generating all content in a single API call...")
 
-	// Use the full transcript
-	fullTranscript := transcript
+	numTitles := opts.NumTitles
+	if numTitles <= 0 {
+		numTitles = DefaultNumTitles
+	}
+
+	// Reuse a cached response for the same transcript and prompt inputs
+	cacheKey := contentCacheKey(transcript, OpenAIContentModel, numTitles, opts)
+	if s.CacheDir != "" && !opts.NoCache {
+		if cached, ok := s.loadCachedResponse(cacheKey); ok {
+			s.logger.Infof("Cache hit: reusing the response from %s (use --no-cache to regenerate)", cached.CreatedAt.Local().Format(time.RFC3339))
+			if opts.StreamTo != nil {
+				fmt.Fprintln(opts.StreamTo, cached.Response)
+			}
+			titles, showNotes := s.parseResponse(cached.Response, cached.JSONMode, numTitles)
+			return titles, showNotes, nil
+		}
+		s.logger.Info("Cache miss: calling OpenAI")
+	}
+
+	// Use the full transcript, or chunk summaries if it is too long for a single prompt
+	fullTranscript, err := s.prepareTranscript(ctx, transcript, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Create a combined prompt that requests both title and show note
-	prompt := fmt.Sprintf(
-		"You are GenerativeAI acting as a podcast copy‑writer for a Japanese podcast about parenting and technology.\n\nPlease generate the following content for this podcast episode:\n\n1. TITLE: Follow this pattern exactly:\n   NN. ＜Japanese topic 1＞ / ＜Japanese topic 2＞ [/ ＜Japanese topic 3＞]\n   * NN = episode number (integer)\n   * Provide 2 or 3 topics\n   * Topics should be mainly in Japanese, but keep any necessary English words as‑is (AI, GPT, etc.)\n\n2. SHOW NOTE: Create exactly this format:\n   * Opening summary: 2-3 lines in friendly Japanese with relevant emojis. Each sentence MUST end with an exclamation mark (!)\n   * Bullet points: 8-12 points, each formatted as: [emoji] [Bold headline in Japanese]: [Short description, maximum 1 line]\n   * CTA block: Wrapped in dotted lines (\"………\"), asking for feedback via hashtag #momitfm\n   * Credits section: Must be titled exactly \"✨🎧 Credits\" and list hosts (@_yukamiya & @m2vela) and intro creator (@kirillovlov2983)\n\nHere is the transcript of the podcast:\n%s\n\nFormat your response with clear section headers [TITLE] and [SHOW NOTE] to separate the content.",
-		fullTranscript,
-	)
+	prompt, err := buildContentPrompt(fullTranscript, numTitles, opts, s.logger)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Create the OpenAI API request
-	req := openai.ChatCompletionRequest{
-		Model: openai.GPT4o,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: "You are GenerativeAI acting as a podcast copy‑writer for a Japanese podcast about parenting and technology. Follow the formatting instructions EXACTLY.",
-			},
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: prompt,
-			},
-		},
-		Temperature: 0.7,
-		MaxTokens:   8000,
-	}
+	req := newContentRequest(prompt, opts)
 
 	// Make the API call
-	resp, err := s.client.CreateChatCompletion(ctx, req)
+	resp, err := s.completeContent(ctx, req, opts)
+	if err != nil && opts.ToolCalling && isUnsupportedToolsError(err) {
+		s.logger.Warnf("Model %s does not support tool calling (%v), falling back to the text response", req.Model, err)
+		textOpts := opts
+		textOpts.ToolCalling = false
+		req = newContentRequest(prompt, textOpts)
+		resp, err = s.completeContent(ctx, req, opts)
+	}
+	if err != nil && opts.JSONMode && req.ResponseFormat != nil && isUnsupportedJSONModeError(err) {
+		s.logger.Warnf("Model %s does not support JSON mode (%v), falling back to text markers", req.Model, err)
+		textOpts := opts
+		textOpts.JSONMode = false
+		req = newContentRequest(prompt, textOpts)
+		resp, err = s.completeContent(ctx, req, opts)
+	}
 	if err != nil {
 		s.logger.Errorf("OpenAI API error: %v", err)
 		return nil, nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
-	// Parse the response
-	responseText := resp.Choices[0].Message.Content
-
-	// Split the response into title and show note sections
-	titleSection := ""
-	showNoteSection := ""
-
-	// Extract title section
-	titleStart := strings.Index(responseText, "[TITLE]")
-	showNoteStart := strings.Index(responseText, "[SHOW NOTE]")
-
-	if titleStart >= 0 && showNoteStart > titleStart {
-		titleSection = strings.TrimSpace(responseText[titleStart+len("[TITLE]") : showNoteStart])
-	}
-
-	// Extract show note section
-	if showNoteStart >= 0 {
-		showNoteSection = strings.TrimSpace(responseText[showNoteStart+len("[SHOW NOTE]"):])
-	}
+	reportUsage(opts, req.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
 
-	// If we couldn't find the sections, try to parse the whole response
-	if titleSection == "" && showNoteStart < 0 {
-		// Try to extract the first line as title
-		lines := strings.Split(responseText, "\n")
-		if len(lines) > 0 {
-			titleSection = lines[0]
-			showNoteSection = strings.Join(lines[1:], "\n")
+	// Parse the response; the arguments of the emit_content call are parsed like a JSON mode response
+	responseText := resp.Choices[0].Message.Content
+	jsonMode := req.ResponseFormat != nil
+	if len(req.Tools) > 0 {
+		if arguments, ok := emitContentArguments(resp.Choices[0].Message); ok {
+			responseText = arguments
+			jsonMode = true
+		} else {
+			s.logger.Warnf("Model %s answered without calling %s, parsing the text response", req.Model, emitContentToolName)
 		}
 	}
+	if s.CacheDir != "" {
+		s.saveCachedResponse(cacheKey, &cachedResponse{
+			Model:     req.Model,
+			JSONMode:  jsonMode,
+			Response:  responseText,
+			CreatedAt: time.Now(),
+		})
+	}
 
-	// Return the results
-	titles := []string{titleSection}
-	showNotes := []string{showNoteSection}
+	// Split the response into title candidates and show notes
+	titles, showNotes := s.parseResponse(responseText, jsonMode, numTitles)
 
 	s.logger.Info("Generated content successfully")
 	return titles, showNotes, nil
 }
 
+// parseResponse splits a response into title candidates and show notes, falling back
+// to the text markers when a JSON mode response isn't valid JSON
+func (s *AIService) parseResponse(responseText string, jsonMode bool, numTitles int) ([]string, []string) {
+	if jsonMode {
+		titles, showNotes, err := parseJSONContentResponse(responseText, numTitles, s.logger)
+		if err == nil {
+			return titles, showNotes
+		}
+		s.logger.Warnf("Invalid JSON response (%v), falling back to text markers", err)
+	}
+	return parseContentResponse(responseText, numTitles, s.logger)
+}
+
 // GenerateTitles generates title candidates from a transcript
 // This is kept for backward compatibility, but now uses GenerateAllContent internally
-func (s *AIService) GenerateTitles(ctx context.Context, transcript string) ([]string, error) {
+func (s *AIService) GenerateTitles(ctx context.Context, transcript string, opts GenerateOptions) ([]string, error) {
 	s.logger.Info("Generating title candidates using combined API call...")
 
 	// Use the combined function but only return titles
-	titles, _, err := s.GenerateAllContent(ctx, transcript)
+	titles, _, err := s.GenerateAllContent(ctx, transcript, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate titles: %w", err)
 	}
@@ -120,11 +240,11 @@ func (s *AIService) GenerateTitles(ctx context.Context, transcript string) ([]st
 
 // GenerateShowNotes generates show note candidates from a transcript
 // This is kept for backward compatibility, but now uses GenerateAllContent internally
-func (s *AIService) GenerateShowNotes(ctx context.Context, transcript string) ([]string, error) {
+func (s *AIService) GenerateShowNotes(ctx context.Context, transcript string, opts GenerateOptions) ([]string, error) {
 	s.logger.Info("Generating show note candidates using combined API call...")
 
 	// Use the combined function but only return show notes
-	_, showNotes, err := s.GenerateAllContent(ctx, transcript)
+	_, showNotes, err := s.GenerateAllContent(ctx, transcript, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate show notes: %w", err)
 	}
@@ -133,9 +253,65 @@ func (s *AIService) GenerateShowNotes(ctx context.Context, transcript string) ([
 	return showNotes, nil
 }
 
-// GenerateAdTimecodes generates ad timecode candidates from a transcript
-// This function is no longer used but kept for backward compatibility
-func (s *AIService) GenerateAdTimecodes(ctx context.Context, transcript string) ([][]string, error) {
-	s.logger.Info("Ad timecode generation is deprecated")
-	return [][]string{}, nil
+// newContentRequest creates the chat completion request for title and show note generation.
+// With tool calling the model must call emit_content, whose arguments have a strict schema.
+// In JSON mode the response format is set to a JSON object and the prompt asks for that shape.
+func newContentRequest(prompt string, opts GenerateOptions) openai.ChatCompletionRequest {
+	// The API omits a zero temperature and would use its own default, so send the smallest non-zero value instead
+	temperature := opts.temperature()
+	if temperature == 0 {
+		temperature = math.SmallestNonzeroFloat32
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: OpenAIContentModel,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: contentSystemPrompt(languageNames[opts.language()]),
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		Temperature: temperature,
+		MaxTokens:   opts.maxTokens(),
+	}
+
+	switch {
+	case opts.ToolCalling:
+		req.Messages[1].Content = prompt + "\n\n" + toolCallingInstruction
+		req.Tools = []openai.Tool{emitContentTool()}
+		req.ToolChoice = openai.ToolChoice{
+			Type:     openai.ToolTypeFunction,
+			Function: openai.ToolFunction{Name: emitContentToolName},
+		}
+	case opts.JSONMode:
+		req.Messages[1].Content = prompt + "\n\n" + jsonModeInstruction
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+	return req
+}
+
+// isUnsupportedJSONModeError reports whether OpenAI rejected the request because the model
+// doesn't support the JSON response format
+func isUnsupportedJSONModeError(err error) bool {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(apiErr.Message, "response_format") || (apiErr.Param != nil && *apiErr.Param == "response_format")
+}
+
+// VerifyCredentials checks the OpenAI API key with a models list call, which costs no tokens
+func (s *AIService) VerifyCredentials(ctx context.Context) error {
+	models, err := s.client.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list OpenAI models: %w", err)
+	}
+	s.logger.Debugf("OpenAI API key can access %d models", len(models.Models))
+	return nil
 }
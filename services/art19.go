@@ -4,59 +4,178 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// DefaultMCPTimeout is the default timeout for Playwright MCP calls, which drive a real browser
+const DefaultMCPTimeout = 120 * time.Second
+
 // Art19Service handles interactions with the Art19 platform
 type Art19Service struct {
 	username string
 	password string
+	client   *http.Client
 	logger   *logrus.Logger
+
+	// DryRun prints the MCP payloads instead of sending them
+	DryRun bool
+}
+
+// mcpServerURL is the endpoint of the Playwright MCP server that runs browser automation scripts
+const mcpServerURL = "http://localhost:3001/run-script" // 例: MCPサーバーは3001番
+
+// mcpResult holds the fields a Playwright script may report back through the MCP server
+type mcpResult struct {
+	EpisodeID  string `json:"episodeID"`
+	EpisodeURL string `json:"episodeURL"`
+	Status     string `json:"status"`
+	Title      string `json:"title"`
+	ShowNote   string `json:"showNote"`
+	Output     string `json:"output"`
+}
+
+// Art19 episode statuses reported by the publish script
+const (
+	Art19StatusDraft     = "draft"
+	Art19StatusPublished = "published"
+)
+
+// Art19Episode identifies an episode created on Art19
+type Art19Episode struct {
+	ID  string
+	URL string
+}
+
+// UploadDraftTitle uploads only the title to Art19 as a draft and returns the created episode
+func (s *Art19Service) UploadDraftTitle(ctx context.Context, title string) (*Art19Episode, error) {
+	return s.UploadDraftTitleAndShowNote(ctx, title, "")
 }
 
-// UploadDraftTitle uploads only the title to Art19 as a draft (placeholder implementation)
-func (s *Art19Service) UploadDraftTitle(ctx context.Context, title string) error {
+// UploadDraftTitleAndShowNote uploads the title and show note to Art19 as a draft and returns
+// the created episode. The show note is skipped when empty.
+func (s *Art19Service) UploadDraftTitleAndShowNote(ctx context.Context, title string, showNote string) (*Art19Episode, error) {
 	s.logger.Infof("Uploading draft title to Art19: %s", title)
 
 	// 必要なURL等は設定や引数で受け取る想定
 	art19EpisodeNewURL := os.Getenv("ART19_EPISODE_NEW_URL")
 	if art19EpisodeNewURL == "" {
-		return fmt.Errorf("ART19_EPISODE_NEW_URL is not set")
+		return nil, fmt.Errorf("ART19_EPISODE_NEW_URL is not set")
+	}
+
+	env := map[string]string{
+		"ART19_USERNAME":        s.username,
+		"ART19_PASSWORD":        s.password,
+		"ART19_EPISODE_NEW_URL": art19EpisodeNewURL,
+		"EPISODE_TITLE":         title,
+	}
+	if showNote != "" {
+		env["EPISODE_SHOWNOTE"] = FormatShowNoteHTML(showNote)
 	}
 
 	// Playwright MCPサーバーにPOST
+	result, err := s.runMCPScript(ctx, "scripts/art19_upload_title.js", env)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Draft upload requested via Playwright MCP server")
+	return &Art19Episode{ID: result.EpisodeID, URL: result.EpisodeURL}, nil
+}
+
+// runMCPScript asks the Playwright MCP server to run a script with the given environment
+// and returns the parsed result
+func (s *Art19Service) runMCPScript(ctx context.Context, script string, env map[string]string) (*mcpResult, error) {
 	payload := map[string]interface{}{
-		"script": "scripts/art19_upload_title.js",
-		"env": map[string]string{
-			"ART19_USERNAME": s.username,
-			"ART19_PASSWORD": s.password,
-			"ART19_EPISODE_NEW_URL": art19EpisodeNewURL,
-			"EPISODE_TITLE":  title,
-		},
+		"script": script,
+		"env":    env,
+	}
+
+	if s.DryRun {
+		return s.printMCPPayload(script, env)
 	}
 	data, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal Playwright payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal Playwright payload: %w", err)
 	}
 
-	resp, err := http.Post("http://localhost:3001/run-script", "application/json", bytes.NewBuffer(data)) // 例: MCPサーバーは3001番
+	req, err := http.NewRequestWithContext(ctx, "POST", mcpServerURL, bytes.NewBuffer(data))
 	if err != nil {
-		return fmt.Errorf("failed to call Playwright MCP server: %w", err)
+		return nil, fmt.Errorf("failed to create Playwright MCP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// A cancelled run is not a server problem
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, err
+		}
+		return nil, newMCPError(script, mcpServerURL, s.client.Timeout, err)
 	}
 	defer resp.Body.Close()
 
+	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Playwright MCP server error: %s", string(body))
+		return nil, &MCPError{Script: script, ServerURL: mcpServerURL, StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	s.logger.Info("Draft title upload requested via Playwright MCP server")
-	return nil
+	return parseMCPResult(body), nil
+}
+
+// printMCPPayload prints the payload that would be sent to the MCP server, with the password masked
+func (s *Art19Service) printMCPPayload(script string, env map[string]string) (*mcpResult, error) {
+	masked := make(map[string]string, len(env))
+	for key, value := range env {
+		if key == "ART19_PASSWORD" && value != "" {
+			value = "********"
+		}
+		masked[key] = value
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(map[string]interface{}{
+		"script": script,
+		"env":    masked,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to marshal Playwright payload: %w", err)
+	}
+
+	s.logger.Infof("Dry run: would POST to %s:", mcpServerURL)
+	fmt.Print(buf.String())
+	return &mcpResult{}, nil
+}
+
+// parseMCPResult extracts the script result from an MCP response body.
+// The fields may be at the top level or inside a JSON document printed by the script.
+func parseMCPResult(body []byte) *mcpResult {
+	result := &mcpResult{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return result
+	}
+
+	if result.EpisodeID == "" && result.EpisodeURL == "" && result.Status == "" && result.Output != "" {
+		// Scripts print their result as the last line of output
+		lines := strings.Split(strings.TrimSpace(result.Output), "\n")
+		var output mcpResult
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &output); err == nil {
+			output.Output = result.Output
+			return &output
+		}
+	}
+	return result
 }
 
 // NewArt19Service creates a new Art19Service instance
@@ -64,60 +183,230 @@ func NewArt19Service(username, password string, logger *logrus.Logger) *Art19Ser
 	return &Art19Service{
 		username: username,
 		password: password,
+		client:   NewHTTPClient(slowRequestTimeout(DefaultMCPTimeout)),
 		logger:   logger,
 	}
 }
 
-// PublishEpisode handles the process of publishing an episode to Art19
-func (s *Art19Service) PublishEpisode(ctx context.Context, audioPath string, title string, description string) error {
+// SetTimeout sets the timeout for Playwright MCP calls
+func (s *Art19Service) SetTimeout(timeout time.Duration) {
+	s.client.Timeout = timeout
+}
+
+// PublishEpisode creates an Art19 draft episode with the audio file, title and description
+// and returns the created episode
+func (s *Art19Service) PublishEpisode(ctx context.Context, audioPath string, title string, description string) (*Art19Episode, error) {
 	s.logger.Infof("Starting Art19 publishing process for: %s", title)
 
-	// TODO: Implement Art19 publishing logic
-	// Since Art19 doesn't have a public write API, we'll need to:
-	// 1. Use a headless browser (like Playwright or Puppeteer)
-	// 2. Log in to Art19
-	// 3. Navigate to the episode creation page
-	// 4. Upload the audio file
-	// 5. Fill in the episode details
-	// 6. Set ad markers
-	// 7. Publish the episode
+	result, err := s.uploadAudio(ctx, audioPath, map[string]string{
+		"EPISODE_TITLE":    title,
+		"EPISODE_SHOWNOTE": FormatShowNoteHTML(description),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	// For now, this is a placeholder implementation
-	s.logger.Warn("Art19 publishing not yet implemented")
-	return fmt.Errorf("Art19 publishing not yet implemented")
+	// Ad markers and publishing are separate steps; the episode is left as a draft
+	if !s.DryRun {
+		s.logger.Infof("Created Art19 draft episode %s", result.EpisodeID)
+	}
+	return &Art19Episode{ID: result.EpisodeID, URL: result.EpisodeURL}, nil
 }
 
-// UploadAudio handles the audio file upload to Art19
+// UploadAudio uploads an audio file to a new Art19 episode via the Playwright MCP server
+// and returns the created episode ID
 func (s *Art19Service) UploadAudio(ctx context.Context, audioPath string) (string, error) {
+	result, err := s.uploadAudio(ctx, audioPath, nil)
+	if err != nil {
+		return "", err
+	}
+	return result.EpisodeID, nil
+}
+
+// uploadAudio runs the audio upload script with optional extra environment variables
+func (s *Art19Service) uploadAudio(ctx context.Context, audioPath string, extraEnv map[string]string) (*mcpResult, error) {
 	s.logger.Infof("Uploading audio file: %s", audioPath)
 
-	// Open the audio file
-	file, err := os.Open(audioPath)
+	// Verify the audio file exists and pass an absolute path to the script
+	absPath, err := filepath.Abs(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve audio file path: %w", err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
+	}
+
+	art19EpisodeNewURL := os.Getenv("ART19_EPISODE_NEW_URL")
+	if art19EpisodeNewURL == "" {
+		return nil, fmt.Errorf("ART19_EPISODE_NEW_URL is not set")
+	}
+
+	env := map[string]string{
+		"ART19_USERNAME":        s.username,
+		"ART19_PASSWORD":        s.password,
+		"ART19_EPISODE_NEW_URL": art19EpisodeNewURL,
+		"ART19_AUDIO_PATH":      absPath,
+	}
+	for key, value := range extraEnv {
+		env[key] = value
+	}
+
+	result, err := s.runMCPScript(ctx, "scripts/art19_upload_audio.js", env)
 	if err != nil {
-		return "", fmt.Errorf("failed to open audio file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
+	if result.EpisodeID == "" && !s.DryRun {
+		return nil, fmt.Errorf("Playwright MCP server response did not include an episode ID")
+	}
+
+	if s.DryRun {
+		return result, nil
+	}
+
+	s.logger.Infof("Audio uploaded to Art19 episode %s", result.EpisodeID)
+	return result, nil
+}
+
+// Art19EpisodeContent is the title and show note of an Art19 episode as shown in its editor
+type Art19EpisodeContent struct {
+	Title    string
+	ShowNote string // HTML of the description editor
+}
+
+// ReadEpisode reads back the title and show note of an Art19 episode via the Playwright MCP server
+func (s *Art19Service) ReadEpisode(ctx context.Context, episodeID string) (*Art19EpisodeContent, error) {
+	if episodeID == "" {
+		return nil, fmt.Errorf("episode ID is required to read an episode")
+	}
+
+	result, err := s.runMCPScript(ctx, "scripts/art19_read_episode.js", s.episodeEnv(episodeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Art19 episode: %w", err)
+	}
+	return &Art19EpisodeContent{Title: result.Title, ShowNote: result.ShowNote}, nil
+}
+
+// EpisodeStatus returns the status of an Art19 episode, Art19StatusDraft or Art19StatusPublished,
+// via the Playwright MCP server without changing it
+func (s *Art19Service) EpisodeStatus(ctx context.Context, episodeID string) (string, error) {
+	if episodeID == "" {
+		return "", fmt.Errorf("episode ID is required to check its status")
+	}
+
+	env := s.episodeEnv(episodeID)
+	env["ART19_PUBLISH_CHECK_ONLY"] = "1"
+	result, err := s.runMCPScript(ctx, "scripts/art19_publish_episode.js", env)
+	if err != nil {
+		return "", fmt.Errorf("failed to check Art19 episode status: %w", err)
+	}
+	return result.Status, nil
+}
+
+// PublishDraft publishes an Art19 draft episode via the Playwright MCP server and returns it.
+// The script refuses to publish an episode that isn't a draft.
+func (s *Art19Service) PublishDraft(ctx context.Context, episodeID string) (*Art19Episode, error) {
+	s.logger.Infof("Publishing Art19 episode: %s", episodeID)
+
+	if episodeID == "" && !s.DryRun {
+		return nil, fmt.Errorf("episode ID is required to publish an episode")
+	}
+
+	result, err := s.runMCPScript(ctx, "scripts/art19_publish_episode.js", s.episodeEnv(episodeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish Art19 episode: %w", err)
+	}
+	if s.DryRun {
+		return &Art19Episode{ID: episodeID}, nil
+	}
+	if result.Status != Art19StatusPublished {
+		return nil, fmt.Errorf("Art19 episode %s was not published (status %q)", episodeID, result.Status)
+	}
+
+	s.logger.Infof("Published Art19 episode %s", episodeID)
+	return &Art19Episode{ID: episodeID, URL: result.EpisodeURL}, nil
+}
 
-	// TODO: Implement actual Art19 upload logic
-	// This would involve:
-	// 1. Getting an upload URL from Art19
-	// 2. Uploading the file
-	// 3. Getting the file ID
+// episodeEnv returns the environment of the scripts that open an existing episode
+func (s *Art19Service) episodeEnv(episodeID string) map[string]string {
+	return map[string]string{
+		"ART19_USERNAME":   s.username,
+		"ART19_PASSWORD":   s.password,
+		"ART19_EPISODE_ID": episodeID,
+	}
+}
 
-	s.logger.Warn("Art19 audio upload not yet implemented")
-	return "", fmt.Errorf("Art19 audio upload not yet implemented")
+// ValidateAdMarkers checks that ad marker timestamps (in seconds) are non-negative, sorted
+// in ascending order and within the episode duration. A zero duration skips the upper bound check.
+func ValidateAdMarkers(markers []float64, duration float64) error {
+	for i, marker := range markers {
+		if marker < 0 {
+			return fmt.Errorf("ad marker %d (%gs) is negative", i+1, marker)
+		}
+		if i > 0 && marker <= markers[i-1] {
+			return fmt.Errorf("ad markers must be in ascending order, but marker %d (%gs) is not after %gs", i+1, marker, markers[i-1])
+		}
+		if duration > 0 && marker > duration {
+			return fmt.Errorf("ad marker %d (%gs) is beyond the episode duration of %gs", i+1, marker, duration)
+		}
+	}
+	return nil
 }
 
-// SetAdMarkers sets the ad insertion points for an episode
-func (s *Art19Service) SetAdMarkers(ctx context.Context, episodeID string, markers []float64) error {
+// SetAdMarkers sets the ad insertion points (in seconds) for an episode via the Playwright MCP server.
+// The markers are validated against the episode duration first; a zero duration skips that check.
+func (s *Art19Service) SetAdMarkers(ctx context.Context, episodeID string, markers []float64, duration float64) error {
 	s.logger.Infof("Setting ad markers for episode: %s", episodeID)
 
-	// TODO: Implement ad marker setting logic
-	// This would involve:
-	// 1. Navigating to the episode's ad settings
-	// 2. Adding markers at the specified timestamps
-	// 3. Saving the changes
+	if episodeID == "" && !s.DryRun {
+		return fmt.Errorf("episode ID is required to set ad markers")
+	}
+	if len(markers) == 0 {
+		return fmt.Errorf("no ad markers given")
+	}
+	if err := ValidateAdMarkers(markers, duration); err != nil {
+		return err
+	}
+
+	markersJSON, err := json.Marshal(markers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ad markers: %w", err)
+	}
+
+	env := map[string]string{
+		"ART19_USERNAME":   s.username,
+		"ART19_PASSWORD":   s.password,
+		"ART19_EPISODE_ID": episodeID,
+		"AD_MARKERS":       string(markersJSON),
+	}
+
+	if _, err := s.runMCPScript(ctx, "scripts/art19_set_ad_markers.js", env); err != nil {
+		return fmt.Errorf("failed to set ad markers: %w", err)
+	}
+
+	if !s.DryRun {
+		s.logger.Infof("Set %d ad markers on Art19 episode %s", len(markers), episodeID)
+	}
+	return nil
+}
+
+// Ping checks that the Playwright MCP server is reachable. Any HTTP response counts,
+// since the server only serves the run-script endpoint.
+func (s *Art19Service) Ping(ctx context.Context) error {
+	serverURL, err := url.Parse(mcpServerURL)
+	if err != nil {
+		return fmt.Errorf("invalid Playwright MCP server URL: %w", err)
+	}
+	serverURL.Path = "/"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", serverURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Playwright MCP request: %w", err)
+	}
 
-	s.logger.Warn("Art19 ad marker setting not yet implemented")
-	return fmt.Errorf("Art19 ad marker setting not yet implemented")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return newMCPError("", mcpServerURL, s.client.Timeout, err)
+	}
+	resp.Body.Close()
+	return nil
 }
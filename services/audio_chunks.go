@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WhisperMaxFileSize is the largest audio file accepted by the Whisper API
+const WhisperMaxFileSize = 25 << 20
+
+// DefaultChunkSize is the default size of the audio chunks sent to Whisper for larger files
+const DefaultChunkSize = 24 << 20
+
+// chunkBitrate is the bitrate, in bits per second, of the mono MP3 chunks re-encoded with ffmpeg
+const chunkBitrate = 64000
+
+// chunkSize returns the configured chunk size, capped at the Whisper limit
+func (s *TranscriptionService) chunkSize() int64 {
+	if s.ChunkSize <= 0 || s.ChunkSize > WhisperMaxFileSize {
+		return DefaultChunkSize
+	}
+	return s.ChunkSize
+}
+
+// transcribeChunked splits an audio file that is too large for Whisper into chunks, transcribes
+// them in order, and joins the results, shifting each chunk's segments by the audio before it
+func (s *TranscriptionService) transcribeChunked(ctx context.Context, audioPath string, size int64, opts TranscribeOptions) (*TranscriptionResult, error) {
+	// The chunks are removed even if transcription fails or is cancelled
+	tmpDir, err := NewTempDir(ctx, "chunks", s.logger)
+	if err != nil {
+		return nil, err
+	}
+	defer tmpDir.Cleanup()
+
+	chunks, err := s.splitAudio(ctx, audioPath, size, tmpDir.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TranscriptionResult{}
+	var texts []string
+	for i, chunk := range chunks {
+		s.logger.Infof("Transcribing chunk %d/%d (from %s)...", i+1, len(chunks), formatTimecode(result.Duration))
+
+		// Always ask for segments; the chunk duration gives the offset of the next chunk
+		body, err := s.requestTranscription(ctx, chunk, opts, "verbose_json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to transcribe chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		var part TranscriptionResult
+		if err := json.Unmarshal(body, &part); err != nil {
+			return nil, fmt.Errorf("failed to parse response for chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		if err := tmpDir.Remove(chunk); err != nil {
+			s.logger.Debugf("Failed to remove chunk %d/%d: %v", i+1, len(chunks), err)
+		}
+
+		for _, segment := range part.Segments {
+			segment.Start += result.Duration
+			segment.End += result.Duration
+			result.Segments = append(result.Segments, segment)
+		}
+		if text := strings.TrimSpace(part.Text); text != "" {
+			texts = append(texts, text)
+		}
+		if result.Language == "" {
+			result.Language = part.Language
+		}
+		result.Duration += part.Duration
+	}
+
+	result.Text = strings.Join(texts, "\n")
+	return result, nil
+}
+
+// splitAudio splits an audio file into chunks below the chunk size and returns their paths in order.
+// It re-encodes with ffmpeg when available and otherwise splits MP3 files on frame boundaries.
+func (s *TranscriptionService) splitAudio(ctx context.Context, audioPath string, size int64, dir string) ([]string, error) {
+	chunkSize := s.chunkSize()
+
+	if _, err := exec.LookPath("ffmpeg"); err == nil {
+		return splitAudioFFmpeg(ctx, audioPath, chunkSize, dir)
+	}
+
+	if strings.EqualFold(filepath.Ext(audioPath), ".mp3") {
+		s.logger.Info("ffmpeg not found, splitting the MP3 file on frame boundaries")
+		return splitMP3(audioPath, chunkSize, dir)
+	}
+
+	return nil, fmt.Errorf("audio file is %.1f MB, over Whisper's %d MB limit, and only MP3 files can be split without ffmpeg; install ffmpeg or convert the file to MP3",
+		float64(size)/(1<<20), WhisperMaxFileSize>>20)
+}
+
+// splitAudioFFmpeg re-encodes the audio as mono MP3 segments short enough to stay below chunkSize
+func splitAudioFFmpeg(ctx context.Context, audioPath string, chunkSize int64, dir string) ([]string, error) {
+	// Leave 5% headroom for container overhead and bitrate variation
+	segmentSeconds := int(float64(chunkSize) * 8 / chunkBitrate * 0.95)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error",
+		"-i", audioPath,
+		"-vn", "-ac", "1", "-b:a", strconv.Itoa(chunkBitrate),
+		"-f", "segment", "-segment_time", strconv.Itoa(segmentSeconds), "-reset_timestamps", "1",
+		filepath.Join(dir, "chunk%03d.mp3"),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed to split the audio: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	chunks, err := filepath.Glob(filepath.Join(dir, "chunk*.mp3"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audio chunks: %w", err)
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("ffmpeg produced no audio chunks")
+	}
+	sort.Strings(chunks)
+	return chunks, nil
+}
+
+// splitMP3 splits an MP3 file into pieces of at most chunkSize bytes, cutting at frame sync words
+// so that each piece decodes on its own
+func splitMP3(audioPath string, chunkSize int64, dir string) ([]string, error) {
+	data, err := os.ReadFile(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio file: %w", err)
+	}
+
+	var chunks []string
+	for start := 0; start < len(data); {
+		end := len(data)
+		if int64(end-start) > chunkSize {
+			end = lastMP3FrameStart(data, start+1, start+int(chunkSize))
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("chunk%03d.mp3", len(chunks)))
+		if err := os.WriteFile(path, data[start:end], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write audio chunk: %w", err)
+		}
+		chunks = append(chunks, path)
+		start = end
+	}
+	return chunks, nil
+}
+
+// lastMP3FrameStart returns the position of the last frame sync word in data[from:to],
+// or to if there is none
+func lastMP3FrameStart(data []byte, from, to int) int {
+	for i := to - 1; i > from; i-- {
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1]&0xE0 == 0xE0 {
+			return i
+		}
+	}
+	return to
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultBlueskyPDS is the PDS used when none is configured
+const DefaultBlueskyPDS = "https://bsky.social"
+
+// BlueskyMaxLength is the maximum length of a Bluesky post in characters
+const BlueskyMaxLength = 300
+
+// blueskyMaxBlobSize is the largest image Bluesky accepts in a post
+const blueskyMaxBlobSize = 1000000
+
+// BlueskyLimit is the post length limit of Bluesky. Unlike Twitter/X and Mastodon, URLs count in full.
+var BlueskyLimit = SNSLimit{MaxLength: BlueskyMaxLength, Length: utf8.RuneCountInString}
+
+// BlueskyService handles posting to Bluesky via the AT Protocol
+type BlueskyService struct {
+	pdsURL string
+	client *http.Client
+	logger *logrus.Logger
+}
+
+// NewBlueskyService creates a new BlueskyService instance
+func NewBlueskyService(pdsURL string, logger *logrus.Logger) *BlueskyService {
+	if pdsURL == "" {
+		pdsURL = DefaultBlueskyPDS
+	}
+
+	return &BlueskyService{
+		pdsURL: strings.TrimRight(pdsURL, "/"),
+		client: NewHTTPClient(0),
+		logger: logger,
+	}
+}
+
+// blueskySession is an authenticated AT Protocol session
+type blueskySession struct {
+	AccessJwt string `json:"accessJwt"`
+	Handle    string `json:"handle"`
+	DID       string `json:"did"`
+}
+
+// blueskyFacet annotates a byte range of a post's text, e.g. to make a URL clickable
+type blueskyFacet struct {
+	Index struct {
+		ByteStart int `json:"byteStart"`
+		ByteEnd   int `json:"byteEnd"`
+	} `json:"index"`
+	Features []map[string]string `json:"features"`
+}
+
+// PostText publishes a post with clickable links, and the image attached if it is not nil, and returns its web URL
+func (s *BlueskyService) PostText(ctx context.Context, identifier, appPassword, text string, image *ShareImage) (string, error) {
+	if identifier == "" || appPassword == "" {
+		return "", fmt.Errorf("Bluesky identifier and app password are required")
+	}
+
+	session, err := s.createSession(ctx, identifier, appPassword)
+	if err != nil {
+		return "", err
+	}
+
+	record := map[string]interface{}{
+		"$type":     "app.bsky.feed.post",
+		"text":      text,
+		"createdAt": time.Now().UTC().Format(time.RFC3339),
+	}
+	if facets := linkFacets(text); len(facets) > 0 {
+		record["facets"] = facets
+	}
+	if image != nil {
+		blob, err := s.uploadBlob(ctx, session.AccessJwt, image)
+		if err != nil {
+			return "", err
+		}
+		record["embed"] = map[string]interface{}{
+			"$type": "app.bsky.embed.images",
+			"images": []map[string]interface{}{{
+				"alt":   image.AltText,
+				"image": blob,
+			}},
+		}
+	}
+
+	var result struct {
+		URI string `json:"uri"`
+		CID string `json:"cid"`
+	}
+	err = s.callXRPC(ctx, "com.atproto.repo.createRecord", session.AccessJwt, map[string]interface{}{
+		"repo":       session.DID,
+		"collection": "app.bsky.feed.post",
+		"record":     record,
+	}, &result)
+	if err != nil {
+		return "", fmt.Errorf("failed to create post: %w", err)
+	}
+
+	// at://<did>/app.bsky.feed.post/<rkey> -> https://bsky.app/profile/<handle>/post/<rkey>
+	return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", session.Handle, path.Base(result.URI)), nil
+}
+
+// createSession authenticates with an app password
+func (s *BlueskyService) createSession(ctx context.Context, identifier, appPassword string) (*blueskySession, error) {
+	s.logger.Debugf("Creating Bluesky session for %s", identifier)
+
+	var session blueskySession
+	err := s.callXRPC(ctx, "com.atproto.server.createSession", "", map[string]string{
+		"identifier": identifier,
+		"password":   appPassword,
+	}, &session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with Bluesky: %w", err)
+	}
+
+	return &session, nil
+}
+
+// uploadBlob uploads an image and returns the blob reference to embed in a post
+func (s *BlueskyService) uploadBlob(ctx context.Context, accessJwt string, image *ShareImage) (json.RawMessage, error) {
+	data, contentType, err := image.read()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > blueskyMaxBlobSize {
+		return nil, fmt.Errorf("image %s is %d bytes, over Bluesky's %d byte limit", image.Path, len(data), blueskyMaxBlobSize)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.pdsURL+"/xrpc/com.atproto.repo.uploadBlob", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+accessJwt)
+
+	s.logger.Debugf("Uploading image to Bluesky: %s", image.Path)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("com.atproto.repo.uploadBlob returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Blob json.RawMessage `json:"blob"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(result.Blob) == 0 {
+		return nil, fmt.Errorf("com.atproto.repo.uploadBlob response did not include a blob: %s", string(body))
+	}
+	return result.Blob, nil
+}
+
+// callXRPC POSTs a JSON body to an XRPC procedure and decodes the JSON response into out
+func (s *BlueskyService) callXRPC(ctx context.Context, method, accessJwt string, in interface{}, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.pdsURL+"/xrpc/"+method, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if accessJwt != "" {
+		req.Header.Set("Authorization", "Bearer "+accessJwt)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status code %d: %s", method, resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// linkFacets returns link facets for every URL in the text.
+// Bluesky does not auto-link URLs, and facet ranges are UTF-8 byte offsets.
+func linkFacets(text string) []blueskyFacet {
+	var facets []blueskyFacet
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		var facet blueskyFacet
+		facet.Index.ByteStart = loc[0]
+		facet.Index.ByteEnd = loc[1]
+		facet.Features = []map[string]string{{
+			"$type": "app.bsky.richtext.facet#link",
+			"uri":   text[loc[0]:loc[1]],
+		}}
+		facets = append(facets, facet)
+	}
+	return facets
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// contentPromptVersion is part of the response cache key; bump it when the prompt or
+// response parsing changes in a way that makes cached responses stale
+const contentPromptVersion = 1
+
+// cachedResponse is a raw model response stored in the cache directory
+type cachedResponse struct {
+	Model     string    `json:"model"`
+	JSONMode  bool      `json:"json_mode"`
+	Response  string    `json:"response"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DefaultCacheDir returns the default directory for cached AI responses, or "" if there is no user cache directory
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aipodflow")
+}
+
+// contentCacheKey returns the SHA-256 of the transcript, model, prompt version and the
+// options that change the prompt
+func contentCacheKey(transcript, model string, numTitles int, opts GenerateOptions) string {
+	inputs, _ := json.Marshal(struct {
+		Version        int
+		Model          string
+		NumTitles      int
+		Examples       []StyleExample
+		PromptTemplate string
+		EpisodeNumber  int
+		Hosts          []string
+		JSONMode       bool
+		Temperature    float32
+		MaxTokens      int
+		Language       string
+		Speakers       []string
+		Timestamped    bool `json:",omitempty"`
+		ToolCalling    bool `json:",omitempty"`
+	}{contentPromptVersion, model, numTitles, opts.Examples, opts.PromptTemplate, opts.EpisodeNumber, opts.Hosts, opts.JSONMode, opts.temperature(), opts.maxTokens(), opts.language(), opts.Speakers, opts.TimestampedNotes, opts.ToolCalling})
+
+	hash := sha256.New()
+	hash.Write(inputs)
+	hash.Write([]byte{0})
+	hash.Write([]byte(transcript))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// loadCachedResponse reads a cached response; a missing or unreadable entry is a miss
+func (s *AIService) loadCachedResponse(key string) (*cachedResponse, bool) {
+	data, err := os.ReadFile(filepath.Join(s.CacheDir, key+".json"))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warnf("Failed to read cached response: %v", err)
+		}
+		return nil, false
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Response == "" {
+		s.logger.Warnf("Ignoring invalid cached response %s", key)
+		return nil, false
+	}
+	return &cached, true
+}
+
+// saveCachedResponse stores a response in the cache; failures only log a warning
+func (s *AIService) saveCachedResponse(key string, cached *cachedResponse) {
+	if err := writeCachedResponse(filepath.Join(s.CacheDir, key+".json"), cached); err != nil {
+		s.logger.Warnf("Failed to cache response: %v", err)
+		return
+	}
+	s.logger.Debugf("Cached response as %s", key)
+}
+
+// writeCachedResponse writes a cache entry, creating the cache directory if needed
+func writeCachedResponse(path string, cached *cachedResponse) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached response: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/automate-podcast/internal/model"
+	"github.com/sashabaranov/go-openai"
+)
+
+// chaptersMaxTokens is the maximum number of tokens requested for chapter suggestions
+const chaptersMaxTokens = 1500
+
+// chaptersSystemPrompt is the system prompt used for chapter suggestions
+const chaptersSystemPrompt = "You are a podcast producer dividing an episode into chapters for podcast apps. Each chapter covers one topic. Write short chapter titles (under 40 characters) in the transcript's language, without emojis or numbering."
+
+// chaptersPrompt asks for chapters in a timestamped transcript part
+const chaptersPrompt = `Below is part %d of %d of a podcast transcript. Each line starts with its start time as [HH:MM:SS].
+
+Divide this part into chapters at the points where the topic changes.%s
+Answer with one chapter per line, in this exact format and nothing else:
+HH:MM:SS | chapter title
+
+Use only start times that appear in the transcript, in chronological order.
+
+%s`
+
+// firstChapterInstruction is added for the first transcript part, where the episode starts
+const firstChapterInstruction = " The first chapter must start at 00:00:00."
+
+// GenerateChapters asks the model for chapter markers in a timestamped transcript.
+// Long transcripts are split with the same chunking as content generation. Suggestions that
+// are out of order or beyond the end of the transcript are dropped, and the first chapter
+// always starts at 00:00:00.
+func (s *AIService) GenerateChapters(ctx context.Context, segments []TranscriptSegment, opts GenerateOptions) ([]model.Chapter, error) {
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("chapters need a timestamped transcript (SRT or VTT)")
+	}
+	s.logger.Info("Generating chapter markers...")
+
+	maxTokens := s.MaxTranscriptTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTranscriptTokens
+	}
+
+	duration := 0.0
+	var lines strings.Builder
+	for _, segment := range segments {
+		fmt.Fprintf(&lines, "[%s] %s\n", formatTimecode(segment.Start), strings.ReplaceAll(segment.Text, "\n", " "))
+		duration = math.Max(duration, segment.End)
+	}
+	chunks := splitTranscript(lines.String(), maxTokens)
+
+	var chapters []model.Chapter
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			s.logger.Infof("Finding chapters in part %d/%d...", i+1, len(chunks))
+		}
+
+		instruction := ""
+		if i == 0 {
+			instruction = firstChapterInstruction
+		}
+		req := openai.ChatCompletionRequest{
+			Model: openai.GPT4o,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: chaptersSystemPrompt,
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: fmt.Sprintf(chaptersPrompt, i+1, len(chunks), instruction, chunk),
+				},
+			},
+			Temperature: 0.3,
+			MaxTokens:   chaptersMaxTokens,
+		}
+
+		resp, err := s.createChatCompletion(ctx, req)
+		if err != nil {
+			s.logger.Errorf("OpenAI API error: %v", err)
+			return nil, fmt.Errorf("failed to generate chapters: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("empty response from OpenAI")
+		}
+		reportUsage(opts, req.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+
+		// Chapter lines have the same "timecode | text" shape as ad break suggestions
+		for _, suggestion := range parseAdTimecodes(resp.Choices[0].Message.Content) {
+			seconds, _ := timecodeSeconds(suggestion[0])
+			if seconds > duration {
+				s.logger.Warnf("Dropping chapter %s beyond the end of the transcript (%s)", suggestion[0], formatTimecode(duration))
+				continue
+			}
+			if len(chapters) > 0 && seconds <= chapters[len(chapters)-1].StartSeconds {
+				s.logger.Debugf("Dropping out-of-order chapter %s", suggestion[0])
+				continue
+			}
+			chapters = append(chapters, model.Chapter{StartSeconds: seconds, Title: suggestion[1]})
+		}
+	}
+
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("no chapters found in the response")
+	}
+	if chapters[0].StartSeconds > 0 {
+		s.logger.Debugf("Moving the first chapter from %s to 00:00:00", formatTimecode(chapters[0].StartSeconds))
+		chapters[0].StartSeconds = 0
+	}
+	if err := ValidateChapters(chapters, duration); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Generated %d chapters", len(chapters))
+	return chapters, nil
+}
+
+// ValidateChapters checks that chapter start times are strictly increasing and, when the
+// episode duration is known (greater than 0), within the episode
+func ValidateChapters(chapters []model.Chapter, duration float64) error {
+	for i, chapter := range chapters {
+		if chapter.StartSeconds < 0 {
+			return fmt.Errorf("chapter %d (%q) starts before the episode", i+1, chapter.Title)
+		}
+		if duration > 0 && chapter.StartSeconds > duration {
+			return fmt.Errorf("chapter %d (%q) starts at %s, after the end of the episode (%s)", i+1, chapter.Title, formatTimecode(chapter.StartSeconds), formatTimecode(duration))
+		}
+		if i > 0 && chapter.StartSeconds <= chapters[i-1].StartSeconds {
+			return fmt.Errorf("chapter %d (%q) starts at %s, not after chapter %d (%s)", i+1, chapter.Title, formatTimecode(chapter.StartSeconds), i, formatTimecode(chapters[i-1].StartSeconds))
+		}
+		if strings.TrimSpace(chapter.Title) == "" {
+			return fmt.Errorf("chapter %d at %s has no title", i+1, formatTimecode(chapter.StartSeconds))
+		}
+	}
+	return nil
+}
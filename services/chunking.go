@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// summaryMaxTokens is the maximum number of tokens requested for each chunk summary
+const summaryMaxTokens = 1500
+
+// prepareTranscript returns the transcript unchanged when it fits within MaxTranscriptTokens.
+// Otherwise it splits the transcript into chunks, summarizes each one, and returns the
+// concatenated summaries for use in the final prompt.
+func (s *AIService) prepareTranscript(ctx context.Context, transcript string, opts GenerateOptions) (string, error) {
+	maxTokens := s.MaxTranscriptTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTranscriptTokens
+	}
+
+	tokens := EstimateTokens(transcript)
+	if tokens <= maxTokens {
+		s.logger.Debugf("Transcript fits in a single prompt (~%d tokens)", tokens)
+		return transcript, nil
+	}
+
+	chunks := splitTranscript(transcript, maxTokens)
+	s.logger.Infof("Transcript is ~%d tokens, split into %d chunks for summarization", tokens, len(chunks))
+
+	summaries := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		s.logger.Infof("Summarizing chunk %d/%d...", i+1, len(chunks))
+		summary, err := s.summarizeChunk(ctx, chunk, i+1, len(chunks), opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize transcript chunk %d: %w", i+1, err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return joinChunkSummaries(summaries), nil
+}
+
+// joinChunkSummaries combines the chunk summaries into the transcript section of the final prompt
+func joinChunkSummaries(summaries []string) string {
+	parts := make([]string, len(summaries))
+	for i, summary := range summaries {
+		parts[i] = fmt.Sprintf("[Part %d]\n%s", i+1, summary)
+	}
+	return "The transcript was too long to include in full. Below are detailed summaries of each consecutive part of the episode:\n\n" +
+		strings.Join(parts, "\n\n")
+}
+
+// summarizeChunk asks the model for a detailed summary of one transcript chunk
+func (s *AIService) summarizeChunk(ctx context.Context, chunk string, part, total int, opts GenerateOptions) (string, error) {
+	// Keep the start times of timestamped transcripts so the show note can link to the topics
+	instruction := "Summarize it as a list of the topics discussed, in order"
+	if opts.TimestampedNotes {
+		instruction += ", starting each topic with the [MM:SS] time of the transcript line where it begins"
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: "You summarize podcast transcripts. Keep every distinct topic, notable anecdote, product name, and proper noun. Write the summary in the transcript's language.",
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: fmt.Sprintf("This is part %d of %d of a podcast transcript. %s:\n\n%s", part, total, instruction, chunk),
+			},
+		},
+		Temperature: 0.3,
+		MaxTokens:   summaryMaxTokens,
+	}
+
+	resp, err := s.createChatCompletion(ctx, req)
+	if err != nil {
+		s.logger.Errorf("OpenAI API error: %v", err)
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty response from OpenAI")
+	}
+	reportUsage(opts, req.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// splitTranscript splits a transcript on sentence and paragraph boundaries into
+// chunks of at most maxTokens estimated tokens
+func splitTranscript(transcript string, maxTokens int) []string {
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+	}
+
+	for _, sentence := range splitSentences(transcript) {
+		// Hard-split sentences that are larger than a whole chunk on their own
+		for EstimateTokens(sentence) > maxTokens {
+			flush()
+			runes := []rune(sentence)
+			cut := maxTokens * 4
+			chunks = append(chunks, strings.TrimSpace(string(runes[:cut])))
+			sentence = string(runes[cut:])
+		}
+
+		if EstimateTokens(current.String()+sentence) > maxTokens {
+			flush()
+		}
+		current.WriteString(sentence)
+	}
+	flush()
+
+	return chunks
+}
+
+// splitSentences splits text after sentence terminators and newlines, keeping the delimiters
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		switch r {
+		case '\n', '。', '！', '？', '.', '!', '?':
+			end := i + len(string(r))
+			sentences = append(sentences, text[start:end])
+			start = end
+		}
+	}
+	if start < len(text) {
+		sentences = append(sentences, text[start:])
+	}
+	return sentences
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultClaudeModel is the Anthropic model used for content generation
+const DefaultClaudeModel = "claude-sonnet-4-5"
+
+// anthropicMessagesURL is the endpoint of the Anthropic Messages API
+const anthropicMessagesURL = "https://api.anthropic.com/v1/messages"
+
+// anthropicVersion is the Anthropic API version sent with every request
+const anthropicVersion = "2023-06-01"
+
+// ClaudeService is a service responsible for content generation with Anthropic Claude
+type ClaudeService struct {
+	apiKey string
+	model  string
+	client *http.Client
+	logger *logrus.Logger
+
+	// MaxRetries is the number of retries for rate-limit and 5xx errors from Anthropic
+	MaxRetries int
+}
+
+// claudeMessage is a single message in an Anthropic Messages API request
+type claudeMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// claudeRequest is the request body of the Anthropic Messages API
+type claudeRequest struct {
+	Model       string          `json:"model"`
+	MaxTokens   int             `json:"max_tokens"`
+	System      string          `json:"system,omitempty"`
+	Messages    []claudeMessage `json:"messages"`
+	Temperature float32         `json:"temperature"`
+}
+
+// claudeResponse is the response body of the Anthropic Messages API
+type claudeResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// NewClaudeService creates a new ClaudeService instance
+func NewClaudeService(apiKey string, logger *logrus.Logger) *ClaudeService {
+	return &ClaudeService{
+		apiKey:     apiKey,
+		model:      DefaultClaudeModel,
+		client:     NewHTTPClient(slowRequestTimeout(DefaultGenerationTimeout)),
+		logger:     logger,
+		MaxRetries: DefaultMaxRetries,
+	}
+}
+
+// GenerateAllContent generates title candidates and a show note in a single API call
+func (s *ClaudeService) GenerateAllContent(ctx context.Context, transcript string, opts GenerateOptions) ([]string, []string, error) {
+	s.logger.Infof("Generating all content with Anthropic %s...", s.model)
+
+	numTitles := opts.NumTitles
+	if numTitles <= 0 {
+		numTitles = DefaultNumTitles
+	}
+
+	// Create a combined prompt that requests both title and show note
+	prompt, err := buildContentPrompt(transcript, numTitles, opts, s.logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := claudeRequest{
+		Model:     s.model,
+		MaxTokens: opts.maxTokens(),
+		System:    contentSystemPrompt(languageNames[opts.language()]),
+		Messages: []claudeMessage{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: opts.temperature(),
+	}
+
+	// Make the API call
+	resp, err := s.createMessage(ctx, req)
+	if err != nil {
+		s.logger.Errorf("Anthropic API error: %v", err)
+		return nil, nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	reportUsage(opts, req.Model, resp.Usage.InputTokens, resp.Usage.OutputTokens)
+
+	// Concatenate the text blocks of the response
+	var sb strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+
+	// Split the response into title candidates and show notes
+	titles, showNotes := parseContentResponse(sb.String(), numTitles, s.logger)
+
+	s.logger.Info("Generated content successfully")
+	return titles, showNotes, nil
+}
+
+// createMessage calls the Anthropic Messages API, retrying rate-limit and 5xx errors
+// with exponential backoff plus jitter
+func (s *ClaudeService) createMessage(ctx context.Context, req claudeRequest) (*claudeResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, statusCode, err := s.postMessage(ctx, body)
+		if err == nil {
+			return resp, nil
+		}
+
+		retryable := statusCode == http.StatusTooManyRequests || statusCode >= 500
+		if attempt >= s.MaxRetries || !retryable {
+			return nil, err
+		}
+
+		delay := backoffDelay(attempt)
+		s.logger.Warnf("Anthropic request failed (%v), retrying in %s (attempt %d/%d)", err, delay.Round(time.Millisecond), attempt+2, s.MaxRetries+1)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// postMessage makes a single Messages API call and returns the parsed response and HTTP status code
+func (s *ClaudeService) postMessage(ctx context.Context, body []byte) (*claudeResponse, int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicMessagesURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", s.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result claudeResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, resp.StatusCode, nil
+}
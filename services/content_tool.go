@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// emitContentToolName is the name of the function the model calls with the generated content
+const emitContentToolName = "emit_content"
+
+// toolCallingInstruction replaces the section header instructions when tool calling is used
+const toolCallingInstruction = `Instead of section headers, call the emit_content function with the title candidates and the show note.
+Each title is one string without a list number prefix. The show note keeps the exact format above, with line breaks as \n.`
+
+// emitContentTool returns the tool definition whose arguments have the shape of ContentCandidates.
+// Strict mode makes the model's arguments always match the schema.
+func emitContentTool() openai.Tool {
+	return openai.Tool{
+		Type: openai.ToolTypeFunction,
+		Function: &openai.FunctionDefinition{
+			Name:        emitContentToolName,
+			Description: "Emit the title candidates and show note generated for the podcast episode.",
+			Strict:      true,
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"titles": {
+						Type:        jsonschema.Array,
+						Description: "Distinct title candidates, without list numbers",
+						Items:       &jsonschema.Definition{Type: jsonschema.String},
+					},
+					"show_notes": {
+						Type:        jsonschema.Array,
+						Description: "The show note, as a single-element array",
+						Items:       &jsonschema.Definition{Type: jsonschema.String},
+					},
+				},
+				Required:             []string{"titles", "show_notes"},
+				AdditionalProperties: false,
+			},
+		},
+	}
+}
+
+// emitContentArguments returns the JSON arguments of the emit_content call in a response message,
+// or false if the model answered without calling it
+func emitContentArguments(message openai.ChatCompletionMessage) (string, bool) {
+	for _, call := range message.ToolCalls {
+		if call.Function.Name == emitContentToolName {
+			return call.Function.Arguments, true
+		}
+	}
+	return "", false
+}
+
+// isUnsupportedToolsError reports whether OpenAI rejected the request because the model
+// doesn't support tool calling
+func isUnsupportedToolsError(err error) bool {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		return false
+	}
+	if apiErr.Param != nil && (*apiErr.Param == "tools" || *apiErr.Param == "tool_choice") {
+		return true
+	}
+	return strings.Contains(apiErr.Message, "tools") || strings.Contains(apiErr.Message, "tool_choice")
+}
@@ -0,0 +1,20 @@
+package services
+
+import "context"
+
+// Deployment providers supported by step3
+const (
+	ProviderVercel  = "vercel"
+	ProviderNetlify = "netlify"
+)
+
+// Deployer triggers a redeployment of the podcast website
+type Deployer interface {
+	TriggerRedeploy(ctx context.Context) error
+}
+
+// Make sure the deployment services implement Deployer
+var (
+	_ Deployer = (*VercelService)(nil)
+	_ Deployer = (*NetlifyService)(nil)
+)
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultExampleTokenBudget is the maximum number of tokens spent on few-shot examples
+const DefaultExampleTokenBudget = 2000
+
+// modelContextTokens is the context window size assumed when budgeting prompt content
+const modelContextTokens = 128000
+
+// StyleExample is a past approved title and show note used as a few-shot example
+type StyleExample struct {
+	Title    string `json:"title"`
+	ShowNote string `json:"show_note"`
+	Date     string `json:"date,omitempty"`
+}
+
+// EstimateTokens estimates the token count of a text using a chars/4 heuristic
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// escapeSectionMarkers rewrites response section markers so examples cannot be
+// mistaken for the [TITLE]/[SHOW NOTE] sections of the model's answer
+func escapeSectionMarkers(text string) string {
+	replacer := strings.NewReplacer("[TITLE]", "(TITLE)", "[SHOW NOTE]", "(SHOW NOTE)")
+	return replacer.Replace(text)
+}
+
+// formatExamples renders as many examples as fit within the token budget.
+// Examples are expected to be ordered with the most recent first.
+func formatExamples(examples []StyleExample, budget int) (string, int) {
+	if len(examples) == 0 || budget <= 0 {
+		return "", 0
+	}
+
+	var sb strings.Builder
+	used := 0
+	count := 0
+	for _, example := range examples {
+		block := fmt.Sprintf("<example>\nExample title: %s\nExample show note:\n%s\n</example>\n",
+			escapeSectionMarkers(strings.TrimSpace(example.Title)),
+			escapeSectionMarkers(strings.TrimSpace(example.ShowNote)))
+
+		tokens := EstimateTokens(block)
+		if used+tokens > budget {
+			break
+		}
+		sb.WriteString(block)
+		used += tokens
+		count++
+	}
+
+	if count == 0 {
+		return "", 0
+	}
+
+	header := "Here are examples of previously approved titles and show notes for this show. Match their voice and style, but do not copy them and do not use the section headers inside the examples:\n"
+	return header + sb.String() + "\n", count
+}
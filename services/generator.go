@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"github.com/automate-podcast/internal/model"
+)
+
+// ContentGenerator generates title candidates and show notes from a transcript
+type ContentGenerator interface {
+	GenerateAllContent(ctx context.Context, transcript string, opts GenerateOptions) ([]string, []string, error)
+}
+
+// AdTimecodeGenerator suggests ad break points from a timestamped transcript
+type AdTimecodeGenerator interface {
+	GenerateAdTimecodes(ctx context.Context, segments []TranscriptSegment, opts GenerateOptions) ([][]string, error)
+}
+
+// ChapterGenerator suggests chapter markers from a timestamped transcript
+type ChapterGenerator interface {
+	GenerateChapters(ctx context.Context, segments []TranscriptSegment, opts GenerateOptions) ([]model.Chapter, error)
+}
+
+// Ensure the AI backends satisfy ContentGenerator
+var (
+	_ ContentGenerator    = (*AIService)(nil)
+	_ ContentGenerator    = (*ClaudeService)(nil)
+	_ AdTimecodeGenerator = (*AIService)(nil)
+	_ ChapterGenerator    = (*AIService)(nil)
+)
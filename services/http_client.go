@@ -0,0 +1,59 @@
+package services
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPTimeout is the timeout of outbound API requests unless configured with SetHTTPTimeout
+const DefaultHTTPTimeout = 30 * time.Second
+
+// Timeouts of requests that upload audio or wait for a model or browser to finish. They are
+// raised to the configured HTTP timeout if that is longer, so a larger --http-timeout applies to them too.
+const (
+	DefaultTranscriptionTimeout = 10 * time.Minute
+	DefaultGenerationTimeout    = 5 * time.Minute
+)
+
+var (
+	httpTimeoutMu sync.RWMutex
+	httpTimeout   = DefaultHTTPTimeout
+)
+
+// SetHTTPTimeout sets the timeout of the HTTP clients created afterwards with HTTPTimeout.
+// A timeout of 0 or less restores DefaultHTTPTimeout.
+func SetHTTPTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	httpTimeoutMu.Lock()
+	httpTimeout = timeout
+	httpTimeoutMu.Unlock()
+}
+
+// HTTPTimeout returns the configured timeout of outbound API requests
+func HTTPTimeout() time.Duration {
+	httpTimeoutMu.RLock()
+	defer httpTimeoutMu.RUnlock()
+	return httpTimeout
+}
+
+// slowRequestTimeout returns the timeout of a slow request type: its default, or the configured
+// HTTP timeout if that is longer
+func slowRequestTimeout(timeout time.Duration) time.Duration {
+	if configured := HTTPTimeout(); configured > timeout {
+		return configured
+	}
+	return timeout
+}
+
+// NewHTTPClient creates the HTTP client of an outbound service. A timeout of 0 or less uses
+// the configured HTTPTimeout. Every request of the client, including reading the response
+// body, must finish within the timeout, so a stalled server can't hang a run.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = HTTPTimeout()
+	}
+	return &http.Client{Timeout: timeout}
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // Register the JPEG decoder for background images
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size of the share image, the recommended size for Open Graph images
+const (
+	ShareImageWidth  = 1200
+	ShareImageHeight = 630
+)
+
+// Layout of the title on the share image
+const (
+	shareImagePadding     = 80
+	shareImageMaxLines    = 4
+	shareImageMaxFontSize = 72
+	shareImageMinFontSize = 40
+	shareImageLineSpacing = 1.35
+)
+
+// Default colors of the share image
+var (
+	DefaultShareImageBackground = color.RGBA{R: 0x1f, G: 0x2a, B: 0x44, A: 0xff}
+	DefaultShareImageTextColor  = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+// defaultFontPaths are fonts with Japanese glyphs that are tried when no font is configured
+var defaultFontPaths = []string{
+	"/System/Library/Fonts/ヒラギノ角ゴシック W6.ttc",
+	"/System/Library/Fonts/Hiragino Sans GB.ttc",
+	"/usr/share/fonts/opentype/noto/NotoSansCJK-Bold.ttc",
+	"/usr/share/fonts/noto-cjk/NotoSansCJK-Bold.ttc",
+	"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Bold.ttc",
+	"/usr/share/fonts/truetype/noto/NotoSansJP-Bold.ttf",
+	"C:\\Windows\\Fonts\\YuGothB.ttc",
+	"C:\\Windows\\Fonts\\meiryob.ttc",
+}
+
+// ShareImage is an image attached to a social media post
+type ShareImage struct {
+	Path    string
+	AltText string // Description for screen readers, e.g. the episode title
+}
+
+// read returns the image data and its content type
+func (img *ShareImage) read() ([]byte, string, error) {
+	data, err := os.ReadFile(img.Path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+// multipartBody builds a multipart form with the image in fileField and the given text fields
+func (img *ShareImage) multipartBody(fileField string, fields map[string]string) (*bytes.Buffer, string, error) {
+	data, contentType, err := img.read()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, fileField, filepath.Base(img.Path)))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", fmt.Errorf("failed to write form file: %w", err)
+	}
+
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", fmt.Errorf("failed to write %s field: %w", name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to finalize multipart body: %w", err)
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// ImageService renders share images for social media posts
+type ImageService struct {
+	logger *logrus.Logger
+
+	// FontPath is a TrueType or OpenType font (.ttf, .otf or .ttc) for the title.
+	// When empty, common Japanese system fonts are tried before falling back to Go Bold, which has no CJK glyphs.
+	FontPath string
+	// BackgroundPath is an optional PNG or JPEG image drawn behind the title, scaled to cover the card
+	BackgroundPath string
+	// BackgroundColor fills the card when no background image is set
+	BackgroundColor color.Color
+	// TextColor is the color of the title
+	TextColor color.Color
+}
+
+// NewImageService creates a new ImageService instance
+func NewImageService(logger *logrus.Logger) *ImageService {
+	return &ImageService{
+		logger:          logger,
+		BackgroundColor: DefaultShareImageBackground,
+		TextColor:       DefaultShareImageTextColor,
+	}
+}
+
+// RenderShareImage renders the episode title on a share card and saves it as a PNG at outputPath.
+// Long titles are wrapped within the card and shrunk, and shortened with an ellipsis if they still don't fit.
+func (s *ImageService) RenderShareImage(title, outputPath string) (string, error) {
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		return "", fmt.Errorf("share image title is empty")
+	}
+
+	fnt, err := s.loadFont()
+	if err != nil {
+		return "", err
+	}
+	if missing := missingGlyphs(fnt, title); missing != "" {
+		s.logger.Warnf("Font has no glyphs for %q; set a font with Japanese glyphs to render the title correctly", missing)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, ShareImageWidth, ShareImageHeight))
+	if err := s.drawBackground(img); err != nil {
+		return "", err
+	}
+	if err := s.drawTitle(img, fnt, title); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create share image directory: %w", err)
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create share image: %w", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return "", fmt.Errorf("failed to encode share image: %w", err)
+	}
+
+	s.logger.Debugf("Share image saved to %s", outputPath)
+	return outputPath, nil
+}
+
+// loadFont loads the configured font, the first available system font, or Go Bold
+func (s *ImageService) loadFont() (*sfnt.Font, error) {
+	if s.FontPath != "" {
+		return parseFontFile(s.FontPath)
+	}
+
+	for _, path := range defaultFontPaths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		fnt, err := parseFontFile(path)
+		if err != nil {
+			s.logger.Debugf("Skipping font %s: %v", path, err)
+			continue
+		}
+		s.logger.Debugf("Using font %s for the share image", path)
+		return fnt, nil
+	}
+
+	s.logger.Debug("No Japanese system font found, using Go Bold for the share image")
+	return opentype.Parse(gobold.TTF)
+}
+
+// parseFontFile parses a font file; collections (.ttc) use their first font
+func parseFontFile(path string) (*sfnt.Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".ttc") {
+		collection, err := opentype.ParseCollection(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse font collection %s: %w", path, err)
+		}
+		fnt, err := collection.Font(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load font from collection %s: %w", path, err)
+		}
+		return fnt, nil
+	}
+
+	fnt, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font %s: %w", path, err)
+	}
+	return fnt, nil
+}
+
+// missingGlyphs returns the characters of text that the font can't render
+func missingGlyphs(fnt *sfnt.Font, text string) string {
+	var buf sfnt.Buffer
+	var missing []rune
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if unicode.IsSpace(r) || seen[r] {
+			continue
+		}
+		seen[r] = true
+		if index, err := fnt.GlyphIndex(&buf, r); err != nil || index == 0 {
+			missing = append(missing, r)
+		}
+	}
+	return string(missing)
+}
+
+// drawBackground fills the card with the background image, darkened for legibility, or the background color
+func (s *ImageService) drawBackground(img *image.RGBA) error {
+	if s.BackgroundPath == "" {
+		draw.Draw(img, img.Bounds(), image.NewUniform(s.BackgroundColor), image.Point{}, draw.Src)
+		return nil
+	}
+
+	file, err := os.Open(s.BackgroundPath)
+	if err != nil {
+		return fmt.Errorf("failed to open share image background: %w", err)
+	}
+	defer file.Close()
+
+	background, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("failed to decode share image background %s (PNG or JPEG expected): %w", s.BackgroundPath, err)
+	}
+
+	xdraw.CatmullRom.Scale(img, img.Bounds(), background, coverRect(background.Bounds(), img.Bounds()), draw.Src, nil)
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{A: 0x80}), image.Point{}, draw.Over)
+	return nil
+}
+
+// coverRect returns the centered part of src with the aspect ratio of dst
+func coverRect(src, dst image.Rectangle) image.Rectangle {
+	srcW, srcH := src.Dx(), src.Dy()
+	dstW, dstH := dst.Dx(), dst.Dy()
+
+	if srcW*dstH > srcH*dstW {
+		// Source is wider: crop the sides
+		w := srcH * dstW / dstH
+		x := src.Min.X + (srcW-w)/2
+		return image.Rect(x, src.Min.Y, x+w, src.Max.Y)
+	}
+	// Source is taller: crop the top and bottom
+	h := srcW * dstH / dstW
+	y := src.Min.Y + (srcH-h)/2
+	return image.Rect(src.Min.X, y, src.Max.X, y+h)
+}
+
+// drawTitle draws the title centered on the card, using the largest font size at which it fits
+func (s *ImageService) drawTitle(img *image.RGBA, fnt *sfnt.Font, title string) error {
+	maxWidth := fixed.I(ShareImageWidth - 2*shareImagePadding)
+	maxHeight := float64(ShareImageHeight - 2*shareImagePadding)
+
+	var face font.Face
+	var lines []string
+	size := float64(shareImageMaxFontSize)
+	for {
+		var err error
+		face, err = opentype.NewFace(fnt, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return fmt.Errorf("failed to create font face: %w", err)
+		}
+		lines = wrapText(face, title, maxWidth)
+		fits := len(lines) <= shareImageMaxLines && float64(len(lines))*size*shareImageLineSpacing <= maxHeight
+		if fits || size-4 < shareImageMinFontSize {
+			break
+		}
+		face.Close()
+		size -= 4
+	}
+	defer face.Close()
+
+	// Shorten the title if it doesn't fit even at the smallest size
+	if len(lines) > shareImageMaxLines {
+		lines = lines[:shareImageMaxLines]
+		lines[len(lines)-1] = ellipsize(face, lines[len(lines)-1], maxWidth)
+	}
+
+	drawer := &font.Drawer{Dst: img, Src: image.NewUniform(s.TextColor), Face: face}
+	lineHeight := size * shareImageLineSpacing
+	metrics := face.Metrics()
+	top := (float64(ShareImageHeight) - float64(len(lines))*lineHeight) / 2
+	for i, line := range lines {
+		width := drawer.MeasureString(line)
+		// Center each line, placing the baseline so the ascent sits within the line box
+		x := (fixed.I(ShareImageWidth) - width) / 2
+		y := fixed.Int26_6((top+float64(i)*lineHeight+(lineHeight-size)/2)*64) + metrics.Ascent
+		drawer.Dot = fixed.Point26_6{X: x, Y: y}
+		drawer.DrawString(line)
+	}
+
+	return nil
+}
+
+// wrapText splits text into lines no wider than maxWidth. Lines break at spaces between words
+// and between any two characters of scripts written without spaces, such as Japanese.
+func wrapText(face font.Face, text string, maxWidth fixed.Int26_6) []string {
+	var lines []string
+	line := ""
+	for _, token := range wrapTokens(text) {
+		candidate := line + token
+		if line == "" {
+			candidate = strings.TrimLeft(token, " ")
+		}
+		if font.MeasureString(face, candidate) <= maxWidth {
+			line = candidate
+			continue
+		}
+
+		// Start a new line; a single token wider than the card is broken by character
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+		line = ""
+		for _, r := range strings.TrimLeft(token, " ") {
+			if line != "" && font.MeasureString(face, line+string(r)) > maxWidth {
+				lines = append(lines, line)
+				line = ""
+			}
+			line += string(r)
+		}
+	}
+	if strings.TrimSpace(line) != "" {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines
+}
+
+// closingPunctuation are Japanese punctuation marks that must not start a line
+const closingPunctuation = "、。，．・：；！？」』）】〉》ー…"
+
+// openingPunctuation are Japanese brackets that must not end a line
+const openingPunctuation = "「『（【〈《"
+
+// wrapTokens splits text into the units that wrapText keeps on one line: words with their
+// leading space, and single characters of scripts without spaces. Punctuation stays attached
+// to the neighboring character so closing marks never start and opening brackets never end a line.
+func wrapTokens(text string) []string {
+	var tokens []string
+	current := ""
+	flush := func() {
+		if current != "" {
+			tokens = append(tokens, current)
+			current = ""
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case r == ' ':
+			flush()
+			current = " "
+		case strings.ContainsRune(closingPunctuation+",.!?)", r):
+			// Keep closing punctuation with the previous token
+			if current == "" && len(tokens) > 0 {
+				current = tokens[len(tokens)-1]
+				tokens = tokens[:len(tokens)-1]
+			}
+			current += string(r)
+		case r < 0x2E80:
+			// Latin and other space-separated scripts form words
+			if current != "" && !isWordToken(current) {
+				flush()
+			}
+			current += string(r)
+		default:
+			// Keep opening brackets with the following character so they never end a line
+			if last, _ := utf8.DecodeLastRuneInString(current); !strings.ContainsRune(openingPunctuation, last) {
+				flush()
+			}
+			current += string(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// isWordToken reports whether a token is part of a space-separated word
+func isWordToken(token string) bool {
+	r, _ := utf8.DecodeLastRuneInString(token)
+	return r < 0x2E80 && !strings.ContainsRune(closingPunctuation, r)
+}
+
+// ellipsize shortens a line with a trailing ellipsis so it fits within maxWidth
+func ellipsize(face font.Face, line string, maxWidth fixed.Int26_6) string {
+	runes := []rune(line)
+	for n := len(runes); n > 0; n-- {
+		shortened := strings.TrimSpace(string(runes[:n])) + "…"
+		if font.MeasureString(face, shortened) <= maxWidth {
+			return shortened
+		}
+	}
+	return "…"
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultLinkedInAPIURL is the base URL of the LinkedIn REST API
+const DefaultLinkedInAPIURL = "https://api.linkedin.com"
+
+// linkedInArticleDescriptionMaxLength is the length the description of a shared article is cut to
+const linkedInArticleDescriptionMaxLength = 256
+
+// htmlTagPattern matches HTML tags in RSS descriptions
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// LinkedInArticle is the link shared with a LinkedIn post. LinkedIn only shows a link
+// preview for URLs shared as an article with their own title and description.
+type LinkedInArticle struct {
+	URL         string
+	Title       string
+	Description string
+}
+
+// LinkedInService handles posting to LinkedIn via the UGC Posts API
+type LinkedInService struct {
+	client *http.Client
+	logger *logrus.Logger
+
+	// APIURL is the base URL of the LinkedIn API (default: DefaultLinkedInAPIURL)
+	APIURL string
+}
+
+// NewLinkedInService creates a new LinkedInService instance
+func NewLinkedInService(logger *logrus.Logger) *LinkedInService {
+	return &LinkedInService{
+		client: NewHTTPClient(0),
+		logger: logger,
+		APIURL: DefaultLinkedInAPIURL,
+	}
+}
+
+// PostShare publishes a public post by the author URN (urn:li:person:... or urn:li:organization:...)
+// with the article attached if it is not nil, and returns the post's URL
+func (s *LinkedInService) PostShare(ctx context.Context, accessToken, authorURN, text string, article *LinkedInArticle) (string, error) {
+	if accessToken == "" || authorURN == "" {
+		return "", fmt.Errorf("LinkedIn access token and author URN are required")
+	}
+	if !strings.HasPrefix(authorURN, "urn:li:person:") && !strings.HasPrefix(authorURN, "urn:li:organization:") {
+		return "", fmt.Errorf("LinkedIn author URN %q must start with urn:li:person: or urn:li:organization:", authorURN)
+	}
+
+	shareContent := map[string]interface{}{
+		"shareCommentary":    map[string]string{"text": text},
+		"shareMediaCategory": "NONE",
+	}
+	if article != nil && article.URL != "" {
+		media := map[string]interface{}{
+			"status":      "READY",
+			"originalUrl": article.URL,
+		}
+		if article.Title != "" {
+			media["title"] = map[string]string{"text": article.Title}
+		}
+		if description := PlainDescription(article.Description, linkedInArticleDescriptionMaxLength); description != "" {
+			media["description"] = map[string]string{"text": description}
+		}
+		shareContent["shareMediaCategory"] = "ARTICLE"
+		shareContent["media"] = []interface{}{media}
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"author":          authorURN,
+		"lifecycleState":  "PUBLISHED",
+		"specificContent": map[string]interface{}{"com.linkedin.ugc.ShareContent": shareContent},
+		"visibility":      map[string]string{"com.linkedin.ugc.MemberNetworkVisibility": "PUBLIC"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal post: %w", err)
+	}
+
+	endpoint := strings.TrimRight(s.APIURL, "/") + "/v2/ugcPosts"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Restli-Protocol-Version", "2.0.0")
+
+	s.logger.Debugf("Posting share to LinkedIn: %s", endpoint)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to post share: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("LinkedIn API returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	// The URN of the new post is returned in a header, and in the body by some API versions
+	postURN := resp.Header.Get("X-RestLi-Id")
+	if postURN == "" {
+		var result struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &result); err == nil {
+			postURN = result.ID
+		}
+	}
+	if postURN == "" {
+		return "", fmt.Errorf("LinkedIn API response did not include the post ID: %s", string(body))
+	}
+
+	return "https://www.linkedin.com/feed/update/" + postURN + "/", nil
+}
+
+// PlainDescription converts an HTML episode description to plain text on a single line,
+// cut to maxLength characters with an ellipsis
+func PlainDescription(description string, maxLength int) string {
+	text := html.UnescapeString(htmlTagPattern.ReplaceAllString(description, " "))
+	text = strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(text)
+	if maxLength > 0 && len(runes) > maxLength {
+		text = strings.TrimSpace(string(runes[:maxLength-1])) + "…"
+	}
+	return text
+}
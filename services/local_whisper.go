@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultWhisperBinary is the whisper.cpp command line program run by LocalWhisperService
+const DefaultWhisperBinary = "whisper-cli"
+
+// LocalWhisperService transcribes audio on this machine with a whisper.cpp binary,
+// so the audio never leaves it
+type LocalWhisperService struct {
+	binary    string
+	modelPath string
+	logger    *logrus.Logger
+}
+
+// NewLocalWhisperService creates a new LocalWhisperService instance.
+// binary is the whisper.cpp program (default: DefaultWhisperBinary) and modelPath its ggml model file.
+func NewLocalWhisperService(binary, modelPath string, logger *logrus.Logger) *LocalWhisperService {
+	if binary == "" {
+		binary = DefaultWhisperBinary
+	}
+	return &LocalWhisperService{
+		binary:    binary,
+		modelPath: modelPath,
+		logger:    logger,
+	}
+}
+
+// whisperCppOutput is the JSON file written by whisper.cpp's --output-json option
+type whisperCppOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"` // Start time in milliseconds
+			To   int64 `json:"to"`   // End time in milliseconds
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+}
+
+// Transcribe processes an audio file and returns the transcription
+func (s *LocalWhisperService) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (string, error) {
+	result, err := s.TranscribeWithTimestamps(ctx, audioPath, opts)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// TranscribeWithTimestamps processes an audio file and returns the transcription with timed segments
+func (s *LocalWhisperService) TranscribeWithTimestamps(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	s.logger.Infof("Starting local transcription with %s for: %s", s.binary, audioPath)
+
+	if s.modelPath == "" {
+		return nil, fmt.Errorf("a whisper.cpp model file is required for local transcription")
+	}
+	if _, err := os.Stat(s.modelPath); err != nil {
+		return nil, fmt.Errorf("failed to open whisper.cpp model: %w", err)
+	}
+	if _, err := os.Stat(audioPath); err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	if _, err := exec.LookPath(s.binary); err != nil {
+		return nil, fmt.Errorf("whisper.cpp binary %q not found; install whisper.cpp or set its path: %w", s.binary, err)
+	}
+
+	tmpDir, err := NewTempDir(ctx, "whisper", s.logger)
+	if err != nil {
+		return nil, err
+	}
+	defer tmpDir.Cleanup()
+
+	// whisper.cpp reads 16 kHz WAV reliably; convert other formats if ffmpeg is installed
+	inputPath, err := s.prepareAudio(ctx, audioPath, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run whisper.cpp, writing the segments to <tmpDir>/transcript.json
+	language := opts.Language
+	if language == "" {
+		language = "auto"
+	}
+	outputBase := filepath.Join(tmpDir.Path(), "transcript")
+	args := []string{
+		"--model", s.modelPath,
+		"--file", inputPath,
+		"--language", language,
+		"--output-json",
+		"--output-file", outputBase,
+		"--no-prints",
+	}
+	if opts.Prompt != "" {
+		args = append(args, "--prompt", opts.Prompt)
+	}
+	cmd := exec.CommandContext(ctx, s.binary, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("whisper.cpp failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// Parse the segments
+	data, err := os.ReadFile(outputBase + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read whisper.cpp output: %w", err)
+	}
+	var output whisperCppOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse whisper.cpp output: %w", err)
+	}
+
+	result := &TranscriptionResult{Language: output.Result.Language}
+	var text strings.Builder
+	for _, segment := range output.Transcription {
+		text.WriteString(segment.Text)
+		result.Segments = append(result.Segments, TranscriptSegment{
+			Start: float64(segment.Offsets.From) / 1000,
+			End:   float64(segment.Offsets.To) / 1000,
+			Text:  strings.TrimSpace(segment.Text),
+		})
+	}
+	result.Text = strings.TrimSpace(text.String())
+	if n := len(result.Segments); n > 0 {
+		result.Duration = result.Segments[n-1].End
+	}
+
+	s.logger.Infof("Transcription completed successfully with %d segments", len(result.Segments))
+	return result, nil
+}
+
+// prepareAudio returns a 16 kHz mono WAV version of the audio file in dir, or the file itself
+// if it already is a WAV file or ffmpeg is not installed
+func (s *LocalWhisperService) prepareAudio(ctx context.Context, audioPath string, dir *TempDir) (string, error) {
+	if strings.EqualFold(filepath.Ext(audioPath), ".wav") {
+		return audioPath, nil
+	}
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		s.logger.Warn("ffmpeg not found, passing the audio to whisper.cpp as is; convert it to 16 kHz WAV if whisper.cpp can't read it")
+		return audioPath, nil
+	}
+
+	wavPath := dir.Join("audio.wav")
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error",
+		"-i", audioPath,
+		"-vn", "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le",
+		wavPath,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("ffmpeg failed to convert the audio to WAV: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return wavPath, nil
+}
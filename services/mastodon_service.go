@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MastodonMinScheduleDelay is how far in the future Mastodon requires a scheduled status to be
+const MastodonMinScheduleDelay = 5 * time.Minute
+
+// MastodonService handles posting to Mastodon
+type MastodonService struct {
+	client *http.Client
+	logger *logrus.Logger
+}
+
+// NewMastodonService creates a new MastodonService instance
+func NewMastodonService(logger *logrus.Logger) *MastodonService {
+	return &MastodonService{
+		client: NewHTTPClient(0),
+		logger: logger,
+	}
+}
+
+// PostStatus publishes a public status on a Mastodon instance, with the image attached if it is not nil
+func (s *MastodonService) PostStatus(ctx context.Context, instanceURL, accessToken, text string, image *ShareImage) error {
+	return s.postStatus(ctx, instanceURL, accessToken, text, image, time.Time{})
+}
+
+// ScheduleStatus schedules a public status to be published by the Mastodon instance at the given time,
+// which must be at least MastodonMinScheduleDelay in the future
+func (s *MastodonService) ScheduleStatus(ctx context.Context, instanceURL, accessToken, text string, image *ShareImage, at time.Time) error {
+	if time.Until(at) < MastodonMinScheduleDelay {
+		return fmt.Errorf("Mastodon can only schedule statuses at least %v in the future", MastodonMinScheduleDelay)
+	}
+	return s.postStatus(ctx, instanceURL, accessToken, text, image, at)
+}
+
+// postStatus publishes a status, or schedules it if scheduledAt is not zero
+func (s *MastodonService) postStatus(ctx context.Context, instanceURL, accessToken, text string, image *ShareImage, scheduledAt time.Time) error {
+	if instanceURL == "" || accessToken == "" {
+		return fmt.Errorf("Mastodon instance URL and access token are required")
+	}
+
+	endpoint := strings.TrimRight(instanceURL, "/") + "/api/v1/statuses"
+	form := url.Values{}
+	form.Set("status", text)
+	form.Set("visibility", "public")
+	if !scheduledAt.IsZero() {
+		form.Set("scheduled_at", scheduledAt.UTC().Format(time.RFC3339))
+	}
+
+	// Upload the image first and reference it from the status
+	if image != nil {
+		mediaID, err := s.uploadMedia(ctx, instanceURL, accessToken, image)
+		if err != nil {
+			return err
+		}
+		form.Add("media_ids[]", mediaID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.logger.Debugf("Posting status to Mastodon: %s", endpoint)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Mastodon API returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse the response; a scheduled status is returned as a ScheduledStatus without a URL
+	var status struct {
+		ID          string `json:"id"`
+		URL         string `json:"url"`
+		ScheduledAt string `json:"scheduled_at"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if !scheduledAt.IsZero() {
+		s.logger.Infof("Scheduled Mastodon status %s for %s", status.ID, status.ScheduledAt)
+		return nil
+	}
+	s.logger.Infof("Posted to Mastodon: %s", status.URL)
+	return nil
+}
+
+// uploadMedia uploads an image to a Mastodon instance and returns its media ID
+func (s *MastodonService) uploadMedia(ctx context.Context, instanceURL, accessToken string, image *ShareImage) (string, error) {
+	fields := map[string]string{}
+	if image.AltText != "" {
+		fields["description"] = image.AltText
+	}
+	body, contentType, err := image.multipartBody("file", fields)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := strings.TrimRight(instanceURL, "/") + "/api/v2/media"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create media upload request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", contentType)
+
+	s.logger.Debugf("Uploading image to Mastodon: %s", image.Path)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// 202 means the media is still being processed, which Mastodon allows statuses to reference
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("Mastodon media upload returned status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var media struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &media); err != nil {
+		return "", fmt.Errorf("failed to parse media upload response: %w", err)
+	}
+	if media.ID == "" {
+		return "", fmt.Errorf("Mastodon media upload response did not include a media ID: %s", string(respBody))
+	}
+	return media.ID, nil
+}
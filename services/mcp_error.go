@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MCPError is returned when the Playwright MCP server can't be reached, doesn't answer in time
+// or fails to run a script. Its message ends with a hint on how to fix the failure.
+type MCPError struct {
+	Script     string        // Script the server was asked to run, empty for a ping
+	ServerURL  string        // Endpoint of the MCP server
+	StatusCode int           // HTTP status of the server's response, 0 if there was none
+	Body       string        // Response body of a failed script
+	Timeout    time.Duration // Timeout of the call, set if it timed out
+	Err        error         // Underlying error, nil for a non-200 response
+}
+
+// newMCPError wraps a failed request to the MCP server
+func newMCPError(script, serverURL string, timeout time.Duration, err error) *MCPError {
+	mcpErr := &MCPError{Script: script, ServerURL: serverURL, Err: err}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		mcpErr.Timeout = timeout
+	}
+	return mcpErr
+}
+
+// Error returns the failure followed by the hint
+func (e *MCPError) Error() string {
+	var message string
+	switch {
+	case e.StatusCode != 0:
+		message = fmt.Sprintf("Playwright MCP server error (HTTP %d) running %s: %s", e.StatusCode, e.Script, strings.TrimSpace(e.Body))
+	case e.Timeout > 0 && e.Script != "":
+		message = fmt.Sprintf("Playwright MCP script %s did not finish within %s: %v", e.Script, e.Timeout, e.Err)
+	case e.Script != "":
+		message = fmt.Sprintf("failed to call Playwright MCP server: %v", e.Err)
+	default:
+		message = fmt.Sprintf("Playwright MCP server is not reachable: %v", e.Err)
+	}
+	return message + " (" + e.Hint() + ")"
+}
+
+// Unwrap returns the underlying error
+func (e *MCPError) Unwrap() error {
+	return e.Err
+}
+
+// Hint returns an actionable suggestion for the failure
+func (e *MCPError) Hint() string {
+	switch {
+	case e.StatusCode != 0:
+		return fmt.Sprintf("the script failed on the MCP server; check the server's log for its output and that %s exists on the server", e.Script)
+	case e.Timeout > 0:
+		return "the browser automation may be stuck on an unexpected Art19 page; retry, or raise the limit with --mcp-timeout"
+	default:
+		return fmt.Sprintf("is the Playwright MCP server running at %s?", e.serverRoot())
+	}
+}
+
+// serverRoot returns the scheme and host of the MCP server URL
+func (e *MCPError) serverRoot() string {
+	u, err := url.Parse(e.ServerURL)
+	if err != nil || u.Host == "" {
+		return e.ServerURL
+	}
+	return u.Scheme + "://" + u.Host
+}
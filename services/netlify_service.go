@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultNetlifyRetries is the default number of retries for a build hook call that Netlify didn't receive
+const DefaultNetlifyRetries = 3
+
+// NetlifyService is a service responsible for Netlify-related operations
+type NetlifyService struct {
+	buildHookURL string
+	client       *http.Client
+	logger       *logrus.Logger
+
+	// MaxRetries is the number of times a build hook call is retried when it didn't reach Netlify
+	MaxRetries int
+}
+
+// NewNetlifyService creates a new NetlifyService instance
+func NewNetlifyService(buildHookURL string, logger *logrus.Logger) *NetlifyService {
+	// Initialize HTTP client with timeout
+	client := NewHTTPClient(0)
+
+	return &NetlifyService{
+		buildHookURL: buildHookURL,
+		client:       client,
+		logger:       logger,
+		MaxRetries:   DefaultNetlifyRetries,
+	}
+}
+
+// TriggerRedeploy triggers a rebuild of the website on Netlify.
+// Only calls that never reached Netlify (connection failures) or were rate limited are retried:
+// Netlify has no cheap way to check whether a build was queued, so other failures could
+// otherwise trigger duplicate builds.
+func (s *NetlifyService) TriggerRedeploy(ctx context.Context) error {
+	if s.buildHookURL == "" {
+		return fmt.Errorf("Netlify build hook URL is not configured")
+	}
+
+	s.logger.Info("Triggering Netlify build...")
+
+	for attempt := 0; ; attempt++ {
+		retryable, err := s.callBuildHook(ctx)
+		if err == nil {
+			s.logger.Info("Netlify build triggered successfully")
+			return nil
+		}
+
+		if !retryable || attempt >= s.MaxRetries {
+			return fmt.Errorf("Netlify build hook failed: %w", err)
+		}
+
+		delay := backoffDelay(attempt)
+		s.logger.Warnf("Netlify build hook failed (%v), retrying in %s (attempt %d/%d)", err, delay.Round(time.Millisecond), attempt+2, s.MaxRetries+1)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// callBuildHook makes a single build hook call and reports whether the error is safe to retry
+func (s *NetlifyService) callBuildHook(ctx context.Context) (bool, error) {
+	// Netlify build hooks expect an empty POST request
+	req, err := http.NewRequestWithContext(ctx, "POST", s.buildHookURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Dial failures never reached the server; anything else may have
+		var opErr *net.OpError
+		return ctx.Err() == nil && errors.As(err, &opErr) && opErr.Op == "dial", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Log the response status for debugging
+	s.logger.Debugf("Netlify API response status: %s", resp.Status)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		s.logger.Debugf("Response body: %s", string(body))
+		return resp.StatusCode == http.StatusTooManyRequests, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return false, nil
+}
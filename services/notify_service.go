@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DiscordMaxLength is the maximum length of a Discord message in characters
+const DiscordMaxLength = 2000
+
+// SlackMaxLength is the maximum length of the text of a Slack section block in characters
+const SlackMaxLength = 3000
+
+// NotifyService sends notifications about the pipeline to team chat
+type NotifyService struct {
+	client *http.Client
+	logger *logrus.Logger
+}
+
+// NewNotifyService creates a new NotifyService instance
+func NewNotifyService(logger *logrus.Logger) *NotifyService {
+	return &NotifyService{
+		client: NewHTTPClient(0),
+		logger: logger,
+	}
+}
+
+// SendDiscord posts a message to a Discord webhook. Messages over DiscordMaxLength are cut.
+func (s *NotifyService) SendDiscord(ctx context.Context, webhookURL, message string) error {
+	if webhookURL == "" {
+		return fmt.Errorf("Discord webhook URL is required")
+	}
+
+	// Discord answers 204 No Content, or 200 with the message when ?wait=true is set
+	return s.postWebhook(ctx, "Discord", webhookURL, map[string]string{
+		"content": truncateMessage(message, DiscordMaxLength),
+	})
+}
+
+// SendSlack posts a message to a Slack incoming webhook as a section block, so Slack mrkdwn
+// such as *bold* and <url|label> links is rendered. Messages over SlackMaxLength are cut.
+func (s *NotifyService) SendSlack(ctx context.Context, webhookURL, message string) error {
+	if webhookURL == "" {
+		return fmt.Errorf("Slack webhook URL is required")
+	}
+
+	message = truncateMessage(message, SlackMaxLength)
+	return s.postWebhook(ctx, "Slack", webhookURL, map[string]interface{}{
+		// The text is shown in notifications, where blocks are not rendered
+		"text": message,
+		"blocks": []map[string]interface{}{{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": message},
+		}},
+	})
+}
+
+// postWebhook POSTs a JSON payload to a chat webhook and checks for a 2xx response
+func (s *NotifyService) postWebhook(ctx context.Context, name, webhookURL string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	s.logger.Debugf("Sending %s notification", name)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s webhook returned status code %d: %s", name, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// truncateMessage cuts a message to maxLength characters with an ellipsis
+func truncateMessage(message string, maxLength int) string {
+	runes := []rune(message)
+	if len(runes) > maxLength {
+		return string(runes[:maxLength-1]) + "…"
+	}
+	return message
+}
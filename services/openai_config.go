@@ -0,0 +1,48 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// AzureOpenAIAPIVersion is the Azure OpenAI API version used for chat completions and transcription
+const AzureOpenAIAPIVersion = "2024-06-01"
+
+// IsAzureOpenAIURL reports whether a base URL points to an Azure OpenAI resource,
+// e.g. https://<resource>.openai.azure.com
+func IsAzureOpenAIURL(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return strings.HasSuffix(host, ".openai.azure.com") || strings.HasSuffix(host, ".cognitiveservices.azure.com")
+}
+
+// OpenAIClientConfig returns the OpenAI client configuration for an API key and base URL.
+// An empty base URL uses the public OpenAI API. An Azure OpenAI resource URL routes each
+// request to a deployment: azureDeployment if set, otherwise one named after the model.
+// Any other URL is used as an OpenAI-compatible endpoint such as a proxy, e.g. https://proxy.example.com/v1.
+func OpenAIClientConfig(apiKey, baseURL, azureDeployment string) openai.ClientConfig {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	var config openai.ClientConfig
+	switch {
+	case baseURL == "":
+		config = openai.DefaultConfig(apiKey)
+	case IsAzureOpenAIURL(baseURL):
+		config = openai.DefaultAzureConfig(apiKey, baseURL)
+		config.APIVersion = AzureOpenAIAPIVersion
+		if azureDeployment != "" {
+			config.AzureModelMapperFunc = func(string) string { return azureDeployment }
+		}
+	default:
+		config = openai.DefaultConfig(apiKey)
+		config.BaseURL = baseURL
+	}
+
+	// Generation requests can take minutes, but must not hang forever
+	config.HTTPClient = NewHTTPClient(slowRequestTimeout(DefaultGenerationTimeout))
+	return config
+}
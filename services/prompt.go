@@ -0,0 +1,317 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/automate-podcast/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// contentSystemPrompt returns the system prompt used for title and show note generation in a language
+func contentSystemPrompt(language string) string {
+	return fmt.Sprintf("You are GenerativeAI acting as a podcast copy‑writer for a podcast in %s about parenting and technology. Follow the formatting instructions EXACTLY.", language)
+}
+
+// DefaultLanguage is the language code of generated content when none is specified
+const DefaultLanguage = "ja"
+
+// languageNames maps the supported ISO-639-1 language codes to the names used in prompts
+var languageNames = map[string]string{
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"zh": "Chinese",
+}
+
+// LanguageName returns the prompt name of an ISO-639-1 language code
+func LanguageName(code string) (string, error) {
+	name, ok := languageNames[strings.ToLower(code)]
+	if !ok {
+		codes := make([]string, 0, len(languageNames))
+		for code := range languageNames {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		return "", fmt.Errorf("unsupported language %q: must be one of %s", code, strings.Join(codes, ", "))
+	}
+	return name, nil
+}
+
+// titleMarkerPattern matches a leading list marker such as "1.", "1)", "- " or "・"
+var titleMarkerPattern = regexp.MustCompile(`^(?:(\d+)[.)．）]|[-*•・])\s*`)
+
+// PromptData is the data available to prompt templates
+type PromptData struct {
+	Transcript    string   // Transcript text (or chunk summaries for long transcripts)
+	EpisodeNumber int      // Episode number, 0 if unknown
+	Hosts         []string // Host handles or names
+	NumTitles     int      // Number of title candidates requested
+	Examples      string   // Rendered few-shot examples section, empty if none
+	Language      string   // Name of the output language, e.g. Japanese
+	LanguageCode  string   // ISO-639-1 code of the output language, e.g. ja
+	Speakers      []string // Speakers of a speaker-labeled transcript, nil if it isn't labeled
+	Timestamped   bool     // Transcript lines start with [MM:SS] times and the bullets need timecodes
+}
+
+// DefaultPromptTemplate is the built-in prompt template used when no template file is given
+const DefaultPromptTemplate = `You are GenerativeAI acting as a podcast copy‑writer for a podcast in {{.Language}} about parenting and technology.
+
+Please generate the following content for this podcast episode:
+
+1. TITLE: Provide {{.NumTitles}} distinct title candidates, one per line, each prefixed with its list number ("1.", "2.", ...). Each title must follow this pattern exactly:
+   NN. ＜{{.Language}} topic 1＞ / ＜{{.Language}} topic 2＞ [/ ＜{{.Language}} topic 3＞]
+   * NN = {{if .EpisodeNumber}}{{.EpisodeNumber}}{{else}}episode number (integer){{end}}
+   * Provide 2 or 3 topics
+   * Topics should be {{if eq .LanguageCode "en"}}in English{{else}}mainly in {{.Language}}, but keep any necessary English words as‑is (AI, GPT, etc.){{end}}
+
+2. SHOW NOTE: Create exactly this format:
+   * Opening summary: 2-3 lines in friendly {{.Language}} with relevant emojis. Each sentence MUST end with an exclamation mark (!)
+   * Bullet points: 8-12 points, each formatted as: [emoji] [Bold headline in {{.Language}}]: [Short description, maximum 1 line]{{if .Timestamped}} (MM:SS)
+   * Timecodes: end each bullet point with the time its topic starts, in parentheses, e.g. (12:34). Take it from the [MM:SS] mark of the transcript line where the topic is first discussed; never use a time after the last line. List the bullet points in chronological order{{end}}
+   * CTA block: Wrapped in dotted lines ("………"), asking for feedback via hashtag #momitfm
+   * Credits section: Must be titled exactly "✨🎧 Credits" and list hosts ({{if .Hosts}}{{join .Hosts " & "}}{{else}}@_yukamiya & @m2vela{{end}}) and intro creator (@kirillovlov2983)
+
+{{.Examples}}{{if .Speakers}}The transcript is a conversation. The speakers are: {{join .Speakers ", "}}. Each paragraph starts with the name of its speaker; use it to attribute opinions and stories to the right person, and to tell the hosts from any guests.
+
+{{end}}Here is the transcript of the podcast:
+{{.Transcript}}
+
+Format your response with clear section headers [TITLE] and [SHOW NOTE] to separate the content.`
+
+// BuildContentPrompt renders the content generation prompt for a transcript without calling the API.
+// Unlike GenerateAllContent, long transcripts are not chunked and summarized first.
+func BuildContentPrompt(transcript string, opts GenerateOptions, logger *logrus.Logger) (string, error) {
+	numTitles := opts.NumTitles
+	if numTitles <= 0 {
+		numTitles = DefaultNumTitles
+	}
+	return buildContentPrompt(transcript, numTitles, opts, logger)
+}
+
+// PreviewContentRequest renders the system and user messages of the content generation request
+// for inspection without calling the API. When chunking is enabled (maxTranscriptTokens > 0) and
+// the transcript is longer than maxTranscriptTokens, it is split as GenerateAllContent would split
+// it, and each chunk summary is shown as a placeholder with the size of its chunk.
+func PreviewContentRequest(transcript string, maxTranscriptTokens int, opts GenerateOptions, logger *logrus.Logger) (string, error) {
+	numTitles := opts.NumTitles
+	if numTitles <= 0 {
+		numTitles = DefaultNumTitles
+	}
+
+	var sb strings.Builder
+	tokens := EstimateTokens(transcript)
+	fullTranscript := transcript
+	summaryTokens := 0
+	if maxTranscriptTokens > 0 && tokens > maxTranscriptTokens {
+		chunks := splitTranscript(transcript, maxTranscriptTokens)
+		fmt.Fprintf(&sb, "Transcript: ~%d tokens, over the limit of %d. It is split into %d chunks, each summarized in a separate request first:\n", tokens, maxTranscriptTokens, len(chunks))
+		placeholders := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			chunkTokens := EstimateTokens(chunk)
+			fmt.Fprintf(&sb, "  Part %d: ~%d tokens, %d characters\n", i+1, chunkTokens, len([]rune(chunk)))
+			placeholders[i] = fmt.Sprintf("<summary of part %d (~%d tokens of transcript), up to %d tokens>", i+1, chunkTokens, summaryMaxTokens)
+		}
+		fullTranscript = joinChunkSummaries(placeholders)
+		summaryTokens = len(chunks) * summaryMaxTokens
+	} else {
+		fmt.Fprintf(&sb, "Transcript: ~%d tokens, sent in full\n", tokens)
+	}
+
+	prompt, err := buildContentPrompt(fullTranscript, numTitles, opts, logger)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case opts.ToolCalling:
+		prompt += "\n\n" + toolCallingInstruction
+	case opts.JSONMode:
+		prompt += "\n\n" + jsonModeInstruction
+	}
+	system := contentSystemPrompt(languageNames[opts.language()])
+
+	fmt.Fprintf(&sb, "Temperature: %g, max response tokens: %d, JSON mode: %t, tool calling: %t\n", opts.temperature(), opts.maxTokens(), opts.JSONMode && !opts.ToolCalling, opts.ToolCalling)
+	promptTokens := EstimateTokens(system) + EstimateTokens(prompt)
+	if summaryTokens > 0 {
+		fmt.Fprintf(&sb, "Prompt: ~%d tokens plus up to %d tokens of chunk summaries\n", promptTokens, summaryTokens)
+	} else {
+		fmt.Fprintf(&sb, "Prompt: ~%d tokens\n", promptTokens)
+	}
+	fmt.Fprintf(&sb, "\n===== system =====\n%s\n\n===== user =====\n%s\n", system, prompt)
+	if opts.ToolCalling {
+		tool, err := json.MarshalIndent(emitContentTool(), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal tool definition: %w", err)
+		}
+		fmt.Fprintf(&sb, "\n===== tools (%s is required) =====\n%s\n", emitContentToolName, tool)
+	}
+	return sb.String(), nil
+}
+
+// buildContentPrompt builds the user prompt requesting title candidates and a show note
+func buildContentPrompt(fullTranscript string, numTitles int, opts GenerateOptions, logger *logrus.Logger) (string, error) {
+	// Budget the few-shot examples so the transcript and response still fit in the context window
+	exampleBudget := opts.ExampleTokenBudget
+	if exampleBudget <= 0 {
+		exampleBudget = DefaultExampleTokenBudget
+	}
+	if remaining := modelContextTokens - opts.maxTokens() - EstimateTokens(fullTranscript) - 1000; remaining < exampleBudget {
+		exampleBudget = remaining
+	}
+	language, err := LanguageName(opts.language())
+	if err != nil {
+		return "", err
+	}
+
+	examplesSection, exampleCount := formatExamples(opts.Examples, exampleBudget)
+	if len(opts.Examples) > 0 {
+		logger.Infof("Including %d of %d style examples in the prompt", exampleCount, len(opts.Examples))
+	}
+
+	data := PromptData{
+		Transcript:    fullTranscript,
+		EpisodeNumber: opts.EpisodeNumber,
+		Hosts:         opts.Hosts,
+		NumTitles:     numTitles,
+		Examples:      examplesSection,
+		Language:      language,
+		LanguageCode:  opts.language(),
+		Speakers:      opts.Speakers,
+		Timestamped:   opts.TimestampedNotes,
+	}
+
+	templateText := opts.PromptTemplate
+	if templateText == "" {
+		templateText = DefaultPromptTemplate
+	}
+
+	tmpl, err := ParsePromptTemplate(templateText)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// ParsePromptTemplate parses a prompt template, making the join function available
+func ParsePromptTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("prompt").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// jsonModeInstruction replaces the section header instructions when JSON mode is used
+const jsonModeInstruction = `Instead of section headers, respond with a single JSON object of this shape:
+{"titles": ["title 1", "title 2", ...], "show_notes": ["show note"]}
+Each title is one string without a list number prefix. The show note keeps the exact format above, with line breaks as \n.`
+
+// parseJSONContentResponse unmarshals a JSON mode response into title candidates and show notes
+func parseJSONContentResponse(responseText string, numTitles int, logger *logrus.Logger) ([]string, []string, error) {
+	var candidates model.ContentCandidates
+	if err := json.Unmarshal([]byte(responseText), &candidates); err != nil {
+		return nil, nil, err
+	}
+	if len(candidates.Titles) == 0 && len(candidates.ShowNotes) == 0 {
+		return nil, nil, fmt.Errorf("response contains no titles or show notes")
+	}
+
+	// Normalize titles the same way as in text mode, in case the model numbered them anyway
+	titles := parseTitleCandidates(strings.Join(candidates.Titles, "\n"))
+	if len(titles) > numTitles {
+		titles = titles[:numTitles]
+	} else if len(titles) < numTitles {
+		logger.Warnf("Requested %d title candidates but the model returned %d", numTitles, len(titles))
+	}
+
+	var showNotes []string
+	for _, note := range candidates.ShowNotes {
+		if note = strings.TrimSpace(note); note != "" {
+			showNotes = append(showNotes, note)
+		}
+	}
+	return titles, showNotes, nil
+}
+
+// parseContentResponse splits a model response into title candidates and show notes
+func parseContentResponse(responseText string, numTitles int, logger *logrus.Logger) ([]string, []string) {
+	// Split the response into title and show note sections
+	titleSection := ""
+	showNoteSection := ""
+
+	// Extract title section
+	titleStart := strings.Index(responseText, "[TITLE]")
+	showNoteStart := strings.Index(responseText, "[SHOW NOTE]")
+
+	if titleStart >= 0 && showNoteStart > titleStart {
+		titleSection = strings.TrimSpace(responseText[titleStart+len("[TITLE]") : showNoteStart])
+	}
+
+	// Extract show note section
+	if showNoteStart >= 0 {
+		showNoteSection = strings.TrimSpace(responseText[showNoteStart+len("[SHOW NOTE]"):])
+	}
+
+	// If we couldn't find the sections, try to parse the whole response
+	if titleSection == "" && showNoteStart < 0 {
+		// Try to extract the first line as title
+		lines := strings.Split(responseText, "\n")
+		if len(lines) > 0 {
+			titleSection = lines[0]
+			showNoteSection = strings.Join(lines[1:], "\n")
+		}
+	}
+
+	// Split the title section into individual candidates
+	titles := parseTitleCandidates(titleSection)
+	if len(titles) > numTitles {
+		titles = titles[:numTitles]
+	} else if len(titles) < numTitles {
+		logger.Warnf("Requested %d title candidates but the model returned %d", numTitles, len(titles))
+	}
+	showNotes := []string{showNoteSection}
+
+	return titles, showNotes
+}
+
+// parseTitleCandidates splits a title section into candidates, stripping list markers.
+// Numbered markers are only stripped when they match the candidate's position so that
+// an episode number prefix such as "42." is preserved.
+func parseTitleCandidates(section string) []string {
+	var titles []string
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if m := titleMarkerPattern.FindStringSubmatch(line); m != nil {
+			if m[1] == "" {
+				line = line[len(m[0]):]
+			} else if n, err := strconv.Atoi(m[1]); err == nil && n == len(titles)+1 {
+				line = line[len(m[0]):]
+			}
+		}
+
+		line = strings.TrimSpace(line)
+		if line != "" {
+			titles = append(titles, line)
+		}
+	}
+	return titles
+}
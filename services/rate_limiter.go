@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RequestLimiter caps the rate of OpenAI calls across services running concurrently,
+// such as the episodes of a batch. Calls are spaced evenly, so a cap of 60 requests per
+// minute allows one call per second.
+type RequestLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRequestLimiter creates a new RequestLimiter instance allowing requestsPerMinute calls per minute.
+// It returns nil, which never waits, if requestsPerMinute is 0 or less.
+func NewRequestLimiter(requestsPerMinute int) *RequestLimiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+	return &RequestLimiter{interval: time.Minute / time.Duration(requestsPerMinute)}
+}
+
+// Wait blocks until the next call is allowed or ctx is cancelled
+func (l *RequestLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	// Reserve the next free slot
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
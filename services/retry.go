@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultMaxRetries is the default number of retries for retryable OpenAI errors
+const DefaultMaxRetries = 3
+
+// retryBaseDelay is the initial backoff delay before the first retry
+const retryBaseDelay = time.Second
+
+// createChatCompletion calls the OpenAI chat completion API, retrying rate-limit and
+// 5xx errors with exponential backoff plus jitter
+func (s *AIService) createChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	if err := s.waitRateLimit(ctx); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	defer s.markRequestDone()
+
+	for attempt := 0; ; attempt++ {
+		// Retries count against the shared rate limit too
+		if attempt > 0 {
+			if err := s.Limiter.Wait(ctx); err != nil {
+				return openai.ChatCompletionResponse{}, err
+			}
+		}
+		resp, err := s.client.CreateChatCompletion(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		if attempt >= s.MaxRetries || !isRetryableOpenAIError(err) {
+			return resp, err
+		}
+
+		delay := backoffDelay(attempt)
+		s.logger.Warnf("OpenAI request failed (%v), retrying in %s (attempt %d/%d)", err, delay.Round(time.Millisecond), attempt+2, s.MaxRetries+1)
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// waitRateLimit waits until RateLimitDelay has passed since the previous API call finished,
+// and then for the shared Limiter. The first call of a run is never delayed by RateLimitDelay.
+func (s *AIService) waitRateLimit(ctx context.Context) error {
+	if s.RateLimitDelay <= 0 || s.lastRequestAt.IsZero() {
+		return s.Limiter.Wait(ctx)
+	}
+
+	delay := s.RateLimitDelay - time.Since(s.lastRequestAt)
+	if delay <= 0 {
+		return s.Limiter.Wait(ctx)
+	}
+	s.logger.Infof("Waiting %s before the next OpenAI call (--rate-limit-delay)", delay.Round(time.Millisecond))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return s.Limiter.Wait(ctx)
+	}
+}
+
+// markRequestDone records when the latest API call finished for waitRateLimit
+func (s *AIService) markRequestDone() {
+	s.lastRequestAt = time.Now()
+}
+
+// isRetryableOpenAIError reports whether an OpenAI error is a rate limit or server error
+func isRetryableOpenAIError(err error) bool {
+	statusCode := 0
+
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		statusCode = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		statusCode = reqErr.HTTPStatusCode
+	}
+
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// backoffDelay returns the exponential backoff delay for an attempt with up to 50% jitter
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay * time.Duration(1<<uint(attempt))
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// spotifyIDPattern matches a Spotify ID, which is 22 base-62 characters
+	spotifyIDPattern = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+	// applePodcastIDPattern matches an Apple Podcasts ID in a path segment such as "id1234567890"
+	applePodcastIDPattern = regexp.MustCompile(`^id(\d{5,12})$`)
+	// appleNumericIDPattern matches an Apple Podcasts ID given as a query parameter
+	appleNumericIDPattern = regexp.MustCompile(`^\d{5,12}$`)
+)
+
+// ParseSpotifyShowID extracts the show ID from a Spotify show URL or URI, e.g.
+// https://open.spotify.com/show/<id>, https://open.spotify.com/intl-ja/show/<id>?si=...,
+// https://open.spotify.com/embed/show/<id> or spotify:show:<id>
+func ParseSpotifyShowID(showURL string) (string, error) {
+	showURL = strings.TrimSpace(showURL)
+	if rest, ok := strings.CutPrefix(showURL, "spotify:show:"); ok {
+		if !spotifyIDPattern.MatchString(rest) {
+			return "", fmt.Errorf("invalid Spotify show ID in %q", showURL)
+		}
+		return rest, nil
+	}
+
+	u, err := parseShowURL(showURL)
+	if err != nil {
+		return "", err
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	if host == "spotify.link" || host == "spoti.fi" {
+		return "", fmt.Errorf("%q is a Spotify short link; open it and use the open.spotify.com/show/... URL it redirects to", showURL)
+	}
+	if host != "open.spotify.com" && host != "spotify.com" {
+		return "", fmt.Errorf("%q is not a Spotify URL, expected https://open.spotify.com/show/...", showURL)
+	}
+
+	// The show ID follows "show", after an optional locale (intl-ja) or embed prefix
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments {
+		switch segment {
+		case "show":
+			if i+1 < len(segments) && spotifyIDPattern.MatchString(segments[i+1]) {
+				return segments[i+1], nil
+			}
+			return "", fmt.Errorf("invalid Spotify show ID in %q", showURL)
+		case "episode":
+			return "", fmt.Errorf("%q is a Spotify episode URL, expected the URL of the show", showURL)
+		}
+	}
+	return "", fmt.Errorf("could not find a show ID in Spotify URL %q, expected https://open.spotify.com/show/...", showURL)
+}
+
+// ParseApplePodcastID extracts the podcast ID from an Apple Podcasts URL, e.g.
+// https://podcasts.apple.com/jp/podcast/<name>/id<digits>, https://podcasts.apple.com/podcast/id<digits>?i=...
+// or https://itunes.apple.com/WebObjects/MZStore.woa/wa/viewPodcast?id=<digits>
+func ParseApplePodcastID(showURL string) (string, error) {
+	u, err := parseShowURL(strings.TrimSpace(showURL))
+	if err != nil {
+		return "", err
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	if host != "podcasts.apple.com" && host != "itunes.apple.com" {
+		return "", fmt.Errorf("%q is not an Apple Podcasts URL, expected https://podcasts.apple.com/.../id<digits>", showURL)
+	}
+
+	for _, segment := range strings.Split(u.Path, "/") {
+		if m := applePodcastIDPattern.FindStringSubmatch(segment); m != nil {
+			return m[1], nil
+		}
+	}
+	// Legacy iTunes links pass the ID as a query parameter; "i" is the episode, not the show
+	if id := u.Query().Get("id"); appleNumericIDPattern.MatchString(id) {
+		return id, nil
+	}
+	return "", fmt.Errorf("could not find a podcast ID (id<digits>) in Apple Podcasts URL %q", showURL)
+}
+
+// parseShowURL parses a pasted show URL, which may lack the scheme
+func parseShowURL(showURL string) (*url.URL, error) {
+	if showURL == "" {
+		return nil, fmt.Errorf("show URL is empty")
+	}
+	if !strings.Contains(showURL, "://") {
+		showURL = "https://" + showURL
+	}
+	u, err := url.Parse(showURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid show URL %q: %w", showURL, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import "testing"
+
+const testSpotifyShowID = "4rOoJ6Egrf8K2IrywzwOMk"
+
+func TestParseSpotifyShowID(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{name: "show url", url: "https://open.spotify.com/show/" + testSpotifyShowID, want: testSpotifyShowID},
+		{name: "locale path", url: "https://open.spotify.com/intl-ja/show/" + testSpotifyShowID, want: testSpotifyShowID},
+		{name: "share query", url: "https://open.spotify.com/show/" + testSpotifyShowID + "?si=a1b2c3d4e5f6", want: testSpotifyShowID},
+		{name: "locale path and share query", url: "https://open.spotify.com/intl-ja/show/" + testSpotifyShowID + "?si=a1b2c3d4e5f6", want: testSpotifyShowID},
+		{name: "embed url", url: "https://open.spotify.com/embed/show/" + testSpotifyShowID, want: testSpotifyShowID},
+		{name: "trailing slash", url: "https://open.spotify.com/show/" + testSpotifyShowID + "/", want: testSpotifyShowID},
+		{name: "scheme-less url", url: "open.spotify.com/show/" + testSpotifyShowID, want: testSpotifyShowID},
+		{name: "surrounding spaces", url: "  https://open.spotify.com/show/" + testSpotifyShowID + "\n", want: testSpotifyShowID},
+		{name: "uri", url: "spotify:show:" + testSpotifyShowID, want: testSpotifyShowID},
+		{name: "uri with invalid id", url: "spotify:show:abc", wantErr: true},
+		{name: "spotify.link short link", url: "https://spotify.link/AbCdEfGhIjK", wantErr: true},
+		{name: "spoti.fi short link", url: "spoti.fi/3xyz", wantErr: true},
+		{name: "episode url", url: "https://open.spotify.com/episode/" + testSpotifyShowID, wantErr: true},
+		{name: "invalid show id", url: "https://open.spotify.com/show/tooshort", wantErr: true},
+		{name: "no show in path", url: "https://open.spotify.com/artist/" + testSpotifyShowID, wantErr: true},
+		{name: "other host", url: "https://podcasts.apple.com/show/" + testSpotifyShowID, wantErr: true},
+		{name: "empty", url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSpotifyShowID(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpotifyShowID(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSpotifyShowID(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseApplePodcastID(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{name: "podcast url", url: "https://podcasts.apple.com/podcast/momit-fm/id1234567890", want: "1234567890"},
+		{name: "locale path", url: "https://podcasts.apple.com/jp/podcast/momit-fm/id1234567890", want: "1234567890"},
+		{name: "encoded japanese name", url: "https://podcasts.apple.com/jp/podcast/%E3%83%A2%E3%83%9F%E3%83%83%E3%83%88/id1234567890", want: "1234567890"},
+		{name: "episode query is ignored", url: "https://podcasts.apple.com/jp/podcast/momit-fm/id1234567890?i=1000650000000", want: "1234567890"},
+		{name: "id without name", url: "https://podcasts.apple.com/podcast/id1234567890", want: "1234567890"},
+		{name: "legacy itunes query", url: "https://itunes.apple.com/WebObjects/MZStore.woa/wa/viewPodcast?id=1234567890", want: "1234567890"},
+		{name: "legacy itunes path", url: "https://itunes.apple.com/jp/podcast/momit-fm/id1234567890", want: "1234567890"},
+		{name: "scheme-less url", url: "podcasts.apple.com/jp/podcast/momit-fm/id1234567890", want: "1234567890"},
+		{name: "www prefix", url: "https://www.podcasts.apple.com/podcast/id1234567890", want: "1234567890"},
+		{name: "episode query without show id", url: "https://podcasts.apple.com/jp/podcast/momit-fm?i=1000650000000", wantErr: true},
+		{name: "non-numeric legacy id", url: "https://itunes.apple.com/WebObjects/MZStore.woa/wa/viewPodcast?id=momit", wantErr: true},
+		{name: "other host", url: "https://open.spotify.com/podcast/id1234567890", wantErr: true},
+		{name: "empty", url: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseApplePodcastID(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseApplePodcastID(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseApplePodcastID(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ShowNoteCreditsHeader is the header of the credits section at the end of a show note
+const ShowNoteCreditsHeader = "✨🎧 Credits"
+
+var (
+	// ctaDividerPattern matches a dotted line wrapping the CTA block, e.g. "………"
+	ctaDividerPattern = regexp.MustCompile(`^(?:…{3,}|\.{6,}|・{3,})$`)
+	// showNoteListMarkerPattern matches a markdown-style list marker such as "- ", "* ", "• " or "・"
+	showNoteListMarkerPattern = regexp.MustCompile(`^(?:[-*•]\s+|・)`)
+	// boldPattern matches markdown bold text such as **headline**
+	boldPattern = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	// escapedURLPattern matches URLs in HTML-escaped text
+	escapedURLPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+)
+
+// IsCTADivider reports whether a show note line is one of the dotted lines wrapping the CTA block
+func IsCTADivider(line string) bool {
+	return ctaDividerPattern.MatchString(strings.TrimSpace(line))
+}
+
+// IsShowNoteBullet reports whether a show note line is a bullet point: "[emoji] headline: description"
+func IsShowNoteBullet(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, ShowNoteCreditsHeader) {
+		return false
+	}
+	first := []rune(line)[0]
+	if !unicode.Is(unicode.So, first) && !unicode.Is(unicode.Sk, first) {
+		return false
+	}
+	return strings.ContainsAny(line, ":：")
+}
+
+// IsShowNoteListItem reports whether a show note line is rendered as a list item:
+// an emoji bullet or a line starting with a markdown-style list marker
+func IsShowNoteListItem(line string) bool {
+	return IsShowNoteBullet(line) || showNoteListMarkerPattern.MatchString(strings.TrimSpace(line))
+}
+
+// TrimShowNoteListMarker removes the markdown-style list marker at the start of a show note line
+func TrimShowNoteListMarker(line string) string {
+	return showNoteListMarkerPattern.ReplaceAllString(strings.TrimSpace(line), "")
+}
+
+// FormatShowNoteHTML converts a plain-text show note into HTML for Art19's WYSIWYG description field.
+// Emoji and markdown-style bullets become a <ul> list, other lines become <p> paragraphs split at
+// blank lines, **bold** text and URLs are marked up, and emoji are kept as they are. The CTA block
+// is kept together with its dotted lines in one paragraph, and the credits section gets a bold
+// header with its lines kept as written.
+func FormatShowNoteHTML(note string) string {
+	note = strings.ReplaceAll(strings.ReplaceAll(note, "\r\n", "\n"), "\r", "\n")
+
+	var sb strings.Builder
+	var paragraph, items []string
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			sb.WriteString("<p>" + strings.Join(paragraph, "<br>") + "</p>")
+			paragraph = nil
+		}
+	}
+	flushList := func() {
+		if len(items) > 0 {
+			sb.WriteString("<ul>")
+			for _, item := range items {
+				sb.WriteString("<li>" + item + "</li>")
+			}
+			sb.WriteString("</ul>")
+			items = nil
+		}
+	}
+
+	inCTA, inCredits := false, false
+	for _, line := range strings.Split(strings.TrimSpace(note), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case IsCTADivider(line):
+			// The dividers open and close a paragraph holding the whole CTA block
+			if !inCTA {
+				flushList()
+				flushParagraph()
+			}
+			paragraph = append(paragraph, formatShowNoteLine(line))
+			if inCTA {
+				flushParagraph()
+			}
+			inCTA = !inCTA
+		case inCTA:
+			if line != "" {
+				paragraph = append(paragraph, formatShowNoteLine(line))
+			}
+		case strings.HasPrefix(line, ShowNoteCreditsHeader):
+			flushList()
+			flushParagraph()
+			sb.WriteString("<p><strong>" + html.EscapeString(line) + "</strong></p>")
+			inCredits = true
+		case line == "":
+			flushList()
+			flushParagraph()
+		case !inCredits && IsShowNoteListItem(line):
+			flushParagraph()
+			items = append(items, formatShowNoteLine(TrimShowNoteListMarker(line)))
+		default:
+			flushList()
+			paragraph = append(paragraph, formatShowNoteLine(line))
+		}
+	}
+	flushList()
+	flushParagraph()
+
+	return sb.String()
+}
+
+// formatShowNoteLine escapes a show note line and marks up its **bold** text and URLs
+func formatShowNoteLine(line string) string {
+	escaped := html.EscapeString(line)
+	escaped = boldPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	return escapedURLPattern.ReplaceAllStringFunc(escaped, func(link string) string {
+		return `<a href="` + link + `">` + link + `</a>`
+	})
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// showNoteTimecodePattern matches a timecode in parentheses such as "(12:34)", "(1:02:03)" or "（12:34）",
+// with the spaces in front of it
+var showNoteTimecodePattern = regexp.MustCompile(`([ \t　]*)[(（]\s*(\d{1,2}(?::\d{1,2}){1,2})\s*[)）]`)
+
+// FormatTimestampedTranscript formats transcript segments as lines starting with their
+// start time, e.g. "[12:34] text", for prompts that ask for timestamped show notes
+func FormatTimestampedTranscript(segments []TranscriptSegment) string {
+	duration := TranscriptDuration(segments)
+
+	var sb strings.Builder
+	for _, segment := range segments {
+		text := strings.TrimSpace(strings.ReplaceAll(segment.Text, "\n", " "))
+		if text == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "[%s] %s\n", FormatShowNoteTimecode(segment.Start, duration), text)
+	}
+	return sb.String()
+}
+
+// TranscriptDuration returns the end time in seconds of the last transcript segment
+func TranscriptDuration(segments []TranscriptSegment) float64 {
+	duration := 0.0
+	for _, segment := range segments {
+		duration = math.Max(duration, segment.End)
+	}
+	return duration
+}
+
+// FormatShowNoteTimecode formats seconds as MM:SS, or as H:MM:SS in episodes that are an hour or longer
+func FormatShowNoteTimecode(seconds, duration float64) string {
+	total := int(seconds)
+	if duration >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
+
+// NormalizeShowNoteTimecodes rewrites the "(MM:SS)" timecodes of a show note in the format of
+// FormatShowNoteTimecode. Timecodes that are malformed or after the end of the episode are
+// removed and returned, so a listener never jumps to a point that doesn't exist.
+func NormalizeShowNoteTimecodes(showNote string, duration float64) (string, []string) {
+	var dropped []string
+	normalized := showNoteTimecodePattern.ReplaceAllStringFunc(showNote, func(match string) string {
+		groups := showNoteTimecodePattern.FindStringSubmatch(match)
+		seconds, ok := parseShowNoteTimecode(groups[2])
+		if !ok || seconds > duration {
+			dropped = append(dropped, groups[2])
+			return ""
+		}
+		return groups[1] + "(" + FormatShowNoteTimecode(seconds, duration) + ")"
+	})
+	return normalized, dropped
+}
+
+// CountShowNoteTimecodes returns the number of "(MM:SS)" timecodes in a show note
+func CountShowNoteTimecodes(showNote string) int {
+	return len(showNoteTimecodePattern.FindAllString(showNote, -1))
+}
+
+// parseShowNoteTimecode parses an MM:SS or H:MM:SS timecode into seconds, rejecting
+// minutes or seconds of 60 and more after the first field
+func parseShowNoteTimecode(timecode string) (float64, bool) {
+	fields := strings.Split(timecode, ":")
+	seconds := 0
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return float64(seconds), true
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// DefaultSNSRetries is the default number of retries for a failed SNS URL or feed request
+const DefaultSNSRetries = 2
+
+// DefaultSNSRequestTimeout is the default timeout of a single SNS URL or feed request
+const DefaultSNSRequestTimeout = 10 * time.Second
+
+// DefaultSNSUserAgent is the browser-like User-Agent sent with SNS requests, since Spotify and
+// Apple serve challenge pages to clients without one
+const DefaultSNSUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// ErrShowURLFallback is returned with the show or channel URL when the latest episode URL
+// could not be found, so callers can decide whether posting the show URL is acceptable
+var ErrShowURLFallback = errors.New("latest episode URL not found, falling back to the show URL")
+
+// fetchResponse is the status code and body of a completed HTTP request
+type fetchResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// fetch sends the request built by newRequest, retrying network errors, timeouts, 429 and 5xx
+// responses with backoff. Each attempt is limited to RequestTimeout. Other status codes are
+// returned to the caller without retrying.
+func (s *SNSService) fetch(ctx context.Context, name string, newRequest func(ctx context.Context) (*http.Request, error)) (*fetchResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := s.fetchOnce(ctx, newRequest)
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt >= s.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if err == nil {
+			err = fmt.Errorf("status code %d", resp.StatusCode)
+		}
+		delay := backoffDelay(attempt)
+		s.logger.Warnf("%s request failed (%v), retrying in %s (attempt %d/%d)", name, err, delay.Round(time.Millisecond), attempt+2, s.MaxRetries+1)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// fetchOnce makes a single request within RequestTimeout and reads the whole response body
+func (s *SNSService) fetchOnce(ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error)) (*fetchResponse, error) {
+	if s.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := newRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.UserAgent != "" {
+		req.Header.Set("User-Agent", s.UserAgent)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, fmt.Errorf("request to %s timed out: %w", req.URL.Host, err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// The transport decompresses gzip responses it asked for; also decode servers
+	// that compress without being asked
+	var reader io.Reader = resp.Body
+	if !resp.Uncompressed && resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress response: %w", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return &fetchResponse{StatusCode: resp.StatusCode, Body: body}, nil
+}
+
+// getRequest returns a request builder for a GET request to rawURL
+func getRequest(rawURL string) func(ctx context.Context) (*http.Request, error) {
+	return func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	}
+}
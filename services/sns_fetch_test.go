@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// gzipBytes compresses data with gzip
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchOnceSendsUserAgent(t *testing.T) {
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{name: "default browser user agent", userAgent: DefaultSNSUserAgent, want: DefaultSNSUserAgent},
+		{name: "custom user agent", userAgent: "aipodflow-test/1.0", want: "aipodflow-test/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewSNSService(newTestLogger())
+			service.UserAgent = tt.userAgent
+
+			resp, err := service.fetchOnce(context.Background(), getRequest(server.URL))
+			if err != nil {
+				t.Fatalf("fetchOnce() error = %v", err)
+			}
+			if string(resp.Body) != "ok" {
+				t.Errorf("body = %q, want %q", resp.Body, "ok")
+			}
+			if gotUserAgent != tt.want {
+				t.Errorf("User-Agent = %q, want %q", gotUserAgent, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchOnceDecodesGzip(t *testing.T) {
+	const feed = `<rss><channel><title>momit.fm</title></channel></rss>`
+	compressed := gzipBytes(t, feed)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Compress whatever the client asked for, like misconfigured feed hosts do
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write(compressed)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+	}{
+		// The transport asks for gzip and decompresses the response itself
+		{name: "compressed when asked"},
+		// The client doesn't accept gzip, so fetchOnce has to decompress the response
+		{name: "compressed without being asked", acceptEncoding: "identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewSNSService(newTestLogger())
+			newRequest := func(ctx context.Context) (*http.Request, error) {
+				req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+				if err == nil && tt.acceptEncoding != "" {
+					req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+				}
+				return req, err
+			}
+
+			resp, err := service.fetchOnce(context.Background(), newRequest)
+			if err != nil {
+				t.Fatalf("fetchOnce() error = %v", err)
+			}
+			if string(resp.Body) != feed {
+				t.Errorf("body = %q, want the decompressed feed %q", resp.Body, feed)
+			}
+		})
+	}
+}
+
+func TestFetchOnceInvalidGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	service := NewSNSService(newTestLogger())
+	newRequest := func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		if err == nil {
+			req.Header.Set("Accept-Encoding", "identity")
+		}
+		return req, err
+	}
+	if _, err := service.fetchOnce(context.Background(), newRequest); err == nil {
+		t.Fatal("fetchOnce() error = nil, want an error for a corrupt gzip body")
+	}
+}
+
+func TestFetchDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	service := NewSNSService(newTestLogger())
+	resp, err := service.fetch(context.Background(), "RSS feed", getRequest(server.URL))
+	if err != nil {
+		t.Fatalf("fetch() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if requests != 1 {
+		t.Errorf("server got %d requests, want 1 (404 is not retried)", requests)
+	}
+}
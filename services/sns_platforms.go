@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Platforms with their own post text variant
+const (
+	PlatformX        = "x"
+	PlatformMastodon = "mastodon"
+	PlatformBluesky  = "bluesky"
+	PlatformLinkedIn = "linkedin"
+)
+
+// SNSPlatforms lists the platforms accepted by CreatePostText
+var SNSPlatforms = []string{PlatformX, PlatformMastodon, PlatformBluesky, PlatformLinkedIn}
+
+// LinkedInMaxLength is the maximum length of a LinkedIn post in characters
+const LinkedInMaxLength = 3000
+
+// LinkedInLimit is the post length limit of LinkedIn, where URLs count in full
+var LinkedInLimit = SNSLimit{MaxLength: LinkedInMaxLength, Length: utf8.RuneCountInString}
+
+// DefaultLinkedInTemplate is the text/template used for LinkedIn posts: longer, with labeled links
+// and without the emoji pointers of the X template
+const DefaultLinkedInTemplate = `{{.Header}}
+
+{{.Title}}
+
+Listen on:
+- Spotify: {{.SpotifyURL}}
+- Apple Podcasts: {{.ApplePodcastURL}}
+{{if .YouTubeURL}}- YouTube: {{.YouTubeURL}}
+{{end}}{{if .HostHandle}}
+Host: {{.HostHandle}}
+{{end}}{{if .Hashtags}}
+{{.Hashtags}}
+{{end}}`
+
+// snsPlatformConfig is the built-in template and length limit of a platform
+type snsPlatformConfig struct {
+	template string
+	limit    SNSLimit
+}
+
+// snsPlatformConfigs holds the built-in configuration of every platform in SNSPlatforms.
+// X, Mastodon and Bluesky share the original post template and only differ in their limits.
+var snsPlatformConfigs = map[string]snsPlatformConfig{
+	PlatformX:        {template: DefaultSNSTemplate, limit: TwitterLimit},
+	PlatformMastodon: {template: DefaultSNSTemplate, limit: MastodonLimit},
+	PlatformBluesky:  {template: DefaultSNSTemplate, limit: BlueskyLimit},
+	PlatformLinkedIn: {template: DefaultLinkedInTemplate, limit: LinkedInLimit},
+}
+
+// PlatformLimit returns the post length limit of a platform
+func PlatformLimit(platform string) (SNSLimit, error) {
+	config, err := platformConfig(platform)
+	if err != nil {
+		return SNSLimit{}, err
+	}
+	return config.limit, nil
+}
+
+// platformConfig returns the built-in configuration of a platform
+func platformConfig(platform string) (snsPlatformConfig, error) {
+	config, ok := snsPlatformConfigs[platform]
+	if !ok {
+		return snsPlatformConfig{}, fmt.Errorf("unknown SNS platform %q, expected one of: %s", platform, strings.Join(SNSPlatforms, ", "))
+	}
+	return config, nil
+}
+
+// ParseSNSPlatforms parses a comma-separated list of platform names, e.g. "x,linkedin"
+func ParseSNSPlatforms(value string) ([]string, error) {
+	var platforms []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, err := platformConfig(name); err != nil {
+			return nil, err
+		}
+		seen[name] = true
+		platforms = append(platforms, name)
+	}
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("no SNS platform given, expected one of: %s", strings.Join(SNSPlatforms, ", "))
+	}
+	return platforms, nil
+}
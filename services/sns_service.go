@@ -1,14 +1,21 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"io"
+	"html"
 	"net/http"
+	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,177 +24,713 @@ import (
 type RSSFeed struct {
 	XMLName xml.Name `xml:"rss"`
 	Channel struct {
-		Title       string `xml:"title"`
-		Description string `xml:"description"`
-		Items       []struct {
-			Title       string `xml:"title"`
-			Link        string `xml:"link"`
-			Description string `xml:"description"`
-			PubDate     string `xml:"pubDate"`
-			GUID        string `xml:"guid"`
-		} `xml:"item"`
+		Title       string    `xml:"title"`
+		Description string    `xml:"description"`
+		Items       []RSSItem `xml:"item"`
 	} `xml:"channel"`
 }
 
+// RSSItem represents an episode in an RSS feed
+type RSSItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	GUID        string `xml:"guid"`
+}
+
+// pubDateLayouts are the date formats accepted for RSS pubDate values
+var pubDateLayouts = []string{time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"}
+
+// PublishedAt parses the item's pubDate
+func (item RSSItem) PublishedAt() (time.Time, error) {
+	pubDate := strings.TrimSpace(item.PubDate)
+	for _, layout := range pubDateLayouts {
+		if t, err := time.Parse(layout, pubDate); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized pubDate %q", item.PubDate)
+}
+
+// Default values of the SNS post template fields
+const (
+	DefaultSNSHeader     = "IT企業で働くママによる子育て×Tech Podcast momit.fm を配信しました🎙"
+	DefaultSNSHostHandle = "@m2vela"
+	DefaultSNSHashtags   = "#momitfm #子育テック"
+)
+
+// DefaultSNSTemplate is the text/template used for SNS posts when no custom template is set
+const DefaultSNSTemplate = `{{.Header}}{{if .HostHandle}} w/{{.HostHandle}}{{end}}
+—
+{{.Title}}
+
+👇Spotify
+{{.SpotifyURL}}
+
+👇Apple
+{{.ApplePodcastURL}}
+{{if .YouTubeURL}}
+👇YouTube
+{{.YouTubeURL}}
+{{end}}{{if .Hashtags}}
+{{.Hashtags}}
+{{end}}`
+
+// SNSPostData is the data available to an SNS post template
+type SNSPostData struct {
+	Title           string
+	SpotifyURL      string
+	ApplePodcastURL string
+	YouTubeURL      string // Empty when the show is not on YouTube
+	Header          string
+	Hashtags        string
+	HostHandle      string
+}
+
+// youtubeFeed represents the structure of a YouTube channel's Atom feed
+type youtubeFeed struct {
+	XMLName xml.Name `xml:"feed"`
+	Entries []struct {
+		Title     string `xml:"title"`
+		Published string `xml:"published"`
+		Link      struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
 // SNSService handles generating text for social media posts
 type SNSService struct {
 	client *http.Client
 	logger *logrus.Logger
+
+	// PostTemplate is the text/template rendered with SNSPostData for every platform
+	// without an entry in PlatformTemplates (default: the platform's built-in template)
+	PostTemplate string
+	// PlatformTemplates overrides the post template of individual platforms, keyed by platform name
+	PlatformTemplates map[string]string
+	// Header, Hashtags and HostHandle are passed to the post template
+	Header     string
+	Hashtags   string
+	HostHandle string
+
+	// SpotifyClientID and SpotifyClientSecret enable the Spotify Web API; the show page is scraped without them
+	SpotifyClientID     string
+	SpotifyClientSecret string
+	// SpotifyMarket is the market passed to the Spotify Web API (default: DefaultSpotifyMarket)
+	SpotifyMarket string
+
+	// MaxRetries is the number of retries for failed feed and episode URL requests
+	MaxRetries int
+	// RequestTimeout limits each feed and episode URL request attempt
+	RequestTimeout time.Duration
+	// UserAgent is sent with every request (default: DefaultSNSUserAgent)
+	UserAgent string
 }
 
 // NewSNSService creates a new SNSService instance
 func NewSNSService(logger *logrus.Logger) *SNSService {
 	return &SNSService{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		logger: logger,
+		client:         NewHTTPClient(0),
+		logger:         logger,
+		Header:         DefaultSNSHeader,
+		Hashtags:       DefaultSNSHashtags,
+		HostHandle:     DefaultSNSHostHandle,
+		MaxRetries:     DefaultSNSRetries,
+		RequestTimeout: DefaultSNSRequestTimeout,
+		UserAgent:      DefaultSNSUserAgent,
 	}
 }
 
 // GetLatestEpisodeTitle fetches the latest episode title from the RSS feed
 func (s *SNSService) GetLatestEpisodeTitle(ctx context.Context, rssURL string) (string, error) {
-	s.logger.Debugf("Fetching latest episode title from RSS feed: %s", rssURL)
-	
-	feed, err := s.fetchRSSFeed(rssURL)
+	latestEpisode, err := s.GetLatestEpisode(ctx, rssURL)
 	if err != nil {
 		return "", err
 	}
-	
-	if len(feed.Channel.Items) == 0 {
-		return "", fmt.Errorf("no episodes found in the RSS feed")
+	return latestEpisode.Title, nil
+}
+
+// GetLatestEpisode fetches the latest episode from the RSS feed
+func (s *SNSService) GetLatestEpisode(ctx context.Context, rssURL string) (*RSSItem, error) {
+	s.logger.Debugf("Fetching latest episode from RSS feed: %s", rssURL)
+
+	feed, err := s.fetchRSSFeed(ctx, rssURL)
+	if err != nil {
+		return nil, err
+	}
+
+	items := s.sortedItems(feed)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no episodes found in the RSS feed")
 	}
-	
-	latestEpisode := feed.Channel.Items[0]
+
+	latestEpisode := items[0]
 	s.logger.Debugf("Latest episode title: %s", latestEpisode.Title)
-	
-	return latestEpisode.Title, nil
+
+	return &latestEpisode, nil
+}
+
+// GetEpisodeTitleByDate fetches the title of the episode published on the given date.
+// Dates are compared in the location of the given time. When several episodes were published
+// that day, the latest one is returned.
+func (s *SNSService) GetEpisodeTitleByDate(ctx context.Context, rssURL string, date time.Time) (string, error) {
+	s.logger.Debugf("Fetching episode title for %s from RSS feed: %s", date.Format("2006-01-02"), rssURL)
+
+	feed, err := s.fetchRSSFeed(ctx, rssURL)
+	if err != nil {
+		return "", err
+	}
+
+	year, month, day := date.Date()
+	for _, item := range s.sortedItems(feed) {
+		publishedAt, err := item.PublishedAt()
+		if err != nil {
+			continue
+		}
+		y, m, d := publishedAt.In(date.Location()).Date()
+		if y == year && m == month && d == day {
+			s.logger.Debugf("Episode title: %s", item.Title)
+			return item.Title, nil
+		}
+	}
+
+	return "", fmt.Errorf("no episode published on %s found in the RSS feed", date.Format("2006-01-02"))
+}
+
+// leadingEpisodeNumberPattern matches a leading episode number such as "42." or "#42." in a title
+var leadingEpisodeNumberPattern = regexp.MustCompile(`^\s*#?(\d+)[.．]`)
+
+// GetNextEpisodeNumber derives the number of the next episode from the RSS feed.
+// It uses the leading number of the latest episode's title; if the title has no number,
+// it counts the episodes in the feed, treating base as the number of the first episode.
+func (s *SNSService) GetNextEpisodeNumber(ctx context.Context, rssURL string, base int) (int, error) {
+	s.logger.Debugf("Deriving next episode number from RSS feed: %s", rssURL)
+
+	feed, err := s.fetchRSSFeed(ctx, rssURL)
+	if err != nil {
+		return 0, err
+	}
+
+	items := s.sortedItems(feed)
+	if len(items) == 0 {
+		return base, nil
+	}
+
+	if m := leadingEpisodeNumberPattern.FindStringSubmatch(items[0].Title); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			return n + 1, nil
+		}
+	}
+
+	s.logger.Debugf("Latest title %q has no episode number, counting %d episodes from %d", items[0].Title, len(items), base)
+	return base + len(items), nil
+}
+
+// FindEpisode returns the feed item with the given leading episode number (if number is
+// greater than 0) or the same title, or nil if the feed has no such episode
+func (s *SNSService) FindEpisode(ctx context.Context, rssURL string, number int, title string) (*RSSItem, error) {
+	s.logger.Debugf("Looking for episode %d (%q) in RSS feed: %s", number, title, rssURL)
+
+	feed, err := s.fetchRSSFeed(ctx, rssURL)
+	if err != nil {
+		return nil, err
+	}
+
+	title = strings.TrimSpace(title)
+	for i, item := range feed.Channel.Items {
+		if title != "" && strings.TrimSpace(item.Title) == title {
+			return &feed.Channel.Items[i], nil
+		}
+		if number <= 0 {
+			continue
+		}
+		if m := leadingEpisodeNumberPattern.FindStringSubmatch(item.Title); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil && n == number {
+				return &feed.Channel.Items[i], nil
+			}
+		}
+	}
+
+	return nil, nil
 }
 
-// GetLatestSpotifyURL fetches the latest episode URL from Spotify
+// sortedItems returns the feed items ordered newest first by pubDate.
+// If any pubDate cannot be parsed, the feed's original order is kept.
+func (s *SNSService) sortedItems(feed *RSSFeed) []RSSItem {
+	type datedItem struct {
+		item        RSSItem
+		publishedAt time.Time
+	}
+
+	dated := make([]datedItem, len(feed.Channel.Items))
+	for i, item := range feed.Channel.Items {
+		publishedAt, err := item.PublishedAt()
+		if err != nil {
+			s.logger.Warnf("Could not parse the date of episode %q (%v), keeping the feed order", item.Title, err)
+			return feed.Channel.Items
+		}
+		dated[i] = datedItem{item: item, publishedAt: publishedAt}
+	}
+
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].publishedAt.After(dated[j].publishedAt)
+	})
+
+	items := make([]RSSItem, len(dated))
+	for i, d := range dated {
+		items[i] = d.item
+	}
+	return items
+}
+
+// Spotify Web API endpoints
+const (
+	spotifyTokenURL   = "https://accounts.spotify.com/api/token"
+	spotifyAPIBaseURL = "https://api.spotify.com/v1"
+)
+
+// DefaultSpotifyMarket is the market used for Spotify Web API requests when none is configured
+const DefaultSpotifyMarket = "JP"
+
+// GetLatestSpotifyURL fetches the latest episode URL from Spotify.
+// The Spotify Web API is used when client credentials are configured; otherwise the show page is scraped.
+// If the episode URL can't be found, the show URL is returned with an error wrapping ErrShowURLFallback.
 func (s *SNSService) GetLatestSpotifyURL(ctx context.Context, showURL string) (string, error) {
-	s.logger.Debugf("Fetching latest episode URL from Spotify: %s", showURL)
-	
-	// Make a request to the Spotify show page
-	req, err := http.NewRequestWithContext(ctx, "GET", showURL, nil)
+	showID, err := ParseSpotifyShowID(showURL)
+	if err != nil {
+		return showURL, fmt.Errorf("%w: %v", ErrShowURLFallback, err)
+	}
+
+	var episodeURL string
+	if s.SpotifyClientID != "" && s.SpotifyClientSecret != "" {
+		s.logger.Debug("Using the Spotify Web API to find the latest episode")
+		episodeURL, err = s.getLatestSpotifyURLFromAPI(ctx, showID)
+	} else {
+		s.logger.Debug("Spotify client credentials not configured, scraping the show page")
+		episodeURL, err = s.scrapeLatestSpotifyURL(ctx, "https://open.spotify.com/show/"+showID)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to create request for Spotify: %w", err)
+		return showURL, fmt.Errorf("%w: %v", ErrShowURLFallback, err)
 	}
-	
-	resp, err := s.client.Do(req)
+	return episodeURL, nil
+}
+
+// getLatestSpotifyURLFromAPI fetches the latest episode URL of a show using the Spotify Web API client-credentials flow
+func (s *SNSService) getLatestSpotifyURLFromAPI(ctx context.Context, showID string) (string, error) {
+	token, err := s.spotifyAccessToken(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Spotify show page: %w", err)
+		return "", err
+	}
+
+	market := s.SpotifyMarket
+	if market == "" {
+		market = DefaultSpotifyMarket
 	}
-	defer resp.Body.Close()
-	
-	body, err := io.ReadAll(resp.Body)
+
+	// Episodes are returned newest first
+	endpoint := fmt.Sprintf("%s/shows/%s/episodes?limit=1&market=%s", spotifyAPIBaseURL, showID, url.QueryEscape(market))
+	resp, err := s.fetch(ctx, "Spotify Web API", func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request for Spotify Web API: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read Spotify response: %w", err)
+		return "", fmt.Errorf("failed to call Spotify Web API: %w", err)
+	}
+	body := resp.Body
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Spotify Web API returned status code %d: %s", resp.StatusCode, string(body))
 	}
-	
+
+	// Parse the response
+	var result struct {
+		Items []struct {
+			ExternalURLs struct {
+				Spotify string `json:"spotify"`
+			} `json:"external_urls"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse Spotify Web API response: %w", err)
+	}
+
+	if len(result.Items) == 0 || result.Items[0].ExternalURLs.Spotify == "" {
+		return "", fmt.Errorf("Spotify show %s has no episodes", showID)
+	}
+
+	episodeURL := result.Items[0].ExternalURLs.Spotify
+	s.logger.Debugf("Latest Spotify episode URL: %s", episodeURL)
+	return episodeURL, nil
+}
+
+// spotifyAccessToken exchanges the client credentials for a Spotify Web API access token
+func (s *SNSService) spotifyAccessToken(ctx context.Context) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+
+	resp, err := s.fetch(ctx, "Spotify token", func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", spotifyTokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Spotify token request: %w", err)
+		}
+		req.SetBasicAuth(s.SpotifyClientID, s.SpotifyClientSecret)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to request Spotify access token: %w", err)
+	}
+	body := resp.Body
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Spotify token request returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("failed to parse Spotify token response: %w", err)
+	}
+
+	return token.AccessToken, nil
+}
+
+// scrapeLatestSpotifyURL finds the latest episode URL in the Spotify show page HTML
+func (s *SNSService) scrapeLatestSpotifyURL(ctx context.Context, showURL string) (string, error) {
+	s.logger.Debugf("Fetching latest episode URL from Spotify: %s", showURL)
+
+	// Make a request to the Spotify show page
+	resp, err := s.fetch(ctx, "Spotify show page", getRequest(showURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Spotify show page: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Spotify show page returned status code %d", resp.StatusCode)
+	}
+	body := resp.Body
+
 	// Find the latest episode URL using regex
 	// This is a simplified approach and might need adjustment based on actual HTML structure
 	re := regexp.MustCompile(`https://open\.spotify\.com/episode/[a-zA-Z0-9]+`)
 	matches := re.FindStringSubmatch(string(body))
-	
+
 	if len(matches) == 0 {
-		// If we can't find the episode link, return the show URL as fallback
-		s.logger.Warn("Could not find latest episode URL from Spotify, using show URL as fallback")
-		return showURL, nil
+		return "", fmt.Errorf("could not find an episode link on the Spotify show page")
 	}
-	
+
 	episodeURL := matches[0]
 	s.logger.Debugf("Latest Spotify episode URL: %s", episodeURL)
-	
+
 	return episodeURL, nil
 }
 
-// GetLatestApplePodcastURL fetches the latest episode URL from Apple Podcasts
+// itunesLookupURL is the iTunes Lookup API endpoint
+const itunesLookupURL = "https://itunes.apple.com/lookup"
+
+// GetLatestApplePodcastURL fetches the latest episode URL from Apple Podcasts using the iTunes Lookup API.
+// If the episode URL can't be found, the show URL is returned with an error wrapping ErrShowURLFallback.
 func (s *SNSService) GetLatestApplePodcastURL(ctx context.Context, showURL string) (string, error) {
-	s.logger.Debugf("Fetching latest episode URL from Apple Podcasts: %s", showURL)
-	
-	// Make a request to the Apple Podcasts show page
-	req, err := http.NewRequestWithContext(ctx, "GET", showURL, nil)
+	episodeURL, err := s.getLatestApplePodcastURL(ctx, showURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request for Apple Podcasts: %w", err)
+		return showURL, fmt.Errorf("%w: %v", ErrShowURLFallback, err)
 	}
-	
-	resp, err := s.client.Do(req)
+	return episodeURL, nil
+}
+
+// getLatestApplePodcastURL looks up the latest episode of an Apple Podcasts show
+func (s *SNSService) getLatestApplePodcastURL(ctx context.Context, showURL string) (string, error) {
+	s.logger.Debugf("Fetching latest episode URL from Apple Podcasts: %s", showURL)
+
+	// Extract the podcast ID from the show URL
+	podcastID, err := ParseApplePodcastID(showURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch Apple Podcasts show page: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
-	
-	body, err := io.ReadAll(resp.Body)
+
+	// Look up the show's episodes
+	lookupURL := fmt.Sprintf("%s?id=%s&entity=podcastEpisode&limit=10", itunesLookupURL, podcastID)
+	resp, err := s.fetch(ctx, "iTunes Lookup API", getRequest(lookupURL))
 	if err != nil {
-		return "", fmt.Errorf("failed to read Apple Podcasts response: %w", err)
+		return "", fmt.Errorf("failed to call iTunes Lookup API: %w", err)
 	}
-	
-	// Find the latest episode URL using regex
-	// This is a simplified approach and might need adjustment based on actual HTML structure
-	re := regexp.MustCompile(`https://podcasts\.apple\.com/us/podcast/[^"]+/id1589345170\?i=[0-9]+`)
-	matches := re.FindStringSubmatch(string(body))
-	
-	if len(matches) == 0 {
-		// If we can't find the episode link, return the show URL as fallback
-		s.logger.Warn("Could not find latest episode URL from Apple Podcasts, using show URL as fallback")
-		return showURL, nil
+	body := resp.Body
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("iTunes Lookup API returned status code %d", resp.StatusCode)
 	}
-	
-	episodeURL := matches[0]
+
+	// Parse the response
+	var result struct {
+		ResultCount int `json:"resultCount"`
+		Results     []struct {
+			WrapperType  string `json:"wrapperType"`
+			Kind         string `json:"kind"`
+			TrackViewURL string `json:"trackViewUrl"`
+			ReleaseDate  string `json:"releaseDate"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse iTunes Lookup API response: %w", err)
+	}
+
+	// The first result is the show itself; the rest are episodes
+	episodeURL := ""
+	latestRelease := ""
+	for _, item := range result.Results {
+		if item.WrapperType != "podcastEpisode" || item.TrackViewURL == "" {
+			continue
+		}
+		if item.ReleaseDate > latestRelease {
+			latestRelease = item.ReleaseDate
+			episodeURL = item.TrackViewURL
+		}
+	}
+
+	if result.ResultCount == 0 {
+		return "", fmt.Errorf("no podcast found on Apple Podcasts with ID %s", podcastID)
+	}
+	if episodeURL == "" {
+		return "", fmt.Errorf("podcast %s has no episodes on Apple Podcasts", podcastID)
+	}
+
 	s.logger.Debugf("Latest Apple Podcasts episode URL: %s", episodeURL)
-	
 	return episodeURL, nil
 }
 
-// CreateSNSPostText generates text for posting to social media platforms
-func (s *SNSService) CreateSNSPostText(title, spotifyURL, applePodcastURL string) string {
-	// Define the template parts
-	header := "IT企業で働くママによる子育て×Tech Podcast momit.fm を配信しました🎙 w/@m2vela"
-	divider := "—"
-	spotifyPrefix := "👇Spotify"
-	applePrefix := "👇Apple"
-	hashtags := "#momitfm #子育テック"
-	
-	// Build the template using strings.Join for better readability
-	parts := []string{
-		header,
-		divider,
-		title,
-		"",
-		spotifyPrefix,
-		spotifyURL,
-		"",
-		applePrefix,
-		applePodcastURL,
-		"",
-		hashtags,
-	}
-	
-	return strings.Join(parts, "\n")
+// youtubeFeedURL is the Atom feed of a YouTube channel's uploads
+const youtubeFeedURL = "https://www.youtube.com/feeds/videos.xml?channel_id="
+
+// youtubeChannelIDPattern matches the channel ID in a YouTube channel or feed URL
+var youtubeChannelIDPattern = regexp.MustCompile(`(?:/channel/|channel_id=)(UC[a-zA-Z0-9_-]+)`)
+
+// GetLatestYouTubeURL fetches the latest video URL of a YouTube channel from its feed.
+// The channel URL must contain the channel ID (https://www.youtube.com/channel/UC...).
+// If the video URL can't be found, the channel URL is returned with an error wrapping ErrShowURLFallback.
+func (s *SNSService) GetLatestYouTubeURL(ctx context.Context, channelURL string) (string, error) {
+	videoURL, err := s.getLatestYouTubeURL(ctx, channelURL)
+	if err != nil {
+		return channelURL, fmt.Errorf("%w: %v", ErrShowURLFallback, err)
+	}
+	return videoURL, nil
+}
+
+// getLatestYouTubeURL reads the latest video of a YouTube channel from its feed
+func (s *SNSService) getLatestYouTubeURL(ctx context.Context, channelURL string) (string, error) {
+	s.logger.Debugf("Fetching latest video URL from YouTube: %s", channelURL)
+
+	matches := youtubeChannelIDPattern.FindStringSubmatch(channelURL)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("could not find a channel ID (UC...) in YouTube URL: %s", channelURL)
+	}
+
+	feed, err := s.fetchYouTubeFeed(ctx, youtubeFeedURL+matches[1])
+	if err != nil {
+		return "", err
+	}
+
+	// Entries are listed newest first
+	if len(feed.Entries) == 0 || feed.Entries[0].Link.Href == "" {
+		return "", fmt.Errorf("no videos found in the YouTube feed")
+	}
+
+	videoURL := feed.Entries[0].Link.Href
+	s.logger.Debugf("Latest YouTube video URL: %s", videoURL)
+
+	return videoURL, nil
+}
+
+// TwitterMaxLength is the maximum weighted length of a post on Twitter/X
+const TwitterMaxLength = 280
+
+// MastodonMaxLength is the default maximum length of a Mastodon status
+const MastodonMaxLength = 500
+
+// snsURLLength is the length Twitter/X and Mastodon count for every URL, regardless of its actual length
+const snsURLLength = 23
+
+// urlPattern matches URLs in post text
+var urlPattern = regexp.MustCompile(`https?://[^\s]+`)
+
+// SNSLimit describes how a platform limits the length of a post
+type SNSLimit struct {
+	MaxLength int                   // Maximum length of a post, 0 for no limit
+	Length    func(text string) int // Counts the length of a text as the platform does
+}
+
+// Post length limits of the supported platforms
+var (
+	TwitterLimit  = SNSLimit{MaxLength: TwitterMaxLength, Length: TwitterTextLength}
+	MastodonLimit = SNSLimit{MaxLength: MastodonMaxLength, Length: MastodonTextLength}
+)
+
+// TwitterTextLength returns the length of a text as counted by Twitter/X.
+// URLs count as 23 characters and CJK and other wide characters count as 2.
+func TwitterTextLength(text string) int {
+	length := 0
+	for _, segment := range urlPattern.Split(text, -1) {
+		for _, r := range segment {
+			if r <= 0x10FF || (r >= 0x2000 && r <= 0x200D) || (r >= 0x2010 && r <= 0x201F) || (r >= 0x2032 && r <= 0x2037) {
+				length++
+			} else {
+				length += 2
+			}
+		}
+	}
+	return length + len(urlPattern.FindAllString(text, -1))*snsURLLength
+}
+
+// MastodonTextLength returns the length of a text as counted by Mastodon.
+// URLs count as 23 characters and every other character counts as 1.
+func MastodonTextLength(text string) int {
+	length := 0
+	for _, segment := range urlPattern.Split(text, -1) {
+		length += utf8.RuneCountInString(segment)
+	}
+	return length + len(urlPattern.FindAllString(text, -1))*snsURLLength
+}
+
+// CreatePostText generates the post text for a platform (see SNSPlatforms) with its template and length limit.
+// When the text is longer than the platform limit, the title is shortened with an ellipsis
+// so the URLs and hashtags stay intact. The second return value reports whether the title was truncated.
+func (s *SNSService) CreatePostText(platform, title, spotifyURL, applePodcastURL, youtubeURL string) (string, bool, error) {
+	config, err := platformConfig(platform)
+	if err != nil {
+		return "", false, err
+	}
+
+	templateText := s.PlatformTemplates[platform]
+	if templateText == "" {
+		templateText = s.PostTemplate
+	}
+	if templateText == "" {
+		templateText = config.template
+	}
+	tmpl, err := ParseSNSTemplate(templateText)
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", platform, err)
+	}
+
+	text, err := s.buildSNSPostText(tmpl, title, spotifyURL, applePodcastURL, youtubeURL)
+	if err != nil {
+		return "", false, err
+	}
+	limit := config.limit
+	if limit.MaxLength <= 0 || limit.Length(text) <= limit.MaxLength {
+		return text, false, nil
+	}
+
+	// Drop characters from the end of the title until the post fits
+	runes := []rune(title)
+	for n := len(runes) - 1; n >= 0; n-- {
+		shortened := strings.TrimSpace(string(runes[:n])) + "…"
+		text, err = s.buildSNSPostText(tmpl, shortened, spotifyURL, applePodcastURL, youtubeURL)
+		if err != nil {
+			return "", false, err
+		}
+		if limit.Length(text) <= limit.MaxLength {
+			break
+		}
+	}
+
+	return text, true, nil
+}
+
+// buildSNSPostText renders a post template with the given title and URLs
+func (s *SNSService) buildSNSPostText(tmpl *template.Template, title, spotifyURL, applePodcastURL, youtubeURL string) (string, error) {
+	data := SNSPostData{
+		Title:           title,
+		SpotifyURL:      spotifyURL,
+		ApplePodcastURL: applePodcastURL,
+		YouTubeURL:      youtubeURL,
+		Header:          s.Header,
+		Hashtags:        s.Hashtags,
+		HostHandle:      s.HostHandle,
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render SNS post template: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// ParseSNSTemplate parses an SNS post template
+func ParseSNSTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("sns").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SNS post template: %w", err)
+	}
+	return tmpl, nil
 }
 
 // fetchRSSFeed fetches and parses an RSS feed from the given URL
-func (s *SNSService) fetchRSSFeed(url string) (*RSSFeed, error) {
-	resp, err := s.client.Get(url)
+func (s *SNSService) fetchRSSFeed(ctx context.Context, url string) (*RSSFeed, error) {
+	resp, err := s.fetch(ctx, "RSS feed", getRequest(url))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch RSS feed: %w", err)
 	}
-	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to fetch RSS feed, status code: %d", resp.StatusCode)
 	}
-	
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read RSS feed: %w", err)
-	}
-	
+
+	// Accept HTML entities such as &nbsp; that feeds use without declaring them
 	var feed RSSFeed
-	if err := xml.Unmarshal(body, &feed); err != nil {
+	decoder := xml.NewDecoder(bytes.NewReader(resp.Body))
+	decoder.Entity = xml.HTMLEntity
+	if err := decoder.Decode(&feed); err != nil {
 		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
 	}
-	
+
+	for i := range feed.Channel.Items {
+		item := &feed.Channel.Items[i]
+		item.Title = decodeRSSText(item.Title)
+		item.Description = plainRSSText(item.Description)
+	}
+
+	return &feed, nil
+}
+
+// cdataPattern matches a CDATA section left in feed text, e.g. by feeds that wrap it twice or escape it
+var cdataPattern = regexp.MustCompile(`(?s)<!\[CDATA\[(.*?)\]\]>`)
+
+// decodeRSSText removes CDATA wrappers and decodes the HTML entities left in a feed text,
+// such as the &amp; of a title that was escaped twice or inside a CDATA section
+func decodeRSSText(text string) string {
+	text = cdataPattern.ReplaceAllString(text, "$1")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+// plainRSSText decodes a feed text like decodeRSSText and strips its HTML tags,
+// collapsing the whitespace left behind
+func plainRSSText(text string) string {
+	text = cdataPattern.ReplaceAllString(text, "$1")
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// fetchYouTubeFeed fetches and parses a YouTube channel feed from the given URL
+func (s *SNSService) fetchYouTubeFeed(ctx context.Context, url string) (*youtubeFeed, error) {
+	resp, err := s.fetch(ctx, "YouTube feed", getRequest(url))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch YouTube feed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch YouTube feed, status code: %d", resp.StatusCode)
+	}
+
+	var feed youtubeFeed
+	if err := xml.Unmarshal(resp.Body, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse YouTube feed: %w", err)
+	}
+
 	return &feed, nil
 }
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveFeed returns a server that responds with the given RSS feed
+func serveFeed(t *testing.T, feed string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		_, _ = w.Write([]byte(feed))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// rssWithItem returns an RSS feed with a single item
+func rssWithItem(item string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>momit.fm</title><description>Podcast</description>
+<item>` + item + `<pubDate>Mon, 02 Jun 2025 06:00:00 +0900</pubDate></item>
+</channel></rss>`
+}
+
+func TestFetchRSSFeedDecodesText(t *testing.T) {
+	tests := []struct {
+		name            string
+		item            string
+		wantTitle       string
+		wantDescription string
+	}{
+		{
+			name:      "plain title",
+			item:      `<title>42. AIと子育て</title>`,
+			wantTitle: "42. AIと子育て",
+		},
+		{
+			name:      "cdata title",
+			item:      `<title><![CDATA[42. Tech & 子育て <Live>]]></title>`,
+			wantTitle: "42. Tech & 子育て <Live>",
+		},
+		{
+			name:      "entities in title",
+			item:      `<title>Q&amp;A: Mom&#39;s &quot;tech&quot; stack</title>`,
+			wantTitle: `Q&A: Mom's "tech" stack`,
+		},
+		{
+			name:      "entities inside cdata title",
+			item:      `<title><![CDATA[Q&amp;A: Mom&#39;s tech]]></title>`,
+			wantTitle: "Q&A: Mom's tech",
+		},
+		{
+			name:      "escaped twice",
+			item:      `<title>Q&amp;amp;A &amp;#39;42&amp;#39;</title>`,
+			wantTitle: "Q&A '42'",
+		},
+		{
+			name:      "escaped cdata section",
+			item:      `<title>&lt;![CDATA[42. Tech &amp; 子育て]]&gt;</title>`,
+			wantTitle: "42. Tech & 子育て",
+		},
+		{
+			name:      "undeclared html entity",
+			item:      `<title>42.&nbsp;夜泣き&hellip;</title>`,
+			wantTitle: "42. 夜泣き…",
+		},
+		{
+			name:            "html description in cdata",
+			item:            `<title>42</title><description><![CDATA[<p>今回は&amp;<b>夜泣き</b>の話</p>` + "\n" + `<ul><li>Mom&#39;s app</li></ul>]]></description>`,
+			wantTitle:       "42",
+			wantDescription: "今回は& 夜泣き の話 Mom's app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serveFeed(t, rssWithItem(tt.item))
+			service := NewSNSService(newTestLogger())
+
+			feed, err := service.fetchRSSFeed(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("fetchRSSFeed() error = %v", err)
+			}
+			if len(feed.Channel.Items) != 1 {
+				t.Fatalf("fetchRSSFeed() has %d items, want 1", len(feed.Channel.Items))
+			}
+			item := feed.Channel.Items[0]
+			if item.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", item.Title, tt.wantTitle)
+			}
+			if item.Description != tt.wantDescription {
+				t.Errorf("Description = %q, want %q", item.Description, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestFetchRSSFeedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "not found", status: http.StatusNotFound, body: "not found"},
+		{name: "invalid xml", status: http.StatusOK, body: "<rss><channel><title>broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			service := NewSNSService(newTestLogger())
+			if _, err := service.fetchRSSFeed(context.Background(), server.URL); err == nil {
+				t.Error("fetchRSSFeed() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestGetLatestEpisodeTitleDecodesNewestItem(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>momit.fm</title>
+<item><title><![CDATA[41. Older &amp; wiser]]></title><pubDate>Mon, 26 May 2025 06:00:00 +0900</pubDate></item>
+<item><title><![CDATA[42. Tech &amp; 子育て]]></title><pubDate>Mon, 02 Jun 2025 06:00:00 +0900</pubDate></item>
+</channel></rss>`
+	server := serveFeed(t, feed)
+	service := NewSNSService(newTestLogger())
+
+	title, err := service.GetLatestEpisodeTitle(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("GetLatestEpisodeTitle() error = %v", err)
+	}
+	if title != "42. Tech & 子育て" {
+		t.Errorf("GetLatestEpisodeTitle() = %q, want %q", title, "42. Tech & 子育て")
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// completeContent calls the chat completion API, streaming the response to opts.StreamTo when set
+func (s *AIService) completeContent(ctx context.Context, req openai.ChatCompletionRequest, opts GenerateOptions) (openai.ChatCompletionResponse, error) {
+	if opts.StreamTo == nil {
+		return s.createChatCompletion(ctx, req)
+	}
+	return s.createChatCompletionStream(ctx, req, opts.StreamTo)
+}
+
+// createChatCompletionStream streams a chat completion, writing content tokens to w as they arrive,
+// and returns the assembled response with its token usage. Opening the stream is retried like
+// createChatCompletion; errors after tokens have been written are returned as is.
+func (s *AIService) createChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest, w io.Writer) (openai.ChatCompletionResponse, error) {
+	if err := s.waitRateLimit(ctx); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	defer s.markRequestDone()
+
+	req.Stream = true
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	var stream *openai.ChatCompletionStream
+	for attempt := 0; ; attempt++ {
+		// Retries count against the shared rate limit too
+		if attempt > 0 {
+			if err := s.Limiter.Wait(ctx); err != nil {
+				return openai.ChatCompletionResponse{}, err
+			}
+		}
+		var err error
+		stream, err = s.client.CreateChatCompletionStream(ctx, req)
+		if err == nil {
+			break
+		}
+
+		if attempt >= s.MaxRetries || !isRetryableOpenAIError(err) {
+			return openai.ChatCompletionResponse{}, err
+		}
+
+		delay := backoffDelay(attempt)
+		s.logger.Warnf("OpenAI request failed (%v), retrying in %s (attempt %d/%d)", err, delay.Round(time.Millisecond), attempt+2, s.MaxRetries+1)
+		select {
+		case <-ctx.Done():
+			return openai.ChatCompletionResponse{}, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	defer stream.Close()
+
+	resp := openai.ChatCompletionResponse{Model: req.Model}
+	var content strings.Builder
+	var toolCalls []openai.ToolCall
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintln(w)
+			return resp, fmt.Errorf("stream interrupted: %w", err)
+		}
+
+		if chunk.Usage != nil {
+			resp.Usage = *chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				content.WriteString(choice.Delta.Content)
+				fmt.Fprint(w, choice.Delta.Content)
+			}
+			// Tool call arguments arrive in pieces, keyed by the index of the call
+			for _, call := range choice.Delta.ToolCalls {
+				index := 0
+				if call.Index != nil {
+					index = *call.Index
+				}
+				for len(toolCalls) <= index {
+					toolCalls = append(toolCalls, openai.ToolCall{Type: openai.ToolTypeFunction})
+				}
+				if call.ID != "" {
+					toolCalls[index].ID = call.ID
+				}
+				if call.Function.Name != "" {
+					toolCalls[index].Function.Name = call.Function.Name
+				}
+				toolCalls[index].Function.Arguments += call.Function.Arguments
+				fmt.Fprint(w, call.Function.Arguments)
+			}
+		}
+	}
+	fmt.Fprintln(w)
+
+	resp.Choices = []openai.ChatCompletionChoice{{
+		Message: openai.ChatCompletionMessage{
+			Role:      openai.ChatMessageRoleAssistant,
+			Content:   content.String(),
+			ToolCalls: toolCalls,
+		},
+	}}
+	return resp, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultSummarySentences is the default length of an episode summary in sentences
+const DefaultSummarySentences = 3
+
+// summaryResponseTokens is the maximum number of tokens requested for an episode summary
+const summaryResponseTokens = 500
+
+// summarySystemPrompt asks for plain prose that fits podcast directory listings
+const summarySystemPrompt = "You write short episode descriptions for podcast directories. Write plain prose in the transcript's language: no title, no headings, no bullet points, no emojis, no hashtags and no URLs. Describe what the episode covers without addressing the listener."
+
+// listMarkerPattern matches bullet and numbered list markers at the start of a line
+var listMarkerPattern = regexp.MustCompile(`(?m)^\s*(?:[-*•・]|\d+[.)])\s+`)
+
+// Summarize writes a plain summary of at most maxSentences sentences for directory listings.
+// Long transcripts are summarized chunk by chunk first, like for content generation.
+func (s *AIService) Summarize(ctx context.Context, transcript string, maxSentences int) (string, error) {
+	if maxSentences <= 0 {
+		maxSentences = DefaultSummarySentences
+	}
+	s.logger.Infof("Summarizing the episode in up to %d sentences...", maxSentences)
+
+	// Use the full transcript, or chunk summaries if it is too long for a single prompt
+	fullTranscript, err := s.prepareTranscript(ctx, transcript, GenerateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: summarySystemPrompt,
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: fmt.Sprintf("Summarize this podcast episode in %d sentences or fewer:\n\n%s", maxSentences, fullTranscript),
+			},
+		},
+		Temperature: 0.3,
+		MaxTokens:   summaryResponseTokens,
+	}
+
+	resp, err := s.createChatCompletion(ctx, req)
+	if err != nil {
+		s.logger.Errorf("OpenAI API error: %v", err)
+		return "", fmt.Errorf("failed to summarize transcript: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty response from OpenAI")
+	}
+
+	summary := cleanSummary(resp.Choices[0].Message.Content, maxSentences)
+	if summary == "" {
+		return "", fmt.Errorf("OpenAI returned an empty summary")
+	}
+
+	s.logger.Info("Generated summary successfully")
+	return summary, nil
+}
+
+// cleanSummary turns a response into a single paragraph of prose: list markers, emojis
+// (including joiners and variation selectors) and line breaks are removed and the text
+// is cut to maxSentences sentences
+func cleanSummary(text string, maxSentences int) string {
+	text = listMarkerPattern.ReplaceAllString(text, "")
+	text = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.So, r) || r == '\u200d' || r == '\ufe0f' {
+			return -1
+		}
+		return r
+	}, text)
+	text = strings.Join(strings.Fields(text), " ")
+
+	var sentences []string
+	for _, sentence := range splitSentences(text) {
+		sentence = strings.TrimSpace(sentence)
+		if sentence == "" {
+			continue
+		}
+		sentences = append(sentences, sentence)
+		if len(sentences) == maxSentences {
+			break
+		}
+	}
+
+	// Sentences in languages written with spaces are joined with one
+	var sb strings.Builder
+	for i, sentence := range sentences {
+		if i > 0 && sentence[0] < 0x80 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(sentence)
+	}
+	return sb.String()
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// tempDirPrefix is the name prefix of the temporary directories created by NewTempDir
+const tempDirPrefix = "aipodflow-"
+
+// StaleTempDirAge is the age after which a temporary directory left behind by a killed run
+// is removed by the next NewTempDir call with the same name
+const StaleTempDirAge = 24 * time.Hour
+
+// TempDir is a temporary directory for the intermediate files of one operation, such as
+// audio chunks. Its files are removed by Cleanup, or as soon as the context is cancelled,
+// so they don't pile up in the system temporary directory when a run fails or is interrupted.
+type TempDir struct {
+	path   string
+	logger *logrus.Logger
+
+	mu    sync.Mutex
+	files map[string]bool
+
+	once     sync.Once
+	stopWait func() bool
+}
+
+// NewTempDir creates a new TempDir instance named aipodflow-<name>-* in the system temporary
+// directory. It also removes directories of the same name that earlier runs left behind.
+// Callers must call Cleanup when they are done, typically with defer.
+func NewTempDir(ctx context.Context, name string, logger *logrus.Logger) (*TempDir, error) {
+	removeStaleTempDirs(name, logger)
+
+	path, err := os.MkdirTemp("", tempDirPrefix+name+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	d := &TempDir{
+		path:   path,
+		logger: logger,
+		files:  make(map[string]bool),
+	}
+	d.stopWait = context.AfterFunc(ctx, d.Cleanup)
+	return d, nil
+}
+
+// Path returns the directory path
+func (d *TempDir) Path() string {
+	return d.path
+}
+
+// Join returns the path of a file named name in the directory and tracks it,
+// for files written by other programs such as ffmpeg
+func (d *TempDir) Join(name string) string {
+	path := filepath.Join(d.path, name)
+	d.mu.Lock()
+	d.files[path] = true
+	d.mu.Unlock()
+	return path
+}
+
+// Create creates a new file in the directory like os.CreateTemp and tracks it
+func (d *TempDir) Create(pattern string) (*os.File, error) {
+	file, err := os.CreateTemp(d.path, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	d.mu.Lock()
+	d.files[file.Name()] = true
+	d.mu.Unlock()
+	return file, nil
+}
+
+// Remove deletes a file of the directory before Cleanup, e.g. an audio chunk that has been
+// transcribed, to free its disk space early. Removing a file that is already gone is not an error.
+func (d *TempDir) Remove(path string) error {
+	d.mu.Lock()
+	delete(d.files, path)
+	d.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove temporary file: %w", err)
+	}
+	return nil
+}
+
+// Files returns the paths of the tracked files that have not been removed
+func (d *TempDir) Files() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files := make([]string, 0, len(d.files))
+	for path := range d.files {
+		files = append(files, path)
+	}
+	return files
+}
+
+// Cleanup removes the directory with all of its files. It is safe to call more than once
+// and from several goroutines.
+func (d *TempDir) Cleanup() {
+	d.once.Do(func() {
+		if d.stopWait != nil {
+			d.stopWait()
+		}
+		if err := os.RemoveAll(d.path); err != nil {
+			d.logger.Warnf("Failed to remove temporary directory %s: %v", d.path, err)
+			return
+		}
+		d.logger.Debugf("Removed temporary directory %s", d.path)
+	})
+}
+
+// removeStaleTempDirs removes the temporary directories named aipodflow-<name>-* that are
+// older than StaleTempDirAge, left behind by runs that were killed before cleaning up
+func removeStaleTempDirs(name string, logger *logrus.Logger) {
+	paths, err := filepath.Glob(filepath.Join(os.TempDir(), tempDirPrefix+name+"-*"))
+	if err != nil {
+		return
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() || time.Since(info.ModTime()) < StaleTempDirAge {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			logger.Debugf("Failed to remove stale temporary directory %s: %v", path, err)
+			continue
+		}
+		logger.Debugf("Removed stale temporary directory %s", path)
+	}
+}
@@ -0,0 +1,21 @@
+package services
+
+import "context"
+
+// Transcription backends supported by the transcribe command
+const (
+	TranscriptionBackendOpenAI = "openai"
+	TranscriptionBackendLocal  = "local"
+)
+
+// Transcriber turns an audio file into a transcript
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (string, error)
+	TranscribeWithTimestamps(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error)
+}
+
+// Make sure the transcription services implement Transcriber
+var (
+	_ Transcriber = (*TranscriptionService)(nil)
+	_ Transcriber = (*LocalWhisperService)(nil)
+)
@@ -6,80 +6,301 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 )
 
+// transcriptionURL is the OpenAI audio transcription endpoint
+const transcriptionURL = "https://api.openai.com/v1/audio/transcriptions"
+
+// whisperModel is the OpenAI model used for transcription
+const whisperModel = "whisper-1"
+
+// WhisperPromptMaxTokens is the number of prompt tokens Whisper takes into account
+const WhisperPromptMaxTokens = 224
+
 // TranscriptionService handles audio transcription using OpenAI's Whisper API
 type TranscriptionService struct {
-	apiKey string
-	logger *logrus.Logger
+	apiKey          string
+	client          *http.Client
+	logger          *logrus.Logger
+	baseURL         string
+	azureDeployment string
+
+	// ChunkSize is the size of the chunks that files over WhisperMaxFileSize are split into
+	// (default: DefaultChunkSize)
+	ChunkSize int64
 }
 
 // NewTranscriptionService creates a new TranscriptionService instance
 func NewTranscriptionService(apiKey string, logger *logrus.Logger) *TranscriptionService {
 	return &TranscriptionService{
-		apiKey: apiKey,
-		logger: logger,
+		apiKey:    apiKey,
+		client:    NewHTTPClient(slowRequestTimeout(DefaultTranscriptionTimeout)),
+		logger:    logger,
+		ChunkSize: DefaultChunkSize,
+	}
+}
+
+// SetBaseURL sends requests to an OpenAI-compatible endpoint or an Azure OpenAI resource
+// instead of the public OpenAI API; on Azure, azureDeployment is the Whisper deployment
+// (default: whisper-1). An empty base URL keeps the public API.
+func (s *TranscriptionService) SetBaseURL(baseURL, azureDeployment string) {
+	s.baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	s.azureDeployment = azureDeployment
+}
+
+// endpoint returns the transcription URL and the name and value of the authentication header
+func (s *TranscriptionService) endpoint() (string, string, string) {
+	switch {
+	case s.baseURL == "":
+		return transcriptionURL, "Authorization", "Bearer " + s.apiKey
+	case IsAzureOpenAIURL(s.baseURL):
+		deployment := s.azureDeployment
+		if deployment == "" {
+			deployment = whisperModel
+		}
+		return fmt.Sprintf("%s/openai/deployments/%s/audio/transcriptions?api-version=%s", s.baseURL, url.PathEscape(deployment), AzureOpenAIAPIVersion),
+			"api-key", s.apiKey
+	default:
+		return s.baseURL + "/audio/transcriptions", "Authorization", "Bearer " + s.apiKey
+	}
+}
+
+// TranscribeOptions holds optional parameters for a transcription request
+type TranscribeOptions struct {
+	Language string // ISO-639-1 language of the audio (e.g. "ja"), empty to auto-detect
+	Prompt   string // Glossary of names and terms to bias recognition, truncated to WhisperPromptMaxTokens
+}
+
+// BuildWhisperPrompt joins a comma-separated glossary and the terms of a vocabulary file
+// (one per line or comma-separated) into a prompt. Terms that would exceed
+// WhisperPromptMaxTokens are dropped; the second return value reports whether any were.
+func BuildWhisperPrompt(glossary, vocabulary string) (string, bool) {
+	// Drop comment lines of the vocabulary file
+	lines := []string{glossary}
+	for _, line := range strings.Split(vocabulary, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range strings.FieldsFunc(strings.Join(lines, "\n"), func(r rune) bool {
+		return r == ',' || r == '、' || r == '\n'
+	}) {
+		term = strings.TrimSpace(term)
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+
+	prompt := ""
+	for i, term := range terms {
+		next := term
+		if prompt != "" {
+			next = prompt + ", " + term
+		}
+		if estimateWhisperTokens(next) > WhisperPromptMaxTokens {
+			return prompt, i < len(terms)
+		}
+		prompt = next
 	}
+	return prompt, false
+}
+
+// estimateWhisperTokens conservatively estimates the token count of a Whisper prompt:
+// about four characters per token for ASCII text and one token per character otherwise
+func estimateWhisperTokens(text string) int {
+	ascii := 0
+	tokens := 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			tokens++
+		}
+	}
+	return tokens + (ascii+3)/4
+}
+
+// TranscriptSegment is a timed segment of a transcript
+type TranscriptSegment struct {
+	Start float64 `json:"start"` // Start time in seconds
+	End   float64 `json:"end"`   // End time in seconds
+	Text  string  `json:"text"`
+}
+
+// TranscriptionResult is a transcription with timestamped segments
+type TranscriptionResult struct {
+	Text     string              `json:"text"`
+	Language string              `json:"language,omitempty"`
+	Duration float64             `json:"duration,omitempty"`
+	Segments []TranscriptSegment `json:"segments"`
 }
 
 // Transcribe processes an audio file and returns the transcription
-func (s *TranscriptionService) Transcribe(ctx context.Context, audioPath string) (string, error) {
+func (s *TranscriptionService) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (string, error) {
 	s.logger.Infof("Starting transcription for: %s", audioPath)
 
+	// Split files that are too large for a single request
+	if size, err := s.oversizedAudio(audioPath); err != nil {
+		return "", err
+	} else if size > 0 {
+		result, err := s.transcribeChunked(ctx, audioPath, size, opts)
+		if err != nil {
+			return "", err
+		}
+		s.logger.Infof("Transcription completed successfully")
+		return result.Text, nil
+	}
+
+	body, err := s.requestTranscription(ctx, audioPath, opts, "json")
+	if err != nil {
+		return "", err
+	}
+
+	// Parse the response
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	s.logger.Infof("Transcription completed successfully")
+	return result.Text, nil
+}
+
+// TranscribeWithTimestamps processes an audio file and returns the transcription with timed segments
+func (s *TranscriptionService) TranscribeWithTimestamps(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	s.logger.Infof("Starting timestamped transcription for: %s", audioPath)
+
+	// Split files that are too large for a single request
+	if size, err := s.oversizedAudio(audioPath); err != nil {
+		return nil, err
+	} else if size > 0 {
+		result, err := s.transcribeChunked(ctx, audioPath, size, opts)
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Infof("Transcription completed successfully with %d segments", len(result.Segments))
+		return result, nil
+	}
+
+	body, err := s.requestTranscription(ctx, audioPath, opts, "verbose_json")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var result TranscriptionResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	s.logger.Infof("Transcription completed successfully with %d segments", len(result.Segments))
+	return &result, nil
+}
+
+// oversizedAudio returns the size of the audio file if it is over the Whisper upload limit, or 0
+func (s *TranscriptionService) oversizedAudio(audioPath string) (int64, error) {
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	if info.Size() <= WhisperMaxFileSize {
+		return 0, nil
+	}
+
+	s.logger.Infof("Audio file is %.1f MB, over Whisper's %d MB limit; transcribing it in chunks", float64(info.Size())/(1<<20), WhisperMaxFileSize>>20)
+	return info.Size(), nil
+}
+
+// requestTranscription uploads the audio file to the transcription endpoint and returns the raw response body
+func (s *TranscriptionService) requestTranscription(ctx context.Context, audioPath string, opts TranscribeOptions, responseFormat string) ([]byte, error) {
 	// Open the audio file
 	file, err := os.Open(audioPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open audio file: %w", err)
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
 	}
 	defer file.Close()
 
-	// Create a buffer to store the file
+	// Build the multipart form with the audio file and model fields
 	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, file); err != nil {
-		return "", fmt.Errorf("failed to read audio file: %w", err)
+	writer := multipart.NewWriter(&buf)
+
+	part, err := writer.CreateFormFile("file", filepath.Base(audioPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("failed to read audio file: %w", err)
+	}
+
+	if err := writer.WriteField("model", whisperModel); err != nil {
+		return nil, fmt.Errorf("failed to write model field: %w", err)
+	}
+	if err := writer.WriteField("response_format", responseFormat); err != nil {
+		return nil, fmt.Errorf("failed to write response format field: %w", err)
+	}
+	if opts.Language != "" {
+		if err := writer.WriteField("language", opts.Language); err != nil {
+			return nil, fmt.Errorf("failed to write language field: %w", err)
+		}
+	}
+	if opts.Prompt != "" {
+		if err := writer.WriteField("prompt", opts.Prompt); err != nil {
+			return nil, fmt.Errorf("failed to write prompt field: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize multipart body: %w", err)
 	}
 
 	// Create the request
+	endpoint, authHeader, authValue := s.endpoint()
 	req, err := http.NewRequestWithContext(
 		ctx,
 		"POST",
-		"https://api.openai.com/v1/audio/transcriptions",
+		endpoint,
 		&buf,
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", "Bearer "+s.apiKey)
-	req.Header.Set("Content-Type", "multipart/form-data")
+	req.Header.Set(authHeader, authValue)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read the response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse the response
-	var result struct {
-		Text string `json:"text"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("transcription failed with status code %d: %s", resp.StatusCode, string(body))
 	}
 
-	s.logger.Infof("Transcription completed successfully")
-	return result.Text, nil
+	return body, nil
 }
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newTestLogger returns a logger that discards its output
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestRequestTranscriptionMultipartBody(t *testing.T) {
+	audio := []byte("fake mp3 data")
+	audioPath := filepath.Join(t.TempDir(), "episode.mp3")
+	if err := os.WriteFile(audioPath, audio, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotPath, gotAuth string
+	var gotFields map[string]string
+	var gotFile []byte
+	var gotFileName string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("request is not a multipart form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		gotFields = make(map[string]string)
+		for name, values := range r.MultipartForm.Value {
+			gotFields[name] = values[0]
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("missing file field: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		gotFileName = header.Filename
+		gotFile, _ = io.ReadAll(file)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"text":"こんにちは"}`)
+	}))
+	defer server.Close()
+
+	service := NewTranscriptionService("test-key", newTestLogger())
+	service.SetBaseURL(server.URL, "")
+	text, err := service.Transcribe(context.Background(), audioPath, TranscribeOptions{Language: "ja", Prompt: "momit.fm"})
+	if err != nil {
+		t.Fatalf("Transcribe() error = %v", err)
+	}
+
+	if text != "こんにちは" {
+		t.Errorf("Transcribe() = %q, want %q", text, "こんにちは")
+	}
+	if gotPath != "/audio/transcriptions" {
+		t.Errorf("request path = %q, want /audio/transcriptions", gotPath)
+	}
+	if gotAuth != "Bearer test-key" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer test-key")
+	}
+	if gotFileName != "episode.mp3" || string(gotFile) != string(audio) {
+		t.Errorf("file field = %q (%q), want episode.mp3 (%q)", gotFileName, gotFile, audio)
+	}
+	wantFields := map[string]string{
+		"model":           whisperModel,
+		"response_format": "json",
+		"language":        "ja",
+		"prompt":          "momit.fm",
+	}
+	for name, want := range wantFields {
+		if gotFields[name] != want {
+			t.Errorf("field %s = %q, want %q", name, gotFields[name], want)
+		}
+	}
+}
+
+func TestRequestTranscriptionErrorStatus(t *testing.T) {
+	audioPath := filepath.Join(t.TempDir(), "episode.mp3")
+	if err := os.WriteFile(audioPath, []byte("fake mp3 data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"invalid model"}}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	service := NewTranscriptionService("test-key", newTestLogger())
+	service.SetBaseURL(server.URL, "")
+	if _, err := service.Transcribe(context.Background(), audioPath, TranscribeOptions{}); err == nil {
+		t.Fatal("Transcribe() error = nil, want an error for status 400")
+	}
+}
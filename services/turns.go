@@ -0,0 +1,38 @@
+package services
+
+import "strings"
+
+// Turn is what one speaker said before the next speaker took over
+type Turn struct {
+	Speaker string `json:"speaker"` // Empty for text before the first speaker label
+	Text    string `json:"text"`
+}
+
+// FormatTurns renders speaker turns as a transcript with one "Speaker: text" paragraph per turn
+func FormatTurns(turns []Turn) string {
+	var sb strings.Builder
+	for _, turn := range turns {
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		if turn.Speaker != "" {
+			sb.WriteString(turn.Speaker)
+			sb.WriteString(": ")
+		}
+		sb.WriteString(turn.Text)
+	}
+	return sb.String()
+}
+
+// TurnSpeakers returns the distinct speakers of the turns in order of their first turn
+func TurnSpeakers(turns []Turn) []string {
+	seen := make(map[string]bool)
+	var speakers []string
+	for _, turn := range turns {
+		if turn.Speaker != "" && !seen[turn.Speaker] {
+			seen[turn.Speaker] = true
+			speakers = append(speakers, turn.Speaker)
+		}
+	}
+	return speakers
+}
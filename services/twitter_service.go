@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// tweetsURL is the X API v2 endpoint for creating tweets
+const tweetsURL = "https://api.twitter.com/2/tweets"
+
+// Twitter API v1.1 media endpoints, which X API v2 tweets reference by media ID
+const (
+	mediaUploadURL   = "https://upload.twitter.com/1.1/media/upload.json"
+	mediaMetadataURL = "https://upload.twitter.com/1.1/media/metadata/create.json"
+)
+
+// usersMeURL is the X API v2 endpoint that returns the authenticated user
+const usersMeURL = "https://api.twitter.com/2/users/me"
+
+// TwitterService handles posting to Twitter/X
+type TwitterService struct {
+	apiKey       string
+	apiSecret    string
+	accessToken  string
+	accessSecret string
+	client       *http.Client
+	logger       *logrus.Logger
+}
+
+// NewTwitterService creates a new TwitterService instance
+func NewTwitterService(apiKey, apiSecret, accessToken, accessSecret string, logger *logrus.Logger) *TwitterService {
+	return &TwitterService{
+		apiKey:       apiKey,
+		apiSecret:    apiSecret,
+		accessToken:  accessToken,
+		accessSecret: accessSecret,
+		client:       NewHTTPClient(0),
+		logger:       logger,
+	}
+}
+
+// TweetURL returns the public URL of a tweet
+func TweetURL(tweetID string) string {
+	return "https://x.com/i/web/status/" + tweetID
+}
+
+// PostTweet publishes a tweet, with the image attached if it is not nil, and returns its ID
+func (s *TwitterService) PostTweet(ctx context.Context, text string, image *ShareImage) (string, error) {
+	if s.apiKey == "" || s.apiSecret == "" || s.accessToken == "" || s.accessSecret == "" {
+		return "", fmt.Errorf("Twitter API credentials are not fully configured")
+	}
+
+	tweet := map[string]interface{}{"text": text}
+	if image != nil {
+		mediaID, err := s.uploadMedia(ctx, image)
+		if err != nil {
+			return "", err
+		}
+		tweet["media"] = map[string][]string{"media_ids": {mediaID}}
+	}
+
+	payload, err := json.Marshal(tweet)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tweet: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tweetsURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	authHeader, err := s.oauthHeader(req.Method, tweetsURL)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", authHeader)
+	req.Header.Set("Content-Type", "application/json")
+
+	s.logger.Debug("Posting tweet to X API")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to post tweet: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("X API returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse the response
+	var result struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.Data.ID == "" {
+		return "", fmt.Errorf("X API response did not include a tweet ID: %s", string(body))
+	}
+
+	return result.Data.ID, nil
+}
+
+// uploadMedia uploads an image and sets its alt text, returning the media ID
+func (s *TwitterService) uploadMedia(ctx context.Context, image *ShareImage) (string, error) {
+	body, contentType, err := image.multipartBody("media", nil)
+	if err != nil {
+		return "", err
+	}
+
+	s.logger.Debugf("Uploading image to X: %s", image.Path)
+	respBody, err := s.postSigned(ctx, mediaUploadURL, body, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	var media struct {
+		MediaID string `json:"media_id_string"`
+	}
+	if err := json.Unmarshal(respBody, &media); err != nil {
+		return "", fmt.Errorf("failed to parse media upload response: %w", err)
+	}
+	if media.MediaID == "" {
+		return "", fmt.Errorf("X media upload response did not include a media ID: %s", string(respBody))
+	}
+
+	// Alt text is optional, so a failure only loses the description
+	if image.AltText != "" {
+		metadata, err := json.Marshal(map[string]interface{}{
+			"media_id": media.MediaID,
+			"alt_text": map[string]string{"text": image.AltText},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal media metadata: %w", err)
+		}
+		if _, err := s.postSigned(ctx, mediaMetadataURL, bytes.NewReader(metadata), "application/json"); err != nil {
+			s.logger.Warnf("Failed to set the image alt text: %v", err)
+		}
+	}
+
+	return media.MediaID, nil
+}
+
+// postSigned POSTs a body with an OAuth 1.0a signature and returns the response body of a 2xx response
+func (s *TwitterService) postSigned(ctx context.Context, endpoint string, body io.Reader, contentType string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	authHeader, err := s.oauthHeader(req.Method, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", authHeader)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("X API returned status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// oauthHeader builds an OAuth 1.0a Authorization header for a request.
+// Request bodies are JSON or multipart, so only the OAuth parameters are part of the signature.
+func (s *TwitterService) oauthHeader(method, endpoint string) (string, error) {
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", fmt.Errorf("failed to generate OAuth nonce: %w", err)
+	}
+
+	params := map[string]string{
+		"oauth_consumer_key":     s.apiKey,
+		"oauth_nonce":            hex.EncodeToString(nonceBytes),
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_token":            s.accessToken,
+		"oauth_version":          "1.0",
+	}
+	params["oauth_signature"] = oauthSignature(method, endpoint, params, s.apiSecret, s.accessSecret)
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, oauthEscape(key), oauthEscape(params[key])))
+	}
+	return "OAuth " + strings.Join(parts, ", "), nil
+}
+
+// oauthSignature computes the HMAC-SHA1 signature of a request as defined by OAuth 1.0a
+func oauthSignature(method, endpoint string, params map[string]string, consumerSecret, tokenSecret string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, oauthEscape(key)+"="+oauthEscape(params[key]))
+	}
+
+	base := strings.ToUpper(method) + "&" + oauthEscape(endpoint) + "&" + oauthEscape(strings.Join(pairs, "&"))
+	signingKey := oauthEscape(consumerSecret) + "&" + oauthEscape(tokenSecret)
+
+	mac := hmac.New(sha1.New, []byte(signingKey))
+	mac.Write([]byte(base))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// oauthEscape percent-encodes a string as required by OAuth 1.0a (RFC 3986)
+func oauthEscape(s string) string {
+	// url.QueryEscape encodes spaces as "+" and leaves "~" alone; OAuth wants "%20"
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// VerifyCredentials checks the Twitter API credentials and returns the authenticated username
+func (s *TwitterService) VerifyCredentials(ctx context.Context) (string, error) {
+	if s.apiKey == "" || s.apiSecret == "" || s.accessToken == "" || s.accessSecret == "" {
+		return "", fmt.Errorf("Twitter API credentials are not fully configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", usersMeURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	authHeader, err := s.oauthHeader(req.Method, usersMeURL)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", authHeader)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call X API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("X API returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Data struct {
+			Username string `json:"username"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result.Data.Username, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+// Usage holds the token counts reported for one or more API calls
+type Usage struct {
+	Model            string `json:"model"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+	Calls            int    `json:"calls"`
+}
+
+// modelPrice is the price in USD per million tokens
+type modelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// modelPrices is the per-model price table used to estimate cost
+var modelPrices = map[string]modelPrice{
+	"gpt-4o":            {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.60},
+	"gpt-4.1":           {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini":      {Input: 0.40, Output: 1.60},
+	"claude-sonnet-4-5": {Input: 3.00, Output: 15.00},
+}
+
+// Add accumulates the counts of another usage into u
+func (u *Usage) Add(other Usage) {
+	if u.Model == "" {
+		u.Model = other.Model
+	}
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.Calls += other.Calls
+}
+
+// EstimateCost returns the estimated cost in USD, and false if the model's price is unknown
+func EstimateCost(u Usage) (float64, bool) {
+	price, ok := modelPrices[u.Model]
+	if !ok {
+		return 0, false
+	}
+	cost := float64(u.PromptTokens)*price.Input/1e6 + float64(u.CompletionTokens)*price.Output/1e6
+	return cost, true
+}
+
+// reportUsage passes the usage of a single API call to the OnUsage callback if one is set
+func reportUsage(opts GenerateOptions, model string, promptTokens, completionTokens int) {
+	if opts.OnUsage == nil {
+		return
+	}
+	opts.OnUsage(Usage{
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+		Calls:            1,
+	})
+}
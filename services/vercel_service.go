@@ -2,66 +2,210 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// DefaultVercelRetries is the default number of retries for a failed deploy hook call
+const DefaultVercelRetries = 3
+
+// DefaultVercelWaitTimeout is the default time to wait for a deployment to finish
+const DefaultVercelWaitTimeout = 10 * time.Minute
+
+// vercelAPIBaseURL is the base URL of the Vercel REST API
+const vercelAPIBaseURL = "https://api.vercel.com"
+
+// vercelPollInterval is the interval between deployment status checks
+const vercelPollInterval = 5 * time.Second
+
 // VercelService is a service responsible for Vercel-related operations
 type VercelService struct {
-	deployHookURL string
-	client        *http.Client
-	logger        *logrus.Logger
+	deployHookURLs []string
+	apiToken       string
+	projectID      string
+	client         *http.Client
+	logger         *logrus.Logger
+
+	// MaxRetries is the number of times a failed deploy hook call is retried
+	MaxRetries int
+	// WaitTimeout limits how long WaitForDeployment polls the deployment status
+	WaitTimeout time.Duration
+
+	// triggeredAt is when the deploy hook was last triggered successfully
+	triggeredAt time.Time
 }
 
-// NewVercelService creates a new VercelService instance
-func NewVercelService(deployHookURL string, logger *logrus.Logger) *VercelService {
+// NewVercelService creates a new VercelService instance that fires all of the given deploy hooks
+func NewVercelService(deployHookURLs []string, logger *logrus.Logger) *VercelService {
 	// Initialize HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Second * 30,
-	}
+	client := NewHTTPClient(0)
 
 	return &VercelService{
-		deployHookURL: deployHookURL,
-		client:        client,
-		logger:        logger,
+		deployHookURLs: deployHookURLs,
+		client:         client,
+		logger:         logger,
+		MaxRetries:     DefaultVercelRetries,
+		WaitTimeout:    DefaultVercelWaitTimeout,
 	}
 }
 
-// NewVercelServiceFromEnv creates a new VercelService instance using environment variables
+// NewVercelServiceFromEnv creates a new VercelService instance using environment variables.
+// VERCEL_DEPLOY_HOOK may hold several comma-separated hook URLs.
 func NewVercelServiceFromEnv(logger *logrus.Logger) *VercelService {
-	// Get deploy hook URL from environment variable
-	deployHookURL := os.Getenv("VERCEL_DEPLOY_HOOK")
+	// Get deploy hook URLs and optional API credentials from environment variables
+	deployHookURLs := ParseDeployHooks(os.Getenv("VERCEL_DEPLOY_HOOK"))
+	apiToken := os.Getenv("VERCEL_TOKEN")
+	projectID := os.Getenv("VERCEL_PROJECT_ID")
 
 	// Initialize HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Second * 30,
-	}
+	client := NewHTTPClient(0)
 
 	return &VercelService{
-		deployHookURL: deployHookURL,
-		client:        client,
-		logger:        logger,
+		deployHookURLs: deployHookURLs,
+		apiToken:       apiToken,
+		projectID:      projectID,
+		client:         client,
+		logger:         logger,
+		MaxRetries:     DefaultVercelRetries,
+		WaitTimeout:    DefaultVercelWaitTimeout,
+	}
+}
+
+// ParseDeployHooks splits a comma-separated list of deploy hook URLs, dropping empty entries
+func ParseDeployHooks(value string) []string {
+	var hooks []string
+	for _, hook := range strings.Split(value, ",") {
+		if hook = strings.TrimSpace(hook); hook != "" {
+			hooks = append(hooks, hook)
+		}
 	}
+	return hooks
 }
 
-// TriggerRedeploy triggers a redeployment of the website on Vercel
+// SetDeployHooks replaces the deploy hook URLs, e.g. with the ones given on the command line
+func (s *VercelService) SetDeployHooks(deployHookURLs []string) {
+	s.deployHookURLs = deployHookURLs
+}
+
+// TriggerRedeploy triggers a redeployment of the website on Vercel by firing every deploy hook.
+// A failing hook doesn't stop the others; the failures are reported together along with
+// how many of the hooks succeeded.
 func (s *VercelService) TriggerRedeploy(ctx context.Context) error {
-	if s.deployHookURL == "" {
+	if len(s.deployHookURLs) == 0 {
 		return fmt.Errorf("Vercel deploy hook URL is not configured")
 	}
 
-	s.logger.Info("Triggering Vercel redeployment...")
+	// A single hook keeps the original behavior and error messages
+	if len(s.deployHookURLs) == 1 {
+		return s.triggerHook(ctx, s.deployHookURLs[0], "")
+	}
+
+	var errs []error
+	succeeded := 0
+	for i, hookURL := range s.deployHookURLs {
+		label := fmt.Sprintf("hook %d/%d", i+1, len(s.deployHookURLs))
+		if err := s.triggerHook(ctx, hookURL, label); err != nil {
+			s.logger.Errorf("Vercel deploy %s failed: %v", label, err)
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+			continue
+		}
+		succeeded++
+	}
+
+	s.logger.Infof("%d of %d Vercel deploy hooks succeeded", succeeded, len(s.deployHookURLs))
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d Vercel deploy hooks failed: %w", len(errs), len(s.deployHookURLs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// triggerHook fires a single deploy hook. The label identifies the hook in logs when there are several.
+// Failed calls are retried with backoff. When a call may have reached Vercel before failing
+// (e.g. a timeout after the request was sent), it is only retried if the Vercel API confirms
+// that no deployment was created in the meantime, to avoid triggering duplicate builds.
+func (s *VercelService) triggerHook(ctx context.Context, hookURL, label string) error {
+	if label == "" {
+		s.logger.Info("Triggering Vercel redeployment...")
+	} else {
+		s.logger.Infof("Triggering Vercel redeployment (%s)...", label)
+	}
+	started := time.Now()
+
+	var lastErr error
+	for attempt := 0; attempt <= s.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := backoffDelay(attempt - 1)
+			s.logger.Warnf("Retrying Vercel deploy hook in %s (attempt %d/%d)", delay.Round(time.Millisecond), attempt+1, s.MaxRetries+1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		retryable, mayHaveSent, err := s.callDeployHook(ctx, hookURL)
+		if err == nil {
+			s.logger.Info("Vercel redeployment triggered successfully")
+			s.triggeredAt = started
+			return nil
+		}
+		lastErr = err
+
+		if !retryable {
+			s.logger.Debugf("Deploy hook error is not retryable: %v", err)
+			return err
+		}
+
+		if mayHaveSent {
+			// The hook may have queued a build even though we saw an error
+			if s.apiToken == "" {
+				s.logger.Warn("Deploy hook request may have reached Vercel and no VERCEL_TOKEN is set to verify it; not retrying to avoid a duplicate build")
+				return err
+			}
+			if len(s.deployHookURLs) > 1 {
+				// VERCEL_PROJECT_ID names one project, so it can't tell which hook created a deployment
+				s.logger.Warn("Deploy hook request may have reached Vercel and the deployment can't be verified with multiple hooks; not retrying to avoid a duplicate build")
+				return err
+			}
+
+			created, checkErr := s.hasDeploymentSince(ctx, started)
+			if checkErr != nil {
+				s.logger.Warnf("Could not check for a recent deployment (%v); not retrying to avoid a duplicate build", checkErr)
+				return err
+			}
+			if created {
+				s.logger.Info("A deployment was created after the hook call; treating the redeployment as triggered")
+				s.triggeredAt = started
+				return nil
+			}
+			s.logger.Info("No deployment was created after the hook call; safe to retry")
+		}
 
+		s.logger.Warnf("Vercel deploy hook failed: %v", err)
+	}
+
+	return fmt.Errorf("Vercel deploy hook failed after %d attempts: %w", s.MaxRetries+1, lastErr)
+}
+
+// callDeployHook makes a single deploy hook call. It reports whether the error is retryable
+// and whether the request may have been received by Vercel despite the error.
+func (s *VercelService) callDeployHook(ctx context.Context, hookURL string) (retryable bool, mayHaveSent bool, err error) {
 	// Create a POST request to the deploy hook URL
 	// Vercel deploy hooks expect an empty POST request with no body
-	req, err := http.NewRequestWithContext(ctx, "POST", s.deployHookURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", hookURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return false, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set the Content-Type header to application/json
@@ -70,7 +214,13 @@ func (s *VercelService) TriggerRedeploy(ctx context.Context) error {
 	// Send the request
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		if ctx.Err() != nil {
+			return false, true, fmt.Errorf("failed to send request: %w", err)
+		}
+		// Dial failures never reached the server; anything else may have
+		var opErr *net.OpError
+		sent := !(errors.As(err, &opErr) && opErr.Op == "dial")
+		return true, sent, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -82,9 +232,233 @@ func (s *VercelService) TriggerRedeploy(ctx context.Context) error {
 		// Read the response body for error details
 		body, _ := io.ReadAll(resp.Body)
 		s.logger.Debugf("Response body: %s", string(body))
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, resp.StatusCode >= 500, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return false, true, nil
+}
+
+// hasDeploymentSince reports whether a deployment was created at or after the given time
+func (s *VercelService) hasDeploymentSince(ctx context.Context, since time.Time) (bool, error) {
+	query := url.Values{}
+	query.Set("since", strconv.FormatInt(since.UnixMilli(), 10))
+	query.Set("limit", "1")
+	if s.projectID != "" {
+		query.Set("projectId", s.projectID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", vercelAPIBaseURL+"/v6/deployments?"+query.Encode(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Authorization", "Bearer "+s.apiToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to list deployments, status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Deployments []struct {
+			UID     string `json:"uid"`
+			Created int64  `json:"created"`
+		} `json:"deployments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to parse deployments response: %w", err)
+	}
+
+	for _, deployment := range result.Deployments {
+		if deployment.Created >= since.UnixMilli() {
+			s.logger.Debugf("Found deployment %s created after the hook call", deployment.UID)
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
-	s.logger.Info("Vercel redeployment triggered successfully")
+// vercelDeployment is a deployment as returned by the Vercel API
+type vercelDeployment struct {
+	UID          string `json:"uid"`
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	State        string `json:"state"`
+	ReadyState   string `json:"readyState"`
+	Created      int64  `json:"created"`
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// status returns the deployment state, which the list and detail endpoints name differently
+func (d *vercelDeployment) status() string {
+	if d.ReadyState != "" {
+		return d.ReadyState
+	}
+	return d.State
+}
+
+// WaitForDeployment polls the Vercel Deployments API until the latest deployment of the project
+// is READY or ERROR, or WaitTimeout expires. When TriggerRedeploy was called first, only deployments
+// created since the hook fired are considered, so a previous deployment is not mistaken for the new one.
+func (s *VercelService) WaitForDeployment(ctx context.Context, vercelToken, projectID string) error {
+	if vercelToken == "" || projectID == "" {
+		return fmt.Errorf("a Vercel API token and project ID are required to wait for the deployment")
+	}
+
+	if s.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.WaitTimeout)
+		defer cancel()
+	}
+
+	s.logger.Info("Waiting for the Vercel deployment to finish...")
+	lastState := ""
+	for {
+		deployment, err := s.latestDeployment(ctx, vercelToken, projectID, s.triggeredAt)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		if deployment != nil {
+			state := deployment.status()
+			if state != lastState {
+				s.logger.Infof("Vercel deployment %s is %s", deployment.UID, state)
+				lastState = state
+			}
+
+			switch state {
+			case "READY":
+				s.logger.Infof("Vercel deployment is ready: https://%s", deployment.URL)
+				return nil
+			case "ERROR", "CANCELED":
+				return fmt.Errorf("Vercel deployment %s failed: %s", deployment.UID, s.deploymentFailureReason(ctx, vercelToken, deployment))
+			}
+		} else if err == nil {
+			s.logger.Debug("No new Vercel deployment found yet")
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %s waiting for the Vercel deployment (last state: %s)", s.WaitTimeout, lastState)
+			}
+			return ctx.Err()
+		case <-time.After(vercelPollInterval):
+		}
+	}
+}
+
+// latestDeployment returns the most recent deployment of the project created at or after since,
+// or nil if there is none yet
+func (s *VercelService) latestDeployment(ctx context.Context, token, projectID string, since time.Time) (*vercelDeployment, error) {
+	query := url.Values{}
+	query.Set("projectId", projectID)
+	query.Set("limit", "1")
+	if !since.IsZero() {
+		query.Set("since", strconv.FormatInt(since.UnixMilli(), 10))
+	}
+
+	var result struct {
+		Deployments []vercelDeployment `json:"deployments"`
+	}
+	if err := s.getVercelAPI(ctx, token, "/v6/deployments?"+query.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	if len(result.Deployments) == 0 {
+		return nil, nil
+	}
+	return &result.Deployments[0], nil
+}
+
+// deploymentFailureReason fetches the error details of a failed deployment
+func (s *VercelService) deploymentFailureReason(ctx context.Context, token string, deployment *vercelDeployment) string {
+	var detail vercelDeployment
+	if err := s.getVercelAPI(ctx, token, "/v13/deployments/"+url.PathEscape(deployment.UID), &detail); err != nil {
+		s.logger.Warnf("Failed to fetch deployment details: %v", err)
+		detail = *deployment
+	}
+
+	switch {
+	case detail.ErrorMessage != "" && detail.ErrorCode != "":
+		return fmt.Sprintf("%s (%s)", detail.ErrorMessage, detail.ErrorCode)
+	case detail.ErrorMessage != "":
+		return detail.ErrorMessage
+	case detail.ErrorCode != "":
+		return detail.ErrorCode
+	default:
+		return "deployment state is " + deployment.status()
+	}
+}
+
+// getVercelAPI sends an authenticated GET request to the Vercel API and decodes the JSON response
+func (s *VercelService) getVercelAPI(ctx context.Context, token, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", vercelAPIBaseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Vercel API returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
 	return nil
 }
+
+// CheckDeployHooks checks that every deploy hook exists without triggering a deployment.
+// Hooks only accept POST, so a HEAD request answers 404 for unknown hooks and another
+// status for valid ones.
+func (s *VercelService) CheckDeployHooks(ctx context.Context) error {
+	if len(s.deployHookURLs) == 0 {
+		return fmt.Errorf("no Vercel deploy hook is configured")
+	}
+
+	var errs []error
+	for i, hookURL := range s.deployHookURLs {
+		req, err := http.NewRequestWithContext(ctx, "HEAD", hookURL, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hook %d: invalid URL: %w", i+1, err))
+			continue
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hook %d: failed to send request: %w", i+1, err))
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			errs = append(errs, fmt.Errorf("hook %d: Vercel doesn't know this deploy hook", i+1))
+		} else if resp.StatusCode >= 500 {
+			errs = append(errs, fmt.Errorf("hook %d: Vercel returned status code %d", i+1, resp.StatusCode))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckAPIAccess checks that VERCEL_TOKEN can read the deployments of VERCEL_PROJECT_ID
+func (s *VercelService) CheckAPIAccess(ctx context.Context) error {
+	if s.apiToken == "" || s.projectID == "" {
+		return fmt.Errorf("VERCEL_TOKEN and VERCEL_PROJECT_ID are required")
+	}
+	_, err := s.latestDeployment(ctx, s.apiToken, s.projectID, time.Time{})
+	return err
+}